@@ -0,0 +1,15 @@
+-- +goose Up
+-- Базовая миграция создаёт таблицу songs2, а код и следующие миграции работают с songs.
+-- Если songs уже создана вручную, ничего не меняем.
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF to_regclass('songs') IS NULL AND to_regclass('songs2') IS NOT NULL THEN
+        ALTER TABLE songs2 RENAME TO songs;
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Обратно не переименовываем: Down базовой миграции удаляет таблицу songs
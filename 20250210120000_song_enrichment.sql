@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE songs
+    ADD COLUMN enrichment_status VARCHAR(16) NOT NULL DEFAULT 'done',
+    ADD COLUMN enrichment_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN enrichment_error TEXT;
+
+CREATE INDEX idx_songs_enrichment_pending ON songs (id) WHERE enrichment_status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_songs_enrichment_pending;
+
+ALTER TABLE songs
+    DROP COLUMN enrichment_error,
+    DROP COLUMN enrichment_attempts,
+    DROP COLUMN enrichment_status;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Полнотекстовый поиск по названию, группе и тексту песни (русская и английская морфология)
+ALTER TABLE songs ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(song, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(song, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(group_name, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(group_name, '')), 'B') ||
+    setweight(to_tsvector('russian', replace(coalesce(text, ''), '\n', E'\n')), 'C') ||
+    setweight(to_tsvector('english', replace(coalesce(text, ''), '\n', E'\n')), 'C')
+) STORED;
+
+CREATE INDEX idx_songs_search_vector ON songs USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_songs_search_vector;
+
+ALTER TABLE songs DROP COLUMN search_vector;
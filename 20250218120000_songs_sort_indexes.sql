@@ -0,0 +1,10 @@
+-- +goose Up
+-- Индексы под поддерживаемые варианты сортировки для keyset-пагинации (ключ сортировки + id)
+CREATE INDEX idx_songs_group_name_id ON songs (group_name, id);
+CREATE INDEX idx_songs_song_id ON songs (song, id);
+CREATE INDEX idx_songs_release_date_id ON songs (release_date, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_songs_release_date_id;
+DROP INDEX IF EXISTS idx_songs_song_id;
+DROP INDEX IF EXISTS idx_songs_group_name_id;
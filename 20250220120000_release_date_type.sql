@@ -0,0 +1,92 @@
+-- +goose Up
+-- release_date становится DATE с точностью (год, месяц или день);
+-- для неполных дат хранится начало периода: 2006 -> 2006-01-01, 2006-07 -> 2006-07-01.
+-- Значения, которые не удалось разобрать, не теряются: они остаются в release_date_legacy
+-- +goose StatementBegin
+CREATE FUNCTION pg_temp.parse_release_date(value TEXT, OUT parsed DATE, OUT date_precision VARCHAR) AS $$
+BEGIN
+    value := btrim(value);
+    IF value ~ '^\d{2}\.\d{2}\.\d{4}$' THEN
+        parsed := to_date(value, 'DD.MM.YYYY');
+        date_precision := 'day';
+    ELSIF value ~ '^\d{2}\.\d{4}$' THEN
+        parsed := to_date(value, 'MM.YYYY');
+        date_precision := 'month';
+    ELSIF value ~ '^\d{4}-\d{2}-\d{2}($|T)' THEN
+        parsed := substr(value, 1, 10)::date;
+        date_precision := 'day';
+    ELSIF value ~ '^\d{4}-\d{2}$' THEN
+        parsed := to_date(value, 'YYYY-MM');
+        date_precision := 'month';
+    ELSIF value ~ '^\d{4}$' THEN
+        parsed := to_date(value, 'YYYY');
+        date_precision := 'year';
+    END IF;
+EXCEPTION WHEN others THEN
+    parsed := NULL;
+    date_precision := NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+ALTER TABLE songs
+    ADD COLUMN release_date_parsed DATE,
+    ADD COLUMN release_date_precision VARCHAR(5),
+    ADD COLUMN release_date_legacy VARCHAR(255);
+
+UPDATE songs s
+SET (release_date_parsed, release_date_precision) = (
+    SELECT p.parsed, p.date_precision FROM pg_temp.parse_release_date(s.release_date) p
+)
+WHERE s.release_date IS NOT NULL;
+
+UPDATE songs
+SET release_date_legacy = release_date
+WHERE release_date_parsed IS NULL AND btrim(release_date) <> '';
+
+-- +goose StatementBegin
+DO $$
+DECLARE
+    unparsed TEXT;
+BEGIN
+    SELECT string_agg(format('%s: %L', id, release_date_legacy), ', ' ORDER BY id)
+    INTO unparsed
+    FROM songs
+    WHERE release_date_legacy IS NOT NULL;
+    IF unparsed IS NOT NULL THEN
+        RAISE WARNING 'release dates kept in release_date_legacy (id: value): %', unparsed;
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE songs DROP COLUMN release_date;
+ALTER TABLE songs RENAME COLUMN release_date_parsed TO release_date;
+ALTER TABLE songs ADD CONSTRAINT songs_release_date_precision_check CHECK (
+    (release_date IS NULL AND release_date_precision IS NULL)
+    OR (release_date IS NOT NULL AND release_date_precision IN ('year', 'month', 'day'))
+);
+
+CREATE INDEX idx_songs_release_date_id ON songs (release_date, id);
+
+-- +goose Down
+ALTER TABLE songs ADD COLUMN release_date_text VARCHAR(255);
+
+UPDATE songs SET release_date_text = CASE release_date_precision
+    WHEN 'year' THEN to_char(release_date, 'YYYY')
+    WHEN 'month' THEN to_char(release_date, 'YYYY-MM')
+    ELSE to_char(release_date, 'DD.MM.YYYY')
+END
+WHERE release_date IS NOT NULL;
+
+-- Неразобранные значения возвращаются как были
+UPDATE songs SET release_date_text = release_date_legacy
+WHERE release_date IS NULL AND release_date_legacy IS NOT NULL;
+
+ALTER TABLE songs DROP CONSTRAINT songs_release_date_precision_check;
+ALTER TABLE songs DROP COLUMN release_date;
+ALTER TABLE songs DROP COLUMN release_date_precision;
+ALTER TABLE songs DROP COLUMN release_date_legacy;
+ALTER TABLE songs RENAME COLUMN release_date_text TO release_date;
+
+CREATE INDEX idx_songs_release_date_id ON songs (release_date, id);
@@ -0,0 +1,51 @@
+-- +goose Up
+-- Исполнители: одинаковые названия без учёта регистра и лишних пробелов ("Muse", "muse", "MUSE ")
+-- сводятся к одному исполнителю
+CREATE TABLE artists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) GENERATED ALWAYS AS (lower(btrim(regexp_replace(name, '\s+', ' ', 'g')))) STORED,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX artists_normalized_name_key ON artists (normalized_name);
+
+-- Из вариантов написания оставляем самый частый
+INSERT INTO artists (name)
+SELECT DISTINCT ON (lower(g.name)) g.name
+FROM (
+    SELECT btrim(regexp_replace(group_name, '\s+', ' ', 'g')) AS name, COUNT(*) AS songs
+    FROM songs
+    GROUP BY 1
+) g
+WHERE g.name <> ''
+ORDER BY lower(g.name), g.songs DESC, g.name;
+
+-- Песни с пустым названием группы получают исполнителя-заглушку, иначе artist_id не сделать обязательным
+INSERT INTO artists (name)
+SELECT 'Unknown Artist'
+WHERE EXISTS (SELECT 1 FROM songs WHERE btrim(regexp_replace(group_name, '\s+', ' ', 'g')) = '')
+ON CONFLICT (normalized_name) DO NOTHING;
+
+ALTER TABLE songs ADD COLUMN artist_id INT REFERENCES artists (id) ON DELETE RESTRICT;
+
+UPDATE songs s
+SET artist_id = a.id, group_name = a.name
+FROM artists a
+WHERE a.normalized_name = lower(btrim(regexp_replace(s.group_name, '\s+', ' ', 'g')));
+
+UPDATE songs s
+SET artist_id = a.id, group_name = a.name
+FROM artists a
+WHERE s.artist_id IS NULL AND a.normalized_name = 'unknown artist';
+
+ALTER TABLE songs ALTER COLUMN artist_id SET NOT NULL;
+
+CREATE INDEX idx_songs_artist_id ON songs (artist_id, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_songs_artist_id;
+
+ALTER TABLE songs DROP COLUMN artist_id;
+
+DROP TABLE artists;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE albums (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    artist_id INT NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    release_date DATE,
+    release_date_precision VARCHAR(5),
+    type VARCHAR(16) NOT NULL DEFAULT 'LP',
+    cover_link TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT albums_type_check CHECK (type IN ('LP', 'EP', 'single', 'compilation')),
+    CONSTRAINT albums_release_date_precision_check CHECK (
+        (release_date IS NULL AND release_date_precision IS NULL)
+        OR (release_date IS NOT NULL AND release_date_precision IN ('year', 'month', 'day'))
+    )
+);
+
+CREATE INDEX idx_albums_artist_id ON albums (artist_id, id);
+
+-- Одна песня может входить в несколько альбомов (оригинальный релиз и сборник)
+CREATE TABLE album_tracks (
+    album_id INT NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    disc_number INT NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+    track_number INT NOT NULL CHECK (track_number > 0),
+    PRIMARY KEY (album_id, disc_number, track_number)
+);
+
+CREATE INDEX idx_album_tracks_song_id ON album_tracks (song_id);
+
+-- +goose Down
+DROP TABLE album_tracks;
+DROP TABLE albums;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Жанры образуют дерево: rock > alt-rock
+CREATE TABLE genres (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    parent_id INT REFERENCES genres (id) ON DELETE RESTRICT,
+    CONSTRAINT genres_not_own_parent CHECK (parent_id <> id)
+);
+
+CREATE UNIQUE INDEX genres_name_key ON genres (lower(name));
+CREATE INDEX idx_genres_parent_id ON genres (parent_id);
+
+-- Теги — произвольные метки, хранятся в нижнем регистре
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE song_genres (
+    song_id INT NOT NULL,
+    genre_id INT NOT NULL,
+    PRIMARY KEY (song_id, genre_id),
+    CONSTRAINT song_genres_song_id_fkey FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE,
+    CONSTRAINT song_genres_genre_id_fkey FOREIGN KEY (genre_id) REFERENCES genres (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_song_genres_genre_id ON song_genres (genre_id, song_id);
+
+CREATE TABLE song_tags (
+    song_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    PRIMARY KEY (song_id, tag_id),
+    CONSTRAINT song_tags_song_id_fkey FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE,
+    CONSTRAINT song_tags_tag_id_fkey FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_song_tags_tag_id ON song_tags (tag_id, song_id);
+
+-- +goose Down
+DROP TABLE song_tags;
+DROP TABLE song_genres;
+DROP TABLE tags;
+DROP TABLE genres;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Структурированный текст песни: упорядоченные секции (куплет, припев, ...) и их строки.
+-- Секции строятся из songs.text при сохранении; для старых записей — при первом чтении.
+CREATE TABLE lyric_sections (
+    id SERIAL PRIMARY KEY,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    label TEXT NOT NULL,
+    CONSTRAINT lyric_sections_type_check CHECK (type IN ('verse', 'chorus', 'bridge', 'intro', 'outro')),
+    CONSTRAINT lyric_sections_position_key UNIQUE (song_id, position)
+);
+
+CREATE TABLE lyric_lines (
+    section_id INT NOT NULL REFERENCES lyric_sections (id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (section_id, position)
+);
+
+-- +goose Down
+DROP TABLE lyric_lines;
+DROP TABLE lyric_sections;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Синхронизированный текст (LRC): метаданные файла и строки с временем начала в миллисекундах.
+-- words — пословные метки enhanced LRC: [{"startMs": 15400, "text": "You"}, ...]
+CREATE TABLE synced_lyrics (
+    song_id INT PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    tags JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE synced_lines (
+    song_id INT NOT NULL REFERENCES synced_lyrics (song_id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    start_ms BIGINT NOT NULL CHECK (start_ms >= 0),
+    text TEXT NOT NULL,
+    words JSONB,
+    PRIMARY KEY (song_id, position)
+);
+
+-- +goose Down
+DROP TABLE synced_lines;
+DROP TABLE synced_lyrics;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Переводы текста песни; language — канонический тег BCP 47 (en, de, pt-BR, sr-Latn, ...)
+CREATE TABLE song_translations (
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    language VARCHAR(35) NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (song_id, language)
+);
+
+-- +goose Down
+DROP TABLE song_translations;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- История изменений песен. Записи не изменяются и не удаляются вместе с песней (нет внешнего ключа),
+-- чтобы удалённую песню можно было восстановить из последней ревизии.
+-- changes — изменившиеся поля: {"text": {"old": "...", "new": "..."}}; snapshot — состояние полей песни.
+CREATE TABLE song_revisions (
+    song_id INT NOT NULL,
+    revision INT NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    actor TEXT NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    snapshot JSONB NOT NULL,
+    restored_from INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (song_id, revision)
+);
+
+-- Первая ревизия для уже существующих песен — их текущее состояние
+INSERT INTO song_revisions (song_id, revision, action, actor, changes, snapshot)
+SELECT id, 1, 'create', 'migration', '{}', jsonb_build_object(
+    'group', group_name,
+    'song', song,
+    'releaseDate', CASE release_date_precision
+        WHEN 'year' THEN to_char(release_date, 'YYYY')
+        WHEN 'month' THEN to_char(release_date, 'YYYY-MM')
+        ELSE to_char(release_date, 'YYYY-MM-DD')
+    END,
+    'text', COALESCE(text, ''),
+    'lyrics', COALESCE(lyrics, ''),
+    'link', COALESCE(link, '')
+)
+FROM songs;
+
+-- +goose Down
+DROP TABLE song_revisions;
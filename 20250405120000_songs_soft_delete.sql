@@ -0,0 +1,11 @@
+-- +goose Up
+-- Мягкое удаление: песня с deleted_at попадает в корзину и скрыта из выборок,
+-- пока её не восстановят или фоновая очистка не удалит её окончательно
+ALTER TABLE songs ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_songs_deleted_at ON songs (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DELETE FROM songs WHERE deleted_at IS NOT NULL;
+DROP INDEX idx_songs_deleted_at;
+ALTER TABLE songs DROP COLUMN deleted_at;
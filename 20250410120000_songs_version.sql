@@ -0,0 +1,7 @@
+-- +goose Up
+-- Версия песни для оптимистичной блокировки: увеличивается при каждом изменении
+-- и отдаётся клиентам как ETag
+ALTER TABLE songs ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE songs DROP COLUMN version;
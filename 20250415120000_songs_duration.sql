@@ -0,0 +1,6 @@
+-- +goose Up
+-- Длительность песни в секундах; NULL — неизвестна. Нужна для общей длительности плейлистов
+ALTER TABLE songs ADD COLUMN duration INT CONSTRAINT songs_duration_check CHECK (duration > 0);
+
+-- +goose Down
+ALTER TABLE songs DROP COLUMN duration;
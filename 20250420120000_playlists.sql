@@ -0,0 +1,52 @@
+-- +goose Up
+CREATE TABLE playlists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Позиции в плейлисте идут подряд с 1. Уникальность проверяется в конце транзакции,
+-- чтобы вставка и перемещение могли сдвигать соседние записи одним UPDATE.
+-- Одна песня может встречаться в плейлисте несколько раз, поэтому у записи свой id.
+CREATE TABLE playlist_entries (
+    id SERIAL PRIMARY KEY,
+    playlist_id INT NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position > 0),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT playlist_entries_position_key UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX idx_playlist_entries_song_id ON playlist_entries (song_id);
+
+-- После любого удаления записей (из API или каскадом при окончательном удалении песни)
+-- позиции оставшихся записей перенумеровываются без пропусков
+-- +goose StatementBegin
+CREATE FUNCTION playlist_entries_compact() RETURNS trigger AS $$
+BEGIN
+    -- Блокируем плейлисты так же, как это делают изменения из API, чтобы не перенумеровать их одновременно
+    PERFORM 1 FROM playlists WHERE id IN (SELECT playlist_id FROM removed) ORDER BY id FOR UPDATE;
+
+    UPDATE playlist_entries e
+    SET position = numbered.position
+    FROM (
+        SELECT id, row_number() OVER (PARTITION BY playlist_id ORDER BY position) AS position
+        FROM playlist_entries
+        WHERE playlist_id IN (SELECT playlist_id FROM removed)
+    ) numbered
+    WHERE e.id = numbered.id AND e.position <> numbered.position;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER playlist_entries_compact
+    AFTER DELETE ON playlist_entries
+    REFERENCING OLD TABLE AS removed
+    FOR EACH STATEMENT EXECUTE FUNCTION playlist_entries_compact();
+
+-- +goose Down
+DROP TABLE playlist_entries;
+DROP FUNCTION playlist_entries_compact();
+DROP TABLE playlists;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Пользователи API. Имя хранится в нижнем регистре, пароль — bcrypt-хешем.
+-- token_version увеличивается при смене роли: refresh-токены с прежней версией перестают действовать.
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(64) NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    role VARCHAR(16) NOT NULL DEFAULT 'viewer' CHECK (role IN ('viewer', 'editor', 'admin')),
+    token_version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS users;
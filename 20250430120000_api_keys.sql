@@ -0,0 +1,19 @@
+-- +goose Up
+-- Ключи API для машинных клиентов. Сам ключ не хранится — только SHA-256 от него;
+-- prefix (начало ключа) помогает узнать ключ в списке. Лимит — запросов в минуту и ёмкость ведра (burst).
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL CHECK (cardinality(scopes) > 0),
+    rate_limit INT NOT NULL CHECK (rate_limit > 0),
+    burst INT NOT NULL CHECK (burst > 0),
+    created_by INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS api_keys;
@@ -15,6 +15,8 @@ import (
 	ms "github.com/skorpsrgvch/music-lib"
 	_ "github.com/skorpsrgvch/music-lib/docs" // Подключаем Swagger документацию
 	"github.com/skorpsrgvch/music-lib/pkg/handler"
+	"github.com/skorpsrgvch/music-lib/pkg/musicinfo"
+	"github.com/skorpsrgvch/music-lib/pkg/ratelimit"
 	"github.com/skorpsrgvch/music-lib/pkg/repository"
 	"github.com/skorpsrgvch/music-lib/pkg/service"
 	"github.com/spf13/viper"
@@ -25,6 +27,14 @@ import (
 // @description API for managing songs
 // @host localhost:8000
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from POST /auth/login as "Bearer <token>"
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key from POST /api-keys/ with the scopes the route requires
 func main() {
 	// Настройка логирования
 	logrus.SetFormatter(&logrus.TextFormatter{
@@ -70,12 +80,67 @@ func main() {
 	logrus.Info("Database initialized successfully")
 
 	// Инициализация репозитория, сервиса и обработчика
-	repos := repository.NewRepository(db)
+	repos := repository.NewRepository(db, viper.GetDuration("db.query_timeout"))
 	logrus.Debug("Repository layer initialized")
 
-	services := service.NewService(repos)
+	infoClient := musicinfo.NewClient(musicinfo.Config{
+		BaseURL:         viper.GetString("music_info.base_url"),
+		Timeout:         viper.GetDuration("music_info.timeout"),
+		Retries:         viper.GetInt("music_info.retries"),
+		RetryBackoff:    viper.GetDuration("music_info.retry_backoff"),
+		MaxRetryBackoff: viper.GetDuration("music_info.max_retry_backoff"),
+	})
+	logrus.WithField("base_url", viper.GetString("music_info.base_url")).Debug("Music info client initialized")
+
+	enricher := service.NewEnricher(repos.Song, infoClient, service.EnrichmentConfig{
+		Workers:         viper.GetInt("enrichment.workers"),
+		QueueSize:       viper.GetInt("enrichment.queue_size"),
+		MaxAttempts:     viper.GetInt("enrichment.max_attempts"),
+		RetryBackoff:    viper.GetDuration("enrichment.retry_backoff"),
+		MaxRetryBackoff: viper.GetDuration("enrichment.max_retry_backoff"),
+		PollInterval:    viper.GetDuration("enrichment.poll_interval"),
+	})
+	enricher.Start()
+
+	purger := service.NewPurger(repos.Song, service.PurgeConfig{
+		Retention: viper.GetDuration("trash.retention"),
+		Interval:  viper.GetDuration("trash.purge_interval"),
+		BatchSize: viper.GetInt("trash.purge_batch_size"),
+	})
+	purger.Start()
+
+	// Ключи подписи токенов берутся из окружения; короткий ключ HS256 легко подобрать
+	authConfig := service.AuthConfig{
+		AccessSecret:  []byte(os.Getenv("JWT_ACCESS_SECRET")),
+		RefreshSecret: []byte(os.Getenv("JWT_REFRESH_SECRET")),
+		AccessTTL:     viper.GetDuration("auth.access_ttl"),
+		RefreshTTL:    viper.GetDuration("auth.refresh_ttl"),
+	}
+	if len(authConfig.AccessSecret) < 32 || len(authConfig.RefreshSecret) < 32 {
+		logrus.Fatal("JWT_ACCESS_SECRET and JWT_REFRESH_SECRET must be set to at least 32 bytes")
+	}
+	if string(authConfig.AccessSecret) == string(authConfig.RefreshSecret) {
+		logrus.Fatal("JWT_ACCESS_SECRET and JWT_REFRESH_SECRET must differ")
+	}
+
+	// Ведра лимитов ключей API хранятся в памяти процесса: у каждого экземпляра сервиса свой счёт
+	apiKeyConfig := service.APIKeyConfig{
+		DefaultRateLimit: viper.GetInt("api_keys.default_rate_limit"),
+		DefaultBurst:     viper.GetInt("api_keys.default_burst"),
+		Limiter:          ratelimit.NewMemoryStore(viper.GetDuration("api_keys.bucket_idle_ttl")),
+	}
+
+	services := service.NewService(repos, infoClient, enricher, authConfig, apiKeyConfig)
 	logrus.Debug("Service layer initialized")
 
+	// Первый администратор создаётся из окружения; если пользователь уже есть, он не меняется
+	if username, password := os.Getenv("ADMIN_USERNAME"), os.Getenv("ADMIN_PASSWORD"); username != "" && password != "" {
+		if err := services.EnsureAdmin(context.Background(), username, password); err != nil {
+			logrus.Fatalf("Error creating admin user: %s", err.Error())
+		}
+		logrus.WithField("username", username).Info("Admin user is available")
+	}
+
 	handlers := handler.NewHandler(services)
 	logrus.Debug("Handler layer initialized")
 
@@ -105,6 +170,19 @@ func main() {
 		logrus.Fatalf("error occured on server shutting down: %s", err.Error())
 	}
 
+	// Даём воркерам дообработать очередь, оставшиеся песни подберутся после перезапуска.
+	// У очистки корзины свой таймаут, чтобы долгий drain очереди не оборвал её пачку.
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), viper.GetDuration("enrichment.drain_timeout"))
+	defer cancelDrain()
+	if err := enricher.Shutdown(drainCtx); err != nil {
+		logrus.Warnf("enrichment queue was not fully drained: %s", err.Error())
+	}
+	purgeCtx, cancelPurge := context.WithTimeout(context.Background(), viper.GetDuration("trash.drain_timeout"))
+	defer cancelPurge()
+	if err := purger.Shutdown(purgeCtx); err != nil {
+		logrus.Warnf("trash purge was interrupted: %s", err.Error())
+	}
+
 	if err := db.Close(); err != nil {
 		logrus.Fatalf("error occured on db connection close: %s", err.Error())
 	}
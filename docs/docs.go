@@ -15,66 +15,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/info": {
+        "/albums/": {
             "get": {
-                "description": "Get song details",
+                "description": "Get a page of albums ordered by release date",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "info"
+                    "albums"
                 ],
-                "summary": "Get song info",
+                "summary": "Get albums",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Group name",
-                        "name": "group",
-                        "in": "query",
-                        "required": true
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "artistId",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Song name",
-                        "name": "song",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Ok",
-                        "schema": {
-                            "$ref": "#/definitions/service.SongDetail"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request"
+                        "description": "Title contains",
+                        "name": "title",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal server error"
-                    }
-                }
-            }
-        },
-        "/songs/": {
-            "get": {
-                "description": "Get a list of all songs with optional filtering",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "songs"
-                ],
-                "summary": "Get all songs",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by group_name, song or lyrics",
-                        "name": "filter",
+                        "description": "Album type: LP, EP, single, compilation",
+                        "name": "type",
                         "in": "query"
                     },
                     {
@@ -94,25 +61,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Song"
-                            }
+                            "$ref": "#/definitions/models.AlbumPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Add a new song to the database",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create an album. The artist is matched by name (ignoring case and extra whitespace) or created.",
                 "consumes": [
                     "application/json"
                 ],
@@ -120,17 +107,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Add a new song",
+                "summary": "Add a new album",
                 "parameters": [
                     {
-                        "description": "Song JSON",
-                        "name": "song",
+                        "description": "Album JSON (type: LP, EP, single, compilation; default LP)",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
                         }
                     }
                 ],
@@ -139,35 +126,116 @@ const docTemplate = `{
                         "description": "Created",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
                         }
                     }
                 }
             }
         },
-        "/songs/{id}": {
+        "/albums/{id}": {
+            "get": {
+                "description": "Get an album with its tracklist ordered by disc and track number",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Get album by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid album ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
             "put": {
-                "description": "Update details of an existing song by its ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Update the given (non-empty) fields of an album",
                 "consumes": [
                     "application/json"
                 ],
@@ -175,30 +243,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Update a song",
+                "summary": "Update an album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
+                        "description": "Album ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated song data",
-                        "name": "song",
+                        "description": "Album JSON",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Song updated successfully",
+                        "description": "Album updated successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -207,41 +275,76 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
                         }
                     },
                     "500": {
-                        "description": "Failed to update song",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Remove a song from the database by its ID",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
                 ],
+                "description": "Delete an album and its tracklist; the songs themselves are kept",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Delete a song",
+                "summary": "Delete an album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
+                        "description": "Album ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -249,7 +352,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Song deleted successfully",
+                        "description": "Album deleted successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -257,61 +360,96 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid album ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to delete song",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
                         }
                     }
                 }
             }
         },
-        "/songs/{id}/text": {
-            "get": {
-                "description": "Get the lyrics of a song by its ID with optional pagination",
+        "/albums/{id}/tracks": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Replace the whole tracklist of an album. A song may appear on several albums.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Get song text",
+                "summary": "Replace album tracklist",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
+                        "description": "Album ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of verses per page (default: 5)",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Tracklist (disc defaults to 1)",
+                        "name": "tracks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TrackPosition"
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Song text",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid song ID or page number",
+                        "description": "Tracklist updated successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -319,47 +457,5830 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Failed to get song text",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid positions or unknown song",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List keys including revoked ones; the keys themselves are not shown, only their prefixes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Get API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a key for a machine client with scopes songs:read, songs:write and/or import.\nThe key is returned only in this response; only its SHA-256 hash is stored.\nWithout rateLimit and burst the configured defaults are used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "API key",
+                        "name": "key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewAPIKey"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.IssuedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke a key; requests with it are rejected with 401. The key stays in the list with revokedAt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API key revoked successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found or already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}/limits": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change requests per minute and burst of a key; the new limit applies to the next request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Change API key rate limit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rate limit",
+                        "name": "limits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKeyLimits"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Limits changed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found or revoked",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/": {
+            "get": {
+                "description": "Get a page of artists ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Get artists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artist name contains",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtistPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page or limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create an artist. Names are unique ignoring case and extra whitespace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Add a new artist",
+                "parameters": [
+                    {
+                        "description": "Artist JSON",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Artist with the same name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Get artist by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid artist ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Artist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Rename an artist; the group name of all its songs is updated as well",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Rename an artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Artist JSON",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Artist updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Artist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Artist with the same name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete an artist that has no songs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Delete an artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Artist deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid artist ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Artist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Artist still has songs",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}/songs": {
+            "get": {
+                "description": "Same filters, sorting and pagination (including cursor) as GET /songs/",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Get songs of an artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name contains",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields (id, group, song, releaseDate), '-' for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset pagination cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Offset pagination (with cursor the body is models.SongCursorPage)",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Artist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Exchange username and password for an access token (sent as \"Authorization: Bearer \u003ctoken\u003e\")\nand a refresh token for POST /auth/refresh",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in",
+                "parameters": [
+                    {
+                        "description": "Username and password",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Credentials"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new token pair. The new access token carries the user's current role;\nrefresh tokens issued before a role change are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, expired or revoked refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/": {
+            "get": {
+                "description": "Get all genres; the hierarchy is given by parentId",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Get genres",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Genre"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a genre, optionally as a subgenre of parentId",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Add a new genre",
+                "parameters": [
+                    {
+                        "description": "Genre JSON",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Genre"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Genre with the same name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid name or unknown parent genre",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete a genre without subgenres; it is detached from all songs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Delete a genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Genre deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid genre ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Genre has subgenres",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/info": {
+            "get": {
+                "description": "Get song details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "info"
+                ],
+                "summary": "Get song info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group name",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name",
+                        "name": "song",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/service.SongDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Music info service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/": {
+            "get": {
+                "description": "Get a page of playlists, recently changed first, with entry count and total duration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get playlists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name contains",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page or limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Create a playlist",
+                "parameters": [
+                    {
+                        "description": "Playlist JSON (only name is used)",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Create a playlist from an M3U/M3U8, XSPF or PLS file. Entries are matched to songs in the catalog\nby artist and title (case-insensitive), falling back to the link; songs in the trash are not matched.\nUnmatched entries are skipped and listed in the report with their line in the file.\nThe body can also be sent as multipart form field \"file\".",
+                "consumes": [
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "audio/x-scpls",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Import a playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "m3u8, xspf or pls; by default taken from Content-Type or the file extension",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playlist name; by default the title from the file or the file name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Playlist file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Unreadable body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role or songs:write and import scopes required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "415": {
+                        "description": "Unknown file format",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Malformed file or invalid name",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}": {
+            "get": {
+                "description": "Get a playlist with its songs in order and the total duration in seconds.\nSongs without a known duration are not included in the total and are counted in unknownDurations.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get playlist by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid playlist ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Playlist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Rename a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Playlist JSON (only name is used)",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Playlist updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Playlist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Delete a playlist and its entries; the songs themselves are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Delete a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Playlist deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid playlist ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Playlist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Insert a song at the given position (1-based); the following entries move down by one.\nWithout position the song is appended. The same song may be added several times.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Add a song to a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Song and position",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewPlaylistEntry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Entry ID and position",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Playlist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown song or position out of range",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries/{entryId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Remove an entry; the following entries move up by one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Remove a song from a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry removed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Playlist or entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries/{entryId}/position": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Move an entry to another position; the entries in between shift towards the freed place",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Move a playlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New position",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistEntryMove"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry moved successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Playlist or entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Position out of range",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/export": {
+            "get": {
+                "description": "Download a playlist for desktop players: extended M3U8 (#EXTINF with \"Artist - Title\"), XSPF or PLS.\nSongs in the trash are left out; M3U8 and PLS list files, so songs without a link are left out too.",
+                "produces": [
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "audio/x-scpls"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Export a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "m3u8 (default), xspf or pls",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid playlist ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Playlist not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown format",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/": {
+            "get": {
+                "description": "Get a page of songs with per-field filters, multi-field sorting and total count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get all songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by group_name, song or lyrics",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "artistId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group name contains",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name contains",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or after (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)",
+                        "name": "releaseDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or before (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)",
+                        "name": "releaseDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) text",
+                        "name": "hasText",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) link",
+                        "name": "hasLink",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Genre names (subgenres included), comma-separated or repeated",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Combine genres with 'or' (default) or 'and'",
+                        "name": "genreMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags, comma-separated or repeated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Combine tags with 'or' (default) or 'and'",
+                        "name": "tagMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include counts per genre, tag and decade for the whole selection",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields (id, group, song, releaseDate), '-' for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated song fields to return, e.g. group,song,releaseDate (id is always returned)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset pagination: pass empty value for the first page, then next_cursor from the previous response",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Offset pagination (with cursor the body is models.SongCursorPage)",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Add a new song to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Add a new song",
+                "parameters": [
+                    {
+                        "description": "Song JSON",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/export": {
+            "get": {
+                "description": "Stream the whole catalog, or the subset selected by the same filters as GET /songs/, as a file or a playlist.\nPagination parameters are ignored. The response uses chunked transfer encoding and is produced\nfrom a database cursor, so the catalog is never held in memory. CSV and TSV start with a header\nrow of field names, so a CSV export can be loaded back with POST /songs/import.\nM3U8, XSPF and PLS are playlists for desktop players: fields is ignored, and M3U8 and PLS skip songs without a link.",
+                "produces": [
+                    "text/csv",
+                    "text/tab-separated-values",
+                    "application/x-ndjson",
+                    "application/vnd.oasis.opendocument.spreadsheet",
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "audio/x-scpls"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Export songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default), tsv, ndjson, ods, m3u8, xspf or pls",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by group_name, song or lyrics",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "artistId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group name contains",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name contains",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or after (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)",
+                        "name": "releaseDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or before (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)",
+                        "name": "releaseDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) text",
+                        "name": "hasText",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) link",
+                        "name": "hasLink",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Genre names (subgenres included), comma-separated or repeated",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Combine genres with 'or' (default) or 'and'",
+                        "name": "genreMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags, comma-separated or repeated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Combine tags with 'or' (default) or 'and'",
+                        "name": "tagMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields (id, group, song, releaseDate), '-' for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns, e.g. group,song,releaseDate (id is always exported)",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown format, field or sort parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Bulk import songs from CSV (header row with song field names: group, song, releaseDate, text, lyrics, link, duration),\nNDJSON (one song object per line) or a JSON array of songs. The file is read as a stream and stored in batches,\neach batch in its own transaction. The body can also be sent as multipart form field \"file\".\nThe report lists invalid records by line (CSV, NDJSON) or by element number (JSON array).\nIf the import fails after some batches were stored, the error response contains the partial report\nin \"report\": savedThrough is the last line (element) whose batch is stored.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Import songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv, ndjson or json; by default taken from Content-Type or the file extension",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate, do not store anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "abort (default): stop at the first invalid record; skip: skip invalid records",
+                        "name": "on_error",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Songs per transaction (default 500, at most 1000)",
+                        "name": "batch_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Import file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or unreadable body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role or songs:write and import scopes required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "415": {
+                        "description": "Unknown file format",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid CSV header or import options",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/search": {
+            "get": {
+                "description": "Search songs by name, group and lyrics with Russian/English stemming, ordered by relevance.\nMatches in the lyrics are highlighted with \u003cmark\u003e\u003c/mark\u003e.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Full-text search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query (websearch syntax: quotes, OR, -word)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search language: ru, en (default: both)",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SongSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page or limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}": {
+            "get": {
+                "description": "Get a song. The ETag header carries the song version; send it back in If-None-Match to get 304 when nothing changed.\nfields limits the response to the listed song fields (id is always returned); include embeds related data.\nResponses with fields or include carry no ETag: a field subset is a different representation,\nand related data is not versioned with the song.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated song fields, e.g. group,song,releaseDate",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related data: artist, albums, tags, genres",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongDetails"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid song ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown field or relation",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Replace all editable fields of a song. Optional fields that are omitted or empty are cleared.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Replace a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Current song ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated song data",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflicting song data",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update song",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Move a song to the trash. With hard=true the song is removed permanently (also from the trash).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Delete a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete permanently",
+                        "name": "hard",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Current song ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid song ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required, admin for hard=true",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete song",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Partially update a song with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902).\nnull or a removed field clears it; group and song cannot be cleared.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Patch a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Current song ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch object or array of JSON Patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "JSON Patch test operation failed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid patch or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update song",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/genres/{genreId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Attach a genre to a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre ID",
+                        "name": "genreId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Genre attached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Detach a genre from a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre ID",
+                        "name": "genreId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Genre detached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Genre is not attached to the song",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics/synced": {
+            "get": {
+                "description": "Get the synchronized lyrics of a song as LRC (default), JSON or WebVTT.\nThe format is taken from the format parameter or, if absent, from the Accept header.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "text/vtt"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get synchronized lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "lrc (default), json or vtt",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/lyrics.SyncedLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song has no synced lyrics",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Upload an LRC or enhanced LRC (word-level \u003cmm:ss.xx\u003e tags) file for a song, replacing the previous one.\nThe file can be sent as the raw request body or as multipart form field \"file\".\nTimestamps must not go backwards; errors are reported per file line.",
+                "consumes": [
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Upload synchronized lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "LRC file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of stored lines",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid song ID or unreadable body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "LRC errors by line",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Delete synchronized lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Synced lyrics deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song has no synced lyrics",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Move a song out of the trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song restored",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid song ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Song is not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions": {
+            "get": {
+                "description": "Change history of a song, newest first. Available for deleted songs too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "List song revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RevisionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song has no revisions",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/diff": {
+            "get": {
+                "description": "Changed fields between two revisions; text and lyrics are also compared line by line",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Compare two revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Older revision",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Newer revision",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RevisionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}": {
+            "get": {
+                "description": "Revision with the snapshot of song fields it produced",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Get a song revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Roll the song back to the state of a revision. A deleted song is recreated with the same ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Restore a song revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song restored",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/tags/{tag}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Add a free-form tag to a song; the tag is created on first use",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Tag a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag attached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid tag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Remove a tag from a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag detached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag is not attached to the song",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/text": {
+            "get": {
+                "description": "Get the lyrics of a song by its ID, paged by section (verse, chorus, bridge, intro, outro).\nPlain text by default; with format=json (or Accept: application/json) sections are returned with their types and labels.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song text",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of sections per page (default: 5)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: text (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Translation language (BCP 47 tag); 404 if there is no such translation",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages; the original text is returned if no translation matches",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transliterate Cyrillic to Latin: iso9 (ISO 9, System A) or gost (GOST 7.79, System B)",
+                        "name": "translit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song text (plain text unless format=json)",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid song ID or page number",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or requested translation not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid paging parameters, language tag or transliteration",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get song text",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/translations": {
+            "get": {
+                "description": "List languages the song text is translated to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "List translations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Translation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/translations/{lang}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Attach a translation of the song text keyed by BCP 47 language tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Add or replace a translation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "BCP 47 language tag",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translation (only text is used)",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Translation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Translation saved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid language tag or blank text",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Delete a translation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "BCP 47 language tag",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Translation deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token or API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Editor role or songs:write scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Translation not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid language tag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "API key rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/": {
+            "get": {
+                "description": "Get tags in use, most popular first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Get tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "description": "Songs in the trash, most recently deleted first. Supports the same filters, sorting and pagination as GET /songs/.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List deleted songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group name contains",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name contains",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields (id, group, song, releaseDate, deletedAt), '-' for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated song fields to return, e.g. group,song,releaseDate (id is always returned)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset pagination cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Offset pagination (with cursor the body is models.SongCursorPage)",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a user with a role (viewer by default). Usernames are stored in lower case;\npasswords must be 8 to 72 bytes long and are stored as bcrypt hashes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a user",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Username is taken",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the role; the user's refresh tokens are revoked, access tokens expire on their own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change a user's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role: viewer, editor or admin",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserRoleChange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role changed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.problemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.problemDetails": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "song with id 42: not found"
+                },
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/songs/42/text"
+                },
+                "report": {
+                    "description": "Report — отчёт импорта, прерванного ошибкой: что из файла уже сохранено",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "lyrics.DiffLine": {
+            "type": "object",
+            "properties": {
+                "newLine": {
+                    "type": "integer"
+                },
+                "oldLine": {
+                    "type": "integer"
+                },
+                "op": {
+                    "type": "string",
+                    "example": "insert"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "lyrics.Section": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Chorus"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "chorus"
+                }
+            }
+        },
+        "lyrics.SyncedLine": {
+            "type": "object",
+            "properties": {
+                "startMs": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "words": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/lyrics.SyncedWord"
+                    }
+                }
+            }
+        },
+        "lyrics.SyncedLyrics": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/lyrics.SyncedLine"
+                    }
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "lyrics.SyncedWord": {
+            "type": "object",
+            "properties": {
+                "startMs": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "burst": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "type": "integer"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.APIKeyLimits": {
+            "type": "object",
+            "required": [
+                "rateLimit"
+            ],
+            "properties": {
+                "burst": {
+                    "type": "integer"
+                },
+                "rateLimit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Album": {
+            "type": "object",
+            "required": [
+                "artist",
+                "title"
+            ],
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "artistId": {
+                    "type": "integer"
+                },
+                "coverLink": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2006-07-03"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "trackCount": {
+                    "type": "integer"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlbumTrack"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "LP"
+                }
+            }
+        },
+        "models.AlbumPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Album"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "links": {
+                    "$ref": "#/definitions/models.PageLinks"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AlbumTrack": {
+            "type": "object",
+            "properties": {
+                "disc": {
+                    "type": "integer"
+                },
+                "song": {
+                    "$ref": "#/definitions/models.Song"
+                },
+                "track": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Artist": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "songCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ArtistPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Artist"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "links": {
+                    "$ref": "#/definitions/models.PageLinks"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Credentials": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "new": {
+                    "type": "string"
+                },
+                "old": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Genre": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GenreFacet": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "aborted": {
+                    "type": "boolean"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowError"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "savedThrough": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.IssuedAPIKey": {
+            "type": "object",
+            "properties": {
+                "burst": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "type": "integer"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewAPIKey": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "burst": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewPlaylistEntry": {
+            "type": "object",
+            "required": [
+                "songId"
+            ],
+            "properties": {
+                "position": {
+                    "type": "integer"
+                },
+                "songId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NewUser": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PageLinks": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "type": "string"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "self": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Playlist": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaylistEntry"
+                    }
+                },
+                "entryCount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unknownDurations": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaylistEntry": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "song": {
+                    "$ref": "#/definitions/models.Song"
+                }
+            }
+        },
+        "models.PlaylistEntryMove": {
+            "type": "object",
+            "required": [
+                "position"
+            ],
+            "properties": {
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlaylistImportMiss": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaylistImportReport": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "playlistId": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unmatched": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaylistImportMiss"
+                    }
+                }
+            }
+        },
+        "models.PlaylistPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Playlist"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "links": {
+                    "$ref": "#/definitions/models.PageLinks"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RevisionDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "lyrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/lyrics.DiffLine"
+                    }
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/lyrics.DiffLine"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RevisionPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SongRevision"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "links": {
+                    "$ref": "#/definitions/models.PageLinks"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Song": {
+            "type": "object",
+            "required": [
+                "group",
+                "song"
+            ],
+            "properties": {
+                "artistId": {
+                    "type": "integer"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "секунды; 0 — неизвестна",
+                    "type": "integer",
+                    "example": 239
+                },
+                "enrichmentStatus": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "lyrics": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongAlbum": {
+            "type": "object",
+            "required": [
+                "artist",
+                "title"
+            ],
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "artistId": {
+                    "type": "integer"
+                },
+                "coverLink": {
+                    "type": "string"
+                },
+                "disc": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2006-07-03"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "track": {
+                    "type": "integer"
+                },
+                "trackCount": {
+                    "type": "integer"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlbumTrack"
                     }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "LP"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Song": {
+        },
+        "models.SongDetails": {
+            "type": "object",
+            "required": [
+                "group",
+                "song"
+            ],
+            "properties": {
+                "albums": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SongAlbum"
+                    }
+                },
+                "artist": {
+                    "$ref": "#/definitions/models.Artist"
+                },
+                "artistId": {
+                    "type": "integer"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "секунды; 0 — неизвестна",
+                    "type": "integer",
+                    "example": 239
+                },
+                "enrichmentStatus": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Genre"
+                    }
+                },
+                "group": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "lyrics": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongFacets": {
+            "type": "object",
+            "properties": {
+                "decades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GenreFacet"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                }
+            }
+        },
+        "models.SongLyrics": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/lyrics.Section"
+                    }
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                },
+                "translit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SongPage": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/models.SongFacets"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Song"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "links": {
+                    "$ref": "#/definitions/models.PageLinks"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "restoredFrom": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.SongSnapshot"
+                },
+                "songId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongSearchResult": {
             "type": "object",
             "required": [
                 "group",
                 "song"
             ],
             "properties": {
+                "artistId": {
+                    "type": "integer"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "секунды; 0 — неизвестна",
+                    "type": "integer",
+                    "example": 239
+                },
+                "enrichmentStatus": {
+                    "type": "string"
+                },
                 "group": {
                     "type": "string"
                 },
+                "headline": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "link": {
                     "type": "string"
                 },
                 "lyrics": {
                     "type": "string"
                 },
+                "rank": {
+                    "type": "number"
+                },
                 "releaseDate": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongSnapshot": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "integer"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "lyrics": {
                     "type": "string"
                 },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
                 "song": {
                     "type": "string"
                 },
                 "text": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "songCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TokenPair": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                },
+                "expiresIn": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "tokenType": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "models.TrackPosition": {
+            "type": "object",
+            "required": [
+                "songId",
+                "track"
+            ],
+            "properties": {
+                "disc": {
+                    "type": "integer"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "track": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Translation": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserRoleChange": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
                 }
             }
         },
@@ -380,6 +6301,20 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "API key from POST /api-keys/ with the scopes the route requires",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Access token from POST /auth/login as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
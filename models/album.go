@@ -0,0 +1,56 @@
+package models
+
+// Типы альбомов
+const (
+	AlbumTypeLP          = "LP"
+	AlbumTypeEP          = "EP"
+	AlbumTypeSingle      = "single"
+	AlbumTypeCompilation = "compilation"
+)
+
+// Album — релиз исполнителя. Исполнитель задаётся названием (artist) так же, как group у песни:
+// он находится по нормализованному названию или создаётся.
+type Album struct {
+	ID          int          `json:"id"`
+	Title       string       `json:"title" binding:"required"`
+	ArtistID    int          `json:"artistId"`
+	Artist      string       `json:"artist" binding:"required"`
+	ReleaseDate ReleaseDate  `json:"releaseDate" swaggertype:"string" example:"2006-07-03"`
+	Type        string       `json:"type" example:"LP"`
+	CoverLink   string       `json:"coverLink"`
+	TrackCount  int          `json:"trackCount"`
+	Tracks      []AlbumTrack `json:"tracks,omitempty"`
+}
+
+// AlbumTrack — песня в трек-листе альбома
+type AlbumTrack struct {
+	Disc  int  `json:"disc"`
+	Track int  `json:"track"`
+	Song  Song `json:"song"`
+}
+
+// TrackPosition — позиция песни при задании трек-листа (PUT /albums/{id}/tracks)
+type TrackPosition struct {
+	SongID int `json:"songId" binding:"required"`
+	Disc   int `json:"disc"`
+	Track  int `json:"track" binding:"required"`
+}
+
+// AlbumFilter — параметры выборки GET /albums/
+type AlbumFilter struct {
+	ArtistID int
+	Title    string
+	Type     string
+	Page     int
+	Limit    int
+}
+
+// AlbumPage — страница списка альбомов
+type AlbumPage struct {
+	Items      []Album   `json:"items"`
+	Total      int       `json:"total"`
+	Page       int       `json:"page"`
+	Limit      int       `json:"limit"`
+	TotalPages int       `json:"totalPages"`
+	Links      PageLinks `json:"links"`
+}
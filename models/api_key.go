@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Права (scopes) ключей API
+const (
+	ScopeSongsRead  = "songs:read"
+	ScopeSongsWrite = "songs:write"
+	ScopeImport     = "import"
+)
+
+// Scopes — все права ключей API
+var Scopes = []string{ScopeSongsRead, ScopeSongsWrite, ScopeImport}
+
+// APIKey — ключ API машинного клиента. Сам ключ не хранится и показывается только при создании;
+// Prefix — его начало, чтобы ключ можно было узнать в списке. RateLimit — запросов в минуту,
+// Burst — сколько запросов можно сделать подряд.
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rateLimit"`
+	Burst      int        `json:"burst"`
+	CreatedBy  *int       `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	KeyHash    string     `json:"-"`
+}
+
+// NewAPIKey — параметры создаваемого ключа (POST /api-keys/); без лимитов используются значения из конфигурации
+type NewAPIKey struct {
+	Name      string   `json:"name" binding:"required"`
+	Scopes    []string `json:"scopes" binding:"required"`
+	RateLimit int      `json:"rateLimit"`
+	Burst     int      `json:"burst"`
+}
+
+// APIKeyLimits — новый лимит ключа (PUT /api-keys/{id}/limits); без burst он равен rateLimit
+type APIKeyLimits struct {
+	RateLimit int `json:"rateLimit" binding:"required"`
+	Burst     int `json:"burst"`
+}
+
+// IssuedAPIKey — созданный ключ вместе с самим ключом, который больше нигде не показывается
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// Artist — исполнитель (группа). Песни ссылаются на него по artistId,
+// а поле group песни хранит каноническое название исполнителя.
+type Artist struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name" binding:"required"`
+	SongCount int    `json:"songCount"`
+}
+
+// ArtistFilter — параметры выборки GET /artists/
+type ArtistFilter struct {
+	Name  string
+	Page  int
+	Limit int
+}
+
+// ArtistPage — страница списка исполнителей
+type ArtistPage struct {
+	Items      []Artist  `json:"items"`
+	Total      int       `json:"total"`
+	Page       int       `json:"page"`
+	Limit      int       `json:"limit"`
+	TotalPages int       `json:"totalPages"`
+	Links      PageLinks `json:"links"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// Genre — жанр; ParentID задаёт иерархию (rock > alt-rock)
+type Genre struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name" binding:"required"`
+	ParentID *int   `json:"parentId"`
+}
+
+// Tag — произвольная пользовательская метка песни
+type Tag struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	SongCount int    `json:"songCount"`
+}
+
+// Режимы сочетания нескольких жанров или тегов в фильтре
+const (
+	MatchAny = "or"
+	MatchAll = "and"
+)
+
+// SongFacets — количество песен текущей выборки по жанрам, тегам и десятилетиям
+type SongFacets struct {
+	Genres  []GenreFacet `json:"genres"`
+	Tags    []FacetCount `json:"tags"`
+	Decades []FacetCount `json:"decades"`
+}
+
+// GenreFacet — число песен жанра с учётом всех его поджанров
+type GenreFacet struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentID *int   `json:"parentId"`
+	Count    int    `json:"count"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
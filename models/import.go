@@ -0,0 +1,41 @@
+package models
+
+// Форматы файлов импорта песен
+const (
+	ImportCSV    = "csv"
+	ImportNDJSON = "ndjson"
+	ImportJSON   = "json"
+)
+
+// Поведение импорта при ошибке в строке: остановиться или пропустить строку
+const (
+	OnErrorAbort = "abort"
+	OnErrorSkip  = "skip"
+)
+
+// ImportOptions — параметры POST /songs/import
+type ImportOptions struct {
+	Format    string
+	DryRun    bool
+	OnError   string
+	BatchSize int
+}
+
+// ImportRowError — ошибки одной записи: для CSV и NDJSON Row — номер строки файла, для JSON-массива — номер элемента с 1
+type ImportRowError struct {
+	Row    int               `json:"row"`
+	Errors map[string]string `json:"errors"`
+}
+
+// ImportReport — результат импорта. При dryRun песни только проверяются, Imported — сколько было бы сохранено.
+// Aborted — импорт остановлен на ошибке; уже сохранённые пачки остаются в базе. SavedThrough — последняя строка
+// (элемент), пачка с которой сохранена: записи до неё включительно, кроме перечисленных в Errors, уже в базе.
+type ImportReport struct {
+	DryRun       bool             `json:"dryRun"`
+	Total        int              `json:"total"`
+	Imported     int              `json:"imported"`
+	Skipped      int              `json:"skipped"`
+	Aborted      bool             `json:"aborted"`
+	SavedThrough int              `json:"savedThrough,omitempty"`
+	Errors       []ImportRowError `json:"errors"`
+}
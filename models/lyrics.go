@@ -0,0 +1,15 @@
+package models
+
+import "github.com/skorpsrgvch/music-lib/pkg/lyrics"
+
+// SongLyrics — страница текста песни, разбитого на секции
+type SongLyrics struct {
+	SongID     int              `json:"songId"`
+	Language   string           `json:"language,omitempty"`
+	Translit   string           `json:"translit,omitempty"`
+	Sections   []lyrics.Section `json:"sections"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	Total      int              `json:"total"`
+	TotalPages int              `json:"totalPages"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Playlist — плейлист пользователя. Песни в нём упорядочены позициями 1..N без пропусков;
+// одна песня может встречаться несколько раз. Duration — сумма длительностей песен в секундах,
+// песни с неизвестной длительностью в неё не входят и считаются в UnknownDurations.
+type Playlist struct {
+	ID               int             `json:"id"`
+	Name             string          `json:"name" binding:"required"`
+	EntryCount       int             `json:"entryCount"`
+	Duration         int             `json:"duration"`
+	UnknownDurations int             `json:"unknownDurations"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	UpdatedAt        time.Time       `json:"updatedAt"`
+	Entries          []PlaylistEntry `json:"entries,omitempty"`
+}
+
+// PlaylistEntry — песня на своей позиции в плейлисте
+type PlaylistEntry struct {
+	ID       int       `json:"id"`
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"addedAt"`
+	Song     Song      `json:"song"`
+}
+
+// NewPlaylistEntry — песня, добавляемая в плейлист (POST /playlists/{id}/entries).
+// Position — позиция новой записи, следующие записи сдвигаются; 0 — в конец плейлиста.
+type NewPlaylistEntry struct {
+	SongID   int `json:"songId" binding:"required"`
+	Position int `json:"position"`
+}
+
+// PlaylistEntryMove — новая позиция записи (PUT /playlists/{id}/entries/{entryId}/position)
+type PlaylistEntryMove struct {
+	Position int `json:"position" binding:"required"`
+}
+
+// PlaylistFilter — параметры выборки GET /playlists/
+type PlaylistFilter struct {
+	Name  string
+	Page  int
+	Limit int
+}
+
+// PlaylistPage — страница списка плейлистов
+type PlaylistPage struct {
+	Items      []Playlist `json:"items"`
+	Total      int        `json:"total"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	TotalPages int        `json:"totalPages"`
+	Links      PageLinks  `json:"links"`
+}
+
+// Форматы файлов плейлистов для выгрузки и импорта
+const (
+	PlaylistM3U8 = "m3u8"
+	PlaylistXSPF = "xspf"
+	PlaylistPLS  = "pls"
+)
+
+// PlaylistItem — запись файла плейлиста: исполнитель и название из #EXTINF, <creator>/<title> или TitleN,
+// ссылка на файл и длительность в секундах (0 — неизвестна). Line — строка файла, с которой начинается запись.
+type PlaylistItem struct {
+	Line     int
+	Group    string
+	Song     string
+	Location string
+	Duration int
+}
+
+// PlaylistImportOptions — параметры POST /playlists/import. Без Name используется название
+// из файла, а без него — имя загруженного файла.
+type PlaylistImportOptions struct {
+	Format   string
+	Name     string
+	Filename string
+}
+
+// PlaylistImportMiss — запись файла, для которой не нашлось песни в каталоге
+type PlaylistImportMiss struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// PlaylistImportReport — результат импорта: созданный плейлист и записи, которые не удалось сопоставить
+type PlaylistImportReport struct {
+	PlaylistID int                  `json:"playlistId"`
+	Name       string               `json:"name"`
+	Total      int                  `json:"total"`
+	Matched    int                  `json:"matched"`
+	Unmatched  []PlaylistImportMiss `json:"unmatched"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// Precondition — условие заголовка If-Match: изменение допустимо, только если текущая
+// версия песни есть в Versions; Any соответствует «If-Match: *» (подходит любая версия)
+type Precondition struct {
+	Any      bool
+	Versions []int
+}
+
+func (p Precondition) Matches(version int) bool {
+	if p.Any {
+		return true
+	}
+	for _, v := range p.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// DatePrecision — точность даты релиза: известен только год, год и месяц или полная дата
+type DatePrecision string
+
+const (
+	PrecisionYear  DatePrecision = "year"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionDay   DatePrecision = "day"
+)
+
+// ReleaseDate — дата релиза с неполной точностью. Time хранит начало периода
+// (для года — 1 января, для месяца — первое число), нулевое значение означает «дата неизвестна».
+type ReleaseDate struct {
+	Time      time.Time
+	Precision DatePrecision
+}
+
+// releaseDateLayouts — поддерживаемые форматы ввода в порядке проверки
+var releaseDateLayouts = []struct {
+	layout    string
+	precision DatePrecision
+}{
+	{"02.01.2006", PrecisionDay},
+	{"01.2006", PrecisionMonth},
+	{time.DateOnly, PrecisionDay},
+	{"2006-01", PrecisionMonth},
+	{"2006", PrecisionYear},
+	{"20060102", PrecisionDay},
+	{time.RFC3339Nano, PrecisionDay},
+	{"2006-01-02T15:04:05", PrecisionDay},
+}
+
+// ParseReleaseDate разбирает дату в форматах DD.MM.YYYY, MM.YYYY, ISO 8601 (YYYY, YYYY-MM, YYYY-MM-DD)
+// и RFC 3339. Для RFC 3339 берётся календарная дата в указанном часовом поясе.
+func ParseReleaseDate(value string) (ReleaseDate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ReleaseDate{}, nil
+	}
+
+	for _, format := range releaseDateLayouts {
+		parsed, err := time.Parse(format.layout, value)
+		if err != nil {
+			continue
+		}
+		return NewReleaseDate(parsed, format.precision), nil
+	}
+
+	return ReleaseDate{}, fmt.Errorf("unsupported date %q, expected DD.MM.YYYY, YYYY[-MM[-DD]] or RFC 3339", value)
+}
+
+// NewReleaseDate обрезает дату до начала периода с заданной точностью
+func NewReleaseDate(t time.Time, precision DatePrecision) ReleaseDate {
+	switch precision {
+	case PrecisionYear:
+		t = time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case PrecisionMonth:
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		precision = PrecisionDay
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return ReleaseDate{Time: t, Precision: precision}
+}
+
+func (d ReleaseDate) IsZero() bool {
+	return d.Time.IsZero()
+}
+
+// End возвращает последний день периода (для года — 31 декабря)
+func (d ReleaseDate) End() time.Time {
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Time.AddDate(1, 0, -1)
+	case PrecisionMonth:
+		return d.Time.AddDate(0, 1, -1)
+	default:
+		return d.Time
+	}
+}
+
+// String возвращает каноническое представление: YYYY, YYYY-MM или YYYY-MM-DD
+func (d ReleaseDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Time.Format("2006")
+	case PrecisionMonth:
+		return d.Time.Format("2006-01")
+	default:
+		return d.Time.Format(time.DateOnly)
+	}
+}
+
+func (d ReleaseDate) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *ReleaseDate) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return apperror.NewValidationError("releaseDate", "must be a string")
+	}
+	if value == nil {
+		*d = ReleaseDate{}
+		return nil
+	}
+
+	parsed, err := ParseReleaseDate(*value)
+	if err != nil {
+		return apperror.NewValidationError("releaseDate", err.Error())
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+)
+
+// Действия, записываемые в историю изменений песни
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// SongSnapshot — состояние редактируемых полей песни на момент ревизии. Version — версия песни
+// после изменения; в сравнении полей она не участвует.
+type SongSnapshot struct {
+	GroupName   string      `json:"group"`
+	SongName    string      `json:"song"`
+	ReleaseDate ReleaseDate `json:"releaseDate" swaggertype:"string" example:"2006-07-16"`
+	Text        string      `json:"text"`
+	Lyrics      string      `json:"lyrics"`
+	Link        string      `json:"link"`
+	Duration    int         `json:"duration,omitempty"`
+	Version     int         `json:"version,omitempty"`
+}
+
+func NewSongSnapshot(song Song) SongSnapshot {
+	return SongSnapshot{
+		GroupName:   song.GroupName,
+		SongName:    song.SongName,
+		ReleaseDate: song.ReleaseDate,
+		Text:        song.Text,
+		Lyrics:      song.Lyrics,
+		Link:        song.Link,
+		Duration:    song.Duration,
+		Version:     song.Version,
+	}
+}
+
+// fields возвращает значения полей по их именам в JSON; пустые значения — nil
+func (s *SongSnapshot) fields() map[string]*string {
+	if s == nil {
+		s = &SongSnapshot{}
+	}
+	return map[string]*string{
+		"group":       nonEmpty(s.GroupName),
+		"song":        nonEmpty(s.SongName),
+		"releaseDate": nonEmpty(s.ReleaseDate.String()),
+		"text":        nonEmpty(s.Text),
+		"lyrics":      nonEmpty(s.Lyrics),
+		"link":        nonEmpty(s.Link),
+		"duration":    nonEmpty(durationString(s.Duration)),
+	}
+}
+
+// FieldChange — старое и новое значение поля; null означает, что значения не было
+type FieldChange struct {
+	Old *string `json:"old"`
+	New *string `json:"new"`
+}
+
+// DiffSnapshots возвращает изменившиеся поля. nil вместо снимка означает, что песни
+// не было (до создания) или больше нет (после удаления).
+func DiffSnapshots(old, new *SongSnapshot) map[string]FieldChange {
+	before, after := old.fields(), new.fields()
+
+	changes := make(map[string]FieldChange)
+	for field, o := range before {
+		n := after[field]
+		if o == nil && n == nil || o != nil && n != nil && *o == *n {
+			continue
+		}
+		changes[field] = FieldChange{Old: o, New: n}
+	}
+	return changes
+}
+
+func nonEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// durationString — длительность в секундах для сравнения снимков; неизвестная — пустая строка
+func durationString(seconds int) string {
+	if seconds == 0 {
+		return ""
+	}
+	return strconv.Itoa(seconds)
+}
+
+// SongRevision — неизменяемая запись истории песни. Snapshot хранит состояние после изменения,
+// а для удаления — последнее состояние перед ним, чтобы песню можно было восстановить.
+type SongRevision struct {
+	SongID       int                    `json:"songId"`
+	Revision     int                    `json:"revision"`
+	Action       string                 `json:"action" example:"update"`
+	Actor        string                 `json:"actor"`
+	Changes      map[string]FieldChange `json:"changes"`
+	RestoredFrom *int                   `json:"restoredFrom,omitempty"`
+	Snapshot     *SongSnapshot          `json:"snapshot,omitempty"`
+	CreatedAt    time.Time              `json:"createdAt"`
+}
+
+// RevisionFilter — параметры выборки GET /songs/{id}/revisions
+type RevisionFilter struct {
+	SongID int
+	Page   int
+	Limit  int
+}
+
+// RevisionPage — страница истории изменений песни, от новых к старым
+type RevisionPage struct {
+	Items      []SongRevision `json:"items"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalPages int            `json:"totalPages"`
+	Links      PageLinks      `json:"links"`
+}
+
+// RevisionDiff — разница между двумя ревизиями песни; для текста — построчная
+type RevisionDiff struct {
+	SongID  int                    `json:"songId"`
+	From    int                    `json:"from"`
+	To      int                    `json:"to"`
+	Changes map[string]FieldChange `json:"changes"`
+	Text    []lyrics.DiffLine      `json:"text,omitempty"`
+	Lyrics  []lyrics.DiffLine      `json:"lyrics,omitempty"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Языки полнотекстового поиска
+const (
+	SearchLanguageAll     = ""
+	SearchLanguageRussian = "ru"
+	SearchLanguageEnglish = "en"
+)
+
+type SongSearch struct {
+	Query    string
+	Language string
+	Page     int
+	Limit    int
+}
+
+// SongSearchResult — найденная песня с релевантностью и подсвеченными совпадениями
+type SongSearchResult struct {
+	Song
+	Rank     float32  `json:"rank"`
+	Headline string   `json:"headline"`
+	Lines    []string `json:"lines"`
+}
@@ -1,11 +1,49 @@
 package models
 
+import "time"
+
+// Статусы дополнения песни данными из внешнего API
+const (
+	EnrichmentPending = "pending"
+	EnrichmentDone    = "done"
+	EnrichmentFailed  = "failed"
+)
+
 type Song struct {
-	ID          int    `json:"id"`
-	GroupName   string `json:"group" binding:"required"`
-	SongName    string `json:"song" binding:"required"`
-	ReleaseDate string `json:"releaseDate" `
-	Text        string `json:"text"`
-	Lyrics      string `json:"lyrics"`
-	Link        string `json:"link"`
+	ID               int         `json:"id"`
+	ArtistID         int         `json:"artistId"`
+	GroupName        string      `json:"group" binding:"required"`
+	SongName         string      `json:"song" binding:"required"`
+	ReleaseDate      ReleaseDate `json:"releaseDate" swaggertype:"string" example:"2006-07-16"`
+	Text             string      `json:"text"`
+	Lyrics           string      `json:"lyrics"`
+	Link             string      `json:"link"`
+	Duration         int         `json:"duration" example:"239"` // секунды; 0 — неизвестна
+	EnrichmentStatus string      `json:"enrichmentStatus,omitempty"`
+	DeletedAt        *time.Time  `json:"deletedAt,omitempty"`
+	Version          int         `json:"version"`
+}
+
+// EnrichmentJob — песня, ожидающая дополнения данными из внешнего API
+type EnrichmentJob struct {
+	SongID    int
+	GroupName string
+	SongName  string
+	Attempts  int
+}
+
+// SongChanges — изменения полей песни по их именам в JSON (group, song, releaseDate, text, lyrics, link).
+// Строки и ReleaseDate задают новое значение, nil очищает поле; остальные поля не меняются.
+type SongChanges map[string]interface{}
+
+// Форматы тела PATCH /songs/{id}
+const (
+	PatchMerge = "merge-patch"
+	PatchJSON  = "json-patch"
+)
+
+// SongPatch — тело PATCH-запроса в формате JSON Merge Patch (RFC 7396) или JSON Patch (RFC 6902)
+type SongPatch struct {
+	Format   string
+	Document []byte
 }
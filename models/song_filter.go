@@ -0,0 +1,60 @@
+package models
+
+// SortField — поле сортировки списка песен (`sort=-releaseDate,group`)
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// SongFilter — параметры выборки GET /songs/; с Trashed выбираются песни из корзины (GET /trash).
+// Fields — выбранные поля песен (пусто — все): не выбранные text и lyrics не читаются из БД.
+type SongFilter struct {
+	Query           string
+	ArtistID        int
+	Group           string
+	Song            string
+	ReleaseDateFrom string
+	ReleaseDateTo   string
+	HasText         *bool
+	HasLink         *bool
+	Genres          []string
+	GenreMode       string
+	Tags            []string
+	TagMode         string
+	Trashed         bool
+	Fields          []string
+	Sort            []SortField
+	Page            int
+	Limit           int
+}
+
+// SongPage — страница списка песен с общим количеством и ссылками на соседние страницы
+type SongPage struct {
+	Items      []Song      `json:"items"`
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	Limit      int         `json:"limit"`
+	TotalPages int         `json:"totalPages"`
+	Links      PageLinks   `json:"links"`
+	Facets     *SongFacets `json:"facets,omitempty"`
+}
+
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// SongCursorPage — страница списка песен при keyset-пагинации (`cursor`)
+type SongCursorPage struct {
+	Items      []Song          `json:"items"`
+	Limit      int             `json:"limit"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Links      CursorPageLinks `json:"links"`
+	Facets     *SongFacets     `json:"facets,omitempty"`
+}
+
+type CursorPageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// SongFields — поля песни, которые можно выбрать параметром fields (`fields=group,song,releaseDate`).
+// id возвращается всегда.
+var SongFields = []string{"id", "artistId", "group", "song", "releaseDate", "text", "lyrics", "link",
+	"duration", "enrichmentStatus", "deletedAt", "version"}
+
+// Связанные данные, которые можно встроить в песню параметром include
+const (
+	IncludeArtist = "artist"
+	IncludeAlbums = "albums"
+	IncludeTags   = "tags"
+	IncludeGenres = "genres"
+)
+
+// SongQuery — параметры GET /songs/{id}: выбранные поля (пусто — все) и встраиваемые связанные данные
+type SongQuery struct {
+	Fields  []string
+	Include []string
+}
+
+// SongRelations — связанные данные песни; заполняются только перечисленные в include
+type SongRelations struct {
+	Artist *Artist     `json:"artist"`
+	Albums []SongAlbum `json:"albums"`
+	Tags   []Tag       `json:"tags"`
+	Genres []Genre     `json:"genres"`
+}
+
+// SongAlbum — альбом, в который входит песня, и её место в трек-листе
+type SongAlbum struct {
+	Album
+	Disc  int `json:"disc"`
+	Track int `json:"track"`
+}
+
+// SongDetails — песня вместе со встроенными связанными данными
+type SongDetails struct {
+	Song
+	SongRelations
+}
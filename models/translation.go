@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Translation — перевод текста песни на язык с тегом BCP 47
+type Translation struct {
+	Language  string    `json:"language" example:"en"`
+	Text      string    `json:"text,omitempty" binding:"required"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LyricsQuery — параметры получения текста песни (GET /songs/{id}/text)
+type LyricsQuery struct {
+	// Lang — явно запрошенный язык (?lang=); если перевода нет, возвращается ErrNotFound
+	Lang string
+	// AcceptLanguage — заголовок Accept-Language; без подходящего перевода отдаётся оригинал
+	AcceptLanguage string
+	// Translit — система транслитерации кириллицы (iso9, gost)
+	Translit string
+	Page     int
+	Limit    int
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Роли пользователей по возрастанию прав: viewer только читает, editor меняет каталог,
+// admin также удаляет безвозвратно, импортирует файлы и управляет пользователями
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Roles — все роли по возрастанию прав
+var Roles = []string{RoleViewer, RoleEditor, RoleAdmin}
+
+// User — пользователь API. Хеш пароля и версия токенов наружу не отдаются.
+type User struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"username"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	PasswordHash string    `json:"-"`
+	TokenVersion int       `json:"-"`
+}
+
+// NewUser — пользователь, которого создаёт администратор (POST /users/); без role — viewer
+type NewUser struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role"`
+}
+
+// UserRoleChange — новая роль пользователя (PUT /users/{id}/role)
+type UserRoleChange struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// Credentials — имя и пароль для входа (POST /auth/login)
+type Credentials struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest — refresh-токен для получения новой пары токенов (POST /auth/refresh)
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// TokenPair — выданные токены; ExpiresIn — время жизни access-токена в секундах
+type TokenPair struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType" example:"Bearer"`
+	ExpiresIn    int    `json:"expiresIn" example:"900"`
+}
+
+// Principal — от чьего имени выполняется запрос: пользователь из access-токена (UserID, Role)
+// или ключ API (APIKeyID, Scopes). Username — автор изменений в истории ревизий.
+type Principal struct {
+	UserID   int
+	Username string
+	Role     string
+	APIKeyID int
+	Scopes   []string
+}
@@ -0,0 +1,49 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Доменные ошибки, общие для репозитория, сервиса и обработчиков.
+// Конкретные ошибки оборачивают их через fmt.Errorf("...: %w", ErrNotFound).
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrValidation          = errors.New("validation failed")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+)
+
+// ValidationError — ошибка валидации с описанием проблем по отдельным полям
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Fields: map[string]string{field: message}}
+}
+
+func (e *ValidationError) Add(field, message string) {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	e.Fields[field] = message
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field, message := range e.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", field, message))
+	}
+	sort.Strings(fields)
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(fields, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
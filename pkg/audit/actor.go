@@ -0,0 +1,25 @@
+// Package audit передаёт через context автора изменений, которого записывает история ревизий.
+package audit
+
+import "context"
+
+// Anonymous — автор изменений, если он не был указан
+const Anonymous = "anonymous"
+
+type actorKey struct{}
+
+// WithActor возвращает контекст с автором изменений
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor возвращает автора изменений из контекста или Anonymous
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok {
+		return actor
+	}
+	return Anonymous
+}
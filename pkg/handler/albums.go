@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// AddAlbum godoc
+// @Summary Add a new album
+// @Description Create an album. The artist is matched by name (ignoring case and extra whitespace) or created.
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param album body models.Album true "Album JSON (type: LP, EP, single, compilation; default LP)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 422 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /albums/ [post]
+// Добавление альбома
+func (h *Handler) AddAlbum(c *gin.Context) {
+	var album models.Album
+	if err := c.ShouldBindJSON(&album); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"title":  album.Title,
+		"artist": album.Artist,
+		"type":   album.Type,
+	}).Info("Adding new album")
+
+	id, err := h.services.AddAlbum(c.Request.Context(), album)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.WithField("album_id", id).Info("Album added successfully")
+	c.JSON(http.StatusCreated, gin.H{"message": "Album added successfully", "id": id})
+}
+
+// GetAlbums godoc
+// @Summary Get albums
+// @Description Get a page of albums ordered by release date
+// @Tags albums
+// @Produce json
+// @Param artistId query int false "Artist ID"
+// @Param title query string false "Title contains"
+// @Param type query string false "Album type: LP, EP, single, compilation"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {object} models.AlbumPage
+// @Failure 400 {object} problemDetails
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /albums/ [get]
+// Получение списка альбомов
+func (h *Handler) GetAlbums(c *gin.Context) {
+	page, limit, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	filter := models.AlbumFilter{
+		Title: c.Query("title"),
+		Type:  c.Query("type"),
+		Page:  page,
+		Limit: limit,
+	}
+	if raw := c.Query("artistId"); raw != "" {
+		var err error
+		if filter.ArtistID, err = strconv.Atoi(raw); err != nil || filter.ArtistID < 1 {
+			newBadRequest(c, "Invalid artistId value")
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": filter.ArtistID,
+		"title":     filter.Title,
+		"type":      filter.Type,
+		"page":      page,
+		"limit":     limit,
+	}).Info("Fetching albums")
+
+	albumPage, err := h.services.GetAlbums(c.Request.Context(), filter)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	albumPage.Links = pageLinks(c, albumPage.Page, albumPage.TotalPages)
+
+	c.JSON(http.StatusOK, albumPage)
+}
+
+// GetAlbum godoc
+// @Summary Get album by ID
+// @Description Get an album with its tracklist ordered by disc and track number
+// @Tags albums
+// @Produce json
+// @Param id path int true "Album ID"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} problemDetails "Invalid album ID"
+// @Failure 404 {object} problemDetails "Album not found"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /albums/{id} [get]
+// Получение альбома с трек-листом
+func (h *Handler) GetAlbum(c *gin.Context) {
+	id, ok := albumID(c)
+	if !ok {
+		return
+	}
+
+	album, err := h.services.GetAlbum(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// UpdateAlbum godoc
+// @Summary Update an album
+// @Description Update the given (non-empty) fields of an album
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Album ID"
+// @Param album body models.Album true "Album JSON"
+// @Success 200 {object} map[string]string "Album updated successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Album not found"
+// @Failure 422 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /albums/{id} [put]
+// Обновление альбома
+func (h *Handler) UpdateAlbum(c *gin.Context) {
+	id, ok := albumID(c)
+	if !ok {
+		return
+	}
+
+	var album models.Album
+	if err := c.ShouldBindJSON(&album); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+		"title":    album.Title,
+		"artist":   album.Artist,
+	}).Info("Updating album")
+
+	if err := h.services.UpdateAlbum(c.Request.Context(), id, album); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Album updated successfully"})
+}
+
+// SetAlbumTracks godoc
+// @Summary Replace album tracklist
+// @Description Replace the whole tracklist of an album. A song may appear on several albums.
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Album ID"
+// @Param tracks body []models.TrackPosition true "Tracklist (disc defaults to 1)"
+// @Success 200 {object} map[string]string "Tracklist updated successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Album not found"
+// @Failure 422 {object} problemDetails "Invalid positions or unknown song"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /albums/{id}/tracks [put]
+// Замена трек-листа альбома
+func (h *Handler) SetAlbumTracks(c *gin.Context) {
+	id, ok := albumID(c)
+	if !ok {
+		return
+	}
+
+	var tracks []models.TrackPosition
+	if err := c.ShouldBindJSON(&tracks); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+		"tracks":   len(tracks),
+	}).Info("Replacing album tracklist")
+
+	if err := h.services.SetAlbumTracks(c.Request.Context(), id, tracks); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tracklist updated successfully"})
+}
+
+// DeleteAlbum godoc
+// @Summary Delete an album
+// @Description Delete an album and its tracklist; the songs themselves are kept
+// @Tags albums
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Album ID"
+// @Success 200 {object} map[string]string "Album deleted successfully"
+// @Failure 400 {object} problemDetails "Invalid album ID"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Album not found"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /albums/{id} [delete]
+// Удаление альбома
+func (h *Handler) DeleteAlbum(c *gin.Context) {
+	id, ok := albumID(c)
+	if !ok {
+		return
+	}
+
+	logrus.Infof("Deleting album with ID %d", id)
+	if err := h.services.DeleteAlbum(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Album deleted successfully"})
+}
+
+func albumID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.Warnf("Invalid album ID: %v", err)
+		newBadRequest(c, "Invalid album ID")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// AddAPIKey godoc
+// @Summary Create an API key
+// @Description Create a key for a machine client with scopes songs:read, songs:write and/or import.
+// @Description The key is returned only in this response; only its SHA-256 hash is stored.
+// @Description Without rateLimit and burst the configured defaults are used.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key body models.NewAPIKey true "API key"
+// @Success 201 {object} models.IssuedAPIKey
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token"
+// @Failure 403 {object} problemDetails "Admin role required"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /api-keys/ [post]
+// Создание ключа API
+func (h *Handler) AddAPIKey(c *gin.Context) {
+	var key models.NewAPIKey
+	if err := c.ShouldBindJSON(&key); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	principal, _ := currentPrincipal(c)
+	logrus.WithFields(logrus.Fields{
+		"name":   key.Name,
+		"scopes": key.Scopes,
+		"by":     principal.Username,
+	}).Info("Creating API key")
+
+	issued, err := h.services.CreateAPIKey(c.Request.Context(), key, principal.UserID)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+// GetAPIKeys godoc
+// @Summary Get API keys
+// @Description List keys including revoked ones; the keys themselves are not shown, only their prefixes
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.APIKey
+// @Failure 401 {object} problemDetails "Missing or invalid token"
+// @Failure 403 {object} problemDetails "Admin role required"
+// @Failure 500 {object} problemDetails
+// @Router /api-keys/ [get]
+// Получение списка ключей API
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.services.GetAPIKeys(c.Request.Context())
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// SetAPIKeyLimits godoc
+// @Summary Change API key rate limit
+// @Description Change requests per minute and burst of a key; the new limit applies to the next request
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Param limits body models.APIKeyLimits true "Rate limit"
+// @Success 200 {object} map[string]string "Limits changed successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token"
+// @Failure 403 {object} problemDetails "Admin role required"
+// @Failure 404 {object} problemDetails "API key not found or revoked"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /api-keys/{id}/limits [put]
+// Смена лимита ключа API
+func (h *Handler) SetAPIKeyLimits(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.Warnf("Invalid API key ID: %v", err)
+		newBadRequest(c, "Invalid API key ID")
+		return
+	}
+
+	var limits models.APIKeyLimits
+	if err := c.ShouldBindJSON(&limits); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"api_key_id": id,
+		"rate_limit": limits.RateLimit,
+		"burst":      limits.Burst,
+	}).Info("Changing API key limits")
+
+	if err := h.services.SetAPIKeyLimits(c.Request.Context(), id, limits); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Limits changed successfully"})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke a key; requests with it are rejected with 401. The key stays in the list with revokedAt.
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} map[string]string "API key revoked successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token"
+// @Failure 403 {object} problemDetails "Admin role required"
+// @Failure 404 {object} problemDetails "API key not found or already revoked"
+// @Failure 500 {object} problemDetails
+// @Router /api-keys/{id} [delete]
+// Отзыв ключа API
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.Warnf("Invalid API key ID: %v", err)
+		newBadRequest(c, "Invalid API key ID")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"api_key_id": id,
+	}).Info("Revoking API key")
+
+	if err := h.services.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
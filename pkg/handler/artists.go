@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// AddArtist godoc
+// @Summary Add a new artist
+// @Description Create an artist. Names are unique ignoring case and extra whitespace.
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param artist body models.Artist true "Artist JSON"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 409 {object} problemDetails "Artist with the same name already exists"
+// @Failure 422 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /artists/ [post]
+// Добавление исполнителя
+func (h *Handler) AddArtist(c *gin.Context) {
+	var artist models.Artist
+	if err := c.ShouldBindJSON(&artist); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithField("name", artist.Name).Info("Adding new artist")
+
+	id, err := h.services.AddArtist(c.Request.Context(), artist.Name)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.WithField("artist_id", id).Info("Artist added successfully")
+	c.JSON(http.StatusCreated, gin.H{"message": "Artist added successfully", "id": id})
+}
+
+// GetArtists godoc
+// @Summary Get artists
+// @Description Get a page of artists ordered by name
+// @Tags artists
+// @Produce json
+// @Param name query string false "Artist name contains"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {object} models.ArtistPage
+// @Failure 400 {object} problemDetails "Invalid page or limit"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /artists/ [get]
+// Получение списка исполнителей
+func (h *Handler) GetArtists(c *gin.Context) {
+	page, limit, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	filter := models.ArtistFilter{
+		Name:  c.Query("name"),
+		Page:  page,
+		Limit: limit,
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"name":  filter.Name,
+		"page":  page,
+		"limit": limit,
+	}).Info("Fetching artists")
+
+	artistPage, err := h.services.GetArtists(c.Request.Context(), filter)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	artistPage.Links = pageLinks(c, artistPage.Page, artistPage.TotalPages)
+
+	c.JSON(http.StatusOK, artistPage)
+}
+
+// GetArtist godoc
+// @Summary Get artist by ID
+// @Tags artists
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Success 200 {object} models.Artist
+// @Failure 400 {object} problemDetails "Invalid artist ID"
+// @Failure 404 {object} problemDetails "Artist not found"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /artists/{id} [get]
+// Получение исполнителя
+func (h *Handler) GetArtist(c *gin.Context) {
+	id, ok := artistID(c)
+	if !ok {
+		return
+	}
+
+	artist, err := h.services.GetArtist(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// GetArtistSongs godoc
+// @Summary Get songs of an artist
+// @Description Same filters, sorting and pagination (including cursor) as GET /songs/
+// @Tags artists
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Param song query string false "Song name contains"
+// @Param sort query string false "Comma-separated sort fields (id, group, song, releaseDate), '-' for descending"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Param cursor query string false "Keyset pagination cursor"
+// @Success 200 {object} models.SongPage "Offset pagination (with cursor the body is models.SongCursorPage)"
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} problemDetails "Artist not found"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /artists/{id}/songs [get]
+// Получение песен исполнителя
+func (h *Handler) GetArtistSongs(c *gin.Context) {
+	id, ok := artistID(c)
+	if !ok {
+		return
+	}
+
+	// Несуществующий исполнитель — 404, а не пустой список
+	if _, err := h.services.GetArtist(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	filter, ok := songFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	filter.ArtistID = id
+
+	h.listSongs(c, filter)
+}
+
+// UpdateArtist godoc
+// @Summary Rename an artist
+// @Description Rename an artist; the group name of all its songs is updated as well
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Artist ID"
+// @Param artist body models.Artist true "Artist JSON"
+// @Success 200 {object} map[string]string "Artist updated successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Artist not found"
+// @Failure 409 {object} problemDetails "Artist with the same name already exists"
+// @Failure 422 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /artists/{id} [put]
+// Переименование исполнителя
+func (h *Handler) UpdateArtist(c *gin.Context) {
+	id, ok := artistID(c)
+	if !ok {
+		return
+	}
+
+	var artist models.Artist
+	if err := c.ShouldBindJSON(&artist); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": id,
+		"name":      artist.Name,
+	}).Info("Renaming artist")
+
+	if err := h.services.UpdateArtist(c.Request.Context(), id, artist.Name); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Artist updated successfully"})
+}
+
+// DeleteArtist godoc
+// @Summary Delete an artist
+// @Description Delete an artist that has no songs
+// @Tags artists
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Artist ID"
+// @Success 200 {object} map[string]string "Artist deleted successfully"
+// @Failure 400 {object} problemDetails "Invalid artist ID"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Artist not found"
+// @Failure 409 {object} problemDetails "Artist still has songs"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /artists/{id} [delete]
+// Удаление исполнителя
+func (h *Handler) DeleteArtist(c *gin.Context) {
+	id, ok := artistID(c)
+	if !ok {
+		return
+	}
+
+	logrus.Infof("Deleting artist with ID %d", id)
+	if err := h.services.DeleteArtist(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Artist deleted successfully"})
+}
+
+func artistID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.Warnf("Invalid artist ID: %v", err)
+		newBadRequest(c, "Invalid artist ID")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/audit"
+	"github.com/skorpsrgvch/music-lib/pkg/ratelimit"
+)
+
+// principalKey — ключ gin.Context с пользователем запроса (models.Principal)
+const principalKey = "principal"
+
+// roleRank — уровень прав роли: каждая следующая роль может всё, что предыдущая
+var roleRank = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+}
+
+// Login godoc
+// @Summary Log in
+// @Description Exchange username and password for an access token (sent as "Authorization: Bearer <token>")
+// @Description and a refresh token for POST /auth/refresh
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.Credentials true "Username and password"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Invalid username or password"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /auth/login [post]
+// Вход по имени и паролю
+func (h *Handler) Login(c *gin.Context) {
+	var credentials models.Credentials
+	if err := c.ShouldBindJSON(&credentials); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	tokens, err := h.services.Login(c.Request.Context(), credentials)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.WithField("username", credentials.Username).Info("User logged in")
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RefreshToken godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new token pair. The new access token carries the user's current role;
+// @Description refresh tokens issued before a role change are rejected.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Invalid, expired or revoked refresh token"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /auth/refresh [post]
+// Обновление пары токенов
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var request models.RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	tokens, err := h.services.Refresh(c.Request.Context(), request.RefreshToken)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// authenticate — middleware, определяющее, от чьего имени выполняется запрос: пользователя из access-токена
+// ("Authorization: Bearer <token>") или ключа API (заголовок X-API-Key). Запрос без них проходит анонимно
+// (чтение открыто всем), неверный или просроченный токен и неизвестный ключ — 401. Запросы с ключом
+// ограничены лимитом ключа: ответ содержит заголовки X-RateLimit-*, а при превышении — 429 с Retry-After.
+func (h *Handler) authenticate(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	apiKey := c.GetHeader("X-API-Key")
+
+	var principal models.Principal
+	switch {
+	case header != "" && apiKey != "":
+		newErrorResponse(c, fmt.Errorf("send either an Authorization header or X-API-Key, not both: %w", apperror.ErrUnauthorized))
+		return
+	case apiKey != "":
+		var limit ratelimit.Result
+		var err error
+		principal, limit, err = h.services.AuthenticateAPIKey(c.Request.Context(), apiKey)
+		if err != nil {
+			newErrorResponse(c, err)
+			return
+		}
+		if !rateLimitHeaders(c, limit) {
+			return
+		}
+	case header != "":
+		scheme, token, _ := strings.Cut(header, " ")
+		token = strings.TrimSpace(token)
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			newErrorResponse(c, fmt.Errorf("authorization header must be 'Bearer <token>': %w", apperror.ErrUnauthorized))
+			return
+		}
+		var err error
+		principal, err = h.services.ParseAccessToken(token)
+		if err != nil {
+			newErrorResponse(c, err)
+			return
+		}
+	default:
+		c.Next()
+		return
+	}
+
+	c.Set(principalKey, principal)
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), principal.Username))
+	c.Next()
+}
+
+// rateLimitHeaders сообщает клиенту состояние лимита: X-RateLimit-Limit — ёмкость ведра,
+// X-RateLimit-Remaining — сколько запросов можно сделать сейчас, X-RateLimit-Reset — через сколько секунд
+// ведро снова будет полным. Если лимит превышен, отвечает 429 и возвращает false.
+func rateLimitHeaders(c *gin.Context, limit ratelimit.Result) bool {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(limit.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(limit.Reset)))
+	if limit.Allowed {
+		return true
+	}
+
+	retryAfter := ceilSeconds(limit.RetryAfter)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	logrus.WithFields(logrus.Fields{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+	}).Warn("API key rate limit exceeded")
+	abortWithProblem(c, problemDetails{
+		Status: http.StatusTooManyRequests,
+		Detail: fmt.Sprintf("API key rate limit exceeded, retry in %d s", retryAfter),
+	})
+	return false
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// currentPrincipal возвращает владельца запроса; false — анонимный запрос
+func currentPrincipal(c *gin.Context) (models.Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return models.Principal{}, false
+	}
+	principal, ok := value.(models.Principal)
+	return principal, ok
+}
+
+// permission — требование маршрута: роль пользователя и право (scope) ключа API.
+// Без роли маршрут открыт пользователям и анонимным запросам; без scope он недоступен ключам.
+type permission struct {
+	role  string
+	scope string
+}
+
+var (
+	readCatalog  = permission{scope: models.ScopeSongsRead}
+	writeCatalog = permission{role: models.RoleEditor, scope: models.ScopeSongsWrite}
+	importFiles  = permission{role: models.RoleAdmin, scope: models.ScopeImport}
+	adminOnly    = permission{role: models.RoleAdmin}
+)
+
+// authorize проверяет права владельца запроса; иначе отвечает 401 (анонимный запрос) или 403
+func authorize(c *gin.Context, required permission) bool {
+	principal, ok := currentPrincipal(c)
+	switch {
+	case ok && principal.APIKeyID != 0:
+		if required.scope == "" {
+			newErrorResponse(c, fmt.Errorf("not available to API keys: %w", apperror.ErrForbidden))
+			return false
+		}
+		if !slices.Contains(principal.Scopes, required.scope) {
+			newErrorResponse(c, fmt.Errorf("API key scope %s required: %w", required.scope, apperror.ErrForbidden))
+			return false
+		}
+	case required.role == "":
+	case !ok:
+		newErrorResponse(c, fmt.Errorf("authentication required: %w", apperror.ErrUnauthorized))
+		return false
+	case roleRank[principal.Role] < roleRank[required.role]:
+		newErrorResponse(c, fmt.Errorf("role %s or higher required: %w", required.role, apperror.ErrForbidden))
+		return false
+	}
+	return true
+}
+
+// require — middleware маршрута или группы: все запросы требуют прав required
+func require(required permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authorize(c, required) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// catalogAccess — middleware групп каталога: чтение (GET, HEAD, OPTIONS) открыто, а ключам API нужен songs:read;
+// изменения требуют роли editor или songs:write
+func catalogAccess(c *gin.Context) {
+	required := writeCatalog
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		required = readCatalog
+	}
+	if !authorize(c, required) {
+		return
+	}
+	c.Next()
+}
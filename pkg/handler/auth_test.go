@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/audit"
+	"github.com/skorpsrgvch/music-lib/pkg/ratelimit"
+	"github.com/skorpsrgvch/music-lib/pkg/service"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// stubAuth принимает access-токены из tokens
+type stubAuth struct {
+	service.Authorization
+	tokens map[string]models.Principal
+}
+
+func (s stubAuth) ParseAccessToken(token string) (models.Principal, error) {
+	principal, ok := s.tokens[token]
+	if !ok {
+		return models.Principal{}, fmt.Errorf("invalid access token: %w", apperror.ErrUnauthorized)
+	}
+	return principal, nil
+}
+
+// stubAPIKeys принимает ключи из keys; limit — состояние лимита для всех ключей
+type stubAPIKeys struct {
+	service.APIKey
+	keys  map[string]models.Principal
+	limit ratelimit.Result
+}
+
+func (s stubAPIKeys) AuthenticateAPIKey(_ context.Context, raw string) (models.Principal, ratelimit.Result, error) {
+	principal, ok := s.keys[raw]
+	if !ok {
+		return models.Principal{}, ratelimit.Result{}, fmt.Errorf("unknown API key: %w", apperror.ErrUnauthorized)
+	}
+	return principal, s.limit, nil
+}
+
+// Владельцы запросов: заголовок, который нужно отправить, чтобы выступить от их имени
+var principals = map[string]struct {
+	header, value string
+	principal     models.Principal
+}{
+	"anonymous":  {},
+	"viewer":     {"Authorization", "Bearer viewer-token", models.Principal{UserID: 1, Username: "viewer", Role: models.RoleViewer}},
+	"editor":     {"Authorization", "Bearer editor-token", models.Principal{UserID: 2, Username: "editor", Role: models.RoleEditor}},
+	"admin":      {"Authorization", "Bearer admin-token", models.Principal{UserID: 3, Username: "admin", Role: models.RoleAdmin}},
+	"key:none":   {"X-API-Key", "key-none", models.Principal{Username: "key:none", APIKeyID: 1}},
+	"key:read":   {"X-API-Key", "key-read", models.Principal{Username: "key:read", APIKeyID: 2, Scopes: []string{models.ScopeSongsRead}}},
+	"key:write":  {"X-API-Key", "key-write", models.Principal{Username: "key:write", APIKeyID: 3, Scopes: []string{models.ScopeSongsWrite}}},
+	"key:import": {"X-API-Key", "key-import", models.Principal{Username: "key:import", APIKeyID: 4, Scopes: []string{models.ScopeSongsWrite, models.ScopeImport}}},
+}
+
+func newTestServices(limit ratelimit.Result) *service.Service {
+	auth := stubAuth{tokens: map[string]models.Principal{}}
+	keys := stubAPIKeys{keys: map[string]models.Principal{}, limit: limit}
+	for _, p := range principals {
+		switch p.header {
+		case "Authorization":
+			auth.tokens[p.value[len("Bearer "):]] = p.principal
+		case "X-API-Key":
+			keys.keys[p.value] = p.principal
+		}
+	}
+	return &service.Service{Authorization: auth, APIKey: keys}
+}
+
+var allowed = ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 6 * time.Second}
+
+// serve выполняет запрос через router от имени владельца who
+func serve(router http.Handler, method, target, who string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if p := principals[who]; p.header != "" {
+		req.Header.Set(p.header, p.value)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthorize(t *testing.T) {
+	h := &Handler{services: newTestServices(allowed)}
+
+	_, router := gin.CreateTestContext(httptest.NewRecorder())
+	router.Use(h.authenticate)
+	for path, perm := range map[string]permission{
+		"/read":   readCatalog,
+		"/write":  writeCatalog,
+		"/import": importFiles,
+		"/admin":  adminOnly,
+	} {
+		router.GET(path, require(perm), func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	// Ожидаемые коды по правам маршрута для каждого владельца запроса
+	tests := []struct {
+		who                         string
+		read, write, imports, admin int
+	}{
+		{"anonymous", 200, 401, 401, 401},
+		{"viewer", 200, 403, 403, 403},
+		{"editor", 200, 200, 403, 403},
+		{"admin", 200, 200, 200, 200},
+		{"key:none", 403, 403, 403, 403},
+		{"key:read", 200, 403, 403, 403},
+		{"key:write", 403, 200, 403, 403},
+		{"key:import", 403, 200, 200, 403},
+	}
+
+	for _, tt := range tests {
+		for path, want := range map[string]int{"/read": tt.read, "/write": tt.write, "/import": tt.imports, "/admin": tt.admin} {
+			if got := serve(router, http.MethodGet, path, tt.who, nil).Code; got != want {
+				t.Errorf("%s GET %s: status = %d, want %d", tt.who, path, got, want)
+			}
+		}
+	}
+}
+
+func TestCatalogAccess(t *testing.T) {
+	h := &Handler{services: newTestServices(allowed)}
+
+	_, router := gin.CreateTestContext(httptest.NewRecorder())
+	router.Use(h.authenticate)
+	songs := router.Group("/songs", catalogAccess)
+	songs.Any("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		who    string
+		method string
+		want   int
+	}{
+		{"anonymous", http.MethodGet, 200},
+		{"anonymous", http.MethodHead, 200},
+		{"anonymous", http.MethodPost, 401},
+		{"anonymous", http.MethodDelete, 401},
+		{"viewer", http.MethodGet, 200},
+		{"viewer", http.MethodPut, 403},
+		{"editor", http.MethodPatch, 200},
+		{"admin", http.MethodDelete, 200},
+		{"key:none", http.MethodGet, 403},
+		{"key:read", http.MethodGet, 200},
+		{"key:read", http.MethodPost, 403},
+		{"key:write", http.MethodGet, 403},
+		{"key:write", http.MethodPost, 200},
+	}
+
+	for _, tt := range tests {
+		if got := serve(router, tt.method, "/songs/", tt.who, nil).Code; got != tt.want {
+			t.Errorf("%s %s /songs/: status = %d, want %d", tt.who, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		limit   ratelimit.Result
+		want    int
+		actor   string
+	}{
+		{name: "anonymous", want: 200},
+		{name: "bearer token", headers: map[string]string{"Authorization": "Bearer editor-token"}, want: 200, actor: "editor"},
+		{name: "lowercase scheme", headers: map[string]string{"Authorization": "bearer editor-token"}, want: 200, actor: "editor"},
+		{name: "api key", headers: map[string]string{"X-API-Key": "key-read"}, limit: allowed, want: 200, actor: "key:read"},
+		{name: "invalid token", headers: map[string]string{"Authorization": "Bearer forged"}, want: 401},
+		{name: "basic scheme", headers: map[string]string{"Authorization": "Basic YWRtaW46YWRtaW4="}, want: 401},
+		{name: "empty bearer", headers: map[string]string{"Authorization": "Bearer "}, want: 401},
+		{name: "unknown api key", headers: map[string]string{"X-API-Key": "stolen"}, want: 401},
+		{
+			name:    "token and api key",
+			headers: map[string]string{"Authorization": "Bearer admin-token", "X-API-Key": "key-read"},
+			limit:   allowed,
+			want:    401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{services: newTestServices(tt.limit)}
+
+			_, router := gin.CreateTestContext(httptest.NewRecorder())
+			router.Use(h.authenticate)
+			router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, audit.Actor(c.Request.Context())) })
+
+			w := serve(router, http.MethodGet, "/", "anonymous", tt.headers)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if tt.actor != "" && w.Body.String() != tt.actor {
+				t.Errorf("actor = %q, want %q", w.Body, tt.actor)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   ratelimit.Result
+		want    int
+		headers map[string]string
+	}{
+		{
+			name:  "allowed",
+			limit: ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 5500 * time.Millisecond},
+			want:  200,
+			headers: map[string]string{
+				"X-RateLimit-Limit": "10", "X-RateLimit-Remaining": "9", "X-RateLimit-Reset": "6", "Retry-After": "",
+			},
+		},
+		{
+			name:  "exceeded",
+			limit: ratelimit.Result{Allowed: false, Limit: 10, Remaining: 0, Reset: time.Minute, RetryAfter: 1500 * time.Millisecond},
+			want:  429,
+			headers: map[string]string{
+				"X-RateLimit-Limit": "10", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "60", "Retry-After": "2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{services: newTestServices(tt.limit)}
+
+			_, router := gin.CreateTestContext(httptest.NewRecorder())
+			router.Use(h.authenticate)
+			router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := serve(router, http.MethodGet, "/", "key:read", nil)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			for name, want := range tt.headers {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemDetails — тело ответа с ошибкой по RFC 7807
+type problemDetails struct {
+	Type     string            `json:"type" example:"about:blank"`
+	Title    string            `json:"title" example:"Not Found"`
+	Status   int               `json:"status" example:"404"`
+	Detail   string            `json:"detail,omitempty" example:"song with id 42: not found"`
+	Instance string            `json:"instance,omitempty" example:"/songs/42/text"`
+	Errors   map[string]string `json:"errors,omitempty"`
+	// Report — отчёт импорта, прерванного ошибкой: что из файла уже сохранено
+	Report *models.ImportReport `json:"report,omitempty"`
+}
+
+func init() {
+	// В ошибках валидации используем имена полей из JSON, а не из Go-структур
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// newErrorResponse — единая точка преобразования ошибок сервиса в HTTP-ответ
+func newErrorResponse(c *gin.Context, err error) {
+	abortWithProblem(c, errorProblem(c, err))
+}
+
+// errorProblem выбирает статус ответа по ошибке сервиса и пишет её в лог
+func errorProblem(c *gin.Context, err error) problemDetails {
+	problem := problemDetails{Status: http.StatusInternalServerError}
+
+	var validationErr *apperror.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		problem.Status = http.StatusUnprocessableEntity
+		problem.Errors = validationErr.Fields
+	case errors.Is(err, apperror.ErrValidation):
+		problem.Status = http.StatusUnprocessableEntity
+	case errors.Is(err, apperror.ErrUnauthorized):
+		problem.Status = http.StatusUnauthorized
+		c.Header("WWW-Authenticate", `Bearer realm="music-lib"`)
+	case errors.Is(err, apperror.ErrForbidden):
+		problem.Status = http.StatusForbidden
+	case errors.Is(err, apperror.ErrNotFound):
+		problem.Status = http.StatusNotFound
+	case errors.Is(err, apperror.ErrConflict):
+		problem.Status = http.StatusConflict
+	case errors.Is(err, apperror.ErrPreconditionFailed):
+		problem.Status = http.StatusPreconditionFailed
+	case errors.Is(err, apperror.ErrUpstreamUnavailable), errors.Is(err, context.Canceled):
+		problem.Status = http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		problem.Status = http.StatusGatewayTimeout
+	}
+
+	// Детали внутренних ошибок наружу не отдаём
+	if problem.Status >= http.StatusInternalServerError {
+		logrus.WithFields(logrus.Fields{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.Path,
+			"status": problem.Status,
+		}).Errorf("Request failed: %v", err)
+	} else {
+		problem.Detail = err.Error()
+		logrus.WithFields(logrus.Fields{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.Path,
+			"status": problem.Status,
+		}).Warnf("Request rejected: %v", err)
+	}
+	return problem
+}
+
+// newBadRequest — ответ на некорректные параметры запроса или тело, которое не удалось разобрать
+func newBadRequest(c *gin.Context, detail string) {
+	abortWithProblem(c, problemDetails{Status: http.StatusBadRequest, Detail: detail})
+}
+
+// bindingError превращает ошибки валидатора gin в ValidationError (422),
+// остальные ошибки разбора тела отдаются как 400
+func bindingError(c *gin.Context, err error) {
+	// Ошибки разбора отдельных полей (например, даты) уже описаны как ValidationError
+	var validationErr *apperror.ValidationError
+	if errors.As(err, &validationErr) {
+		newErrorResponse(c, validationErr)
+		return
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		logrus.Warnf("Invalid request body: %v", err)
+		newBadRequest(c, "Invalid request body")
+		return
+	}
+
+	validationErr = &apperror.ValidationError{}
+	for _, fe := range fieldErrs {
+		validationErr.Add(fe.Field(), "failed on the '"+fe.Tag()+"' rule")
+	}
+	newErrorResponse(c, validationErr)
+}
+
+func abortWithProblem(c *gin.Context, problem problemDetails) {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	problem.Instance = c.Request.URL.RequestURI()
+
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
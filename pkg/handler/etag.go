@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// songETag — ETag песни: её версия в кавычках
+func songETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// parseETags разбирает список ETag из If-Match или If-None-Match; any — значение «*»
+func parseETags(header string) (tags []string, any bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		switch tag {
+		case "":
+		case "*":
+			any = true
+		default:
+			tags = append(tags, tag)
+		}
+	}
+	return tags, any
+}
+
+// requirePrecondition читает If-Match для изменения песни. Без заголовка отвечает 428:
+// изменять песню можно только зная её текущую версию.
+func requirePrecondition(c *gin.Context) (models.Precondition, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		abortWithProblem(c, problemDetails{
+			Status: http.StatusPreconditionRequired,
+			Detail: "If-Match header with the song ETag is required",
+		})
+		return models.Precondition{}, false
+	}
+
+	tags, any := parseETags(header)
+	precondition := models.Precondition{Any: any}
+	for _, tag := range tags {
+		// If-Match использует строгое сравнение: слабые ETag не подходят
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if version, err := strconv.Atoi(strings.Trim(tag, `"`)); err == nil {
+			precondition.Versions = append(precondition.Versions, version)
+		}
+	}
+	return precondition, true
+}
+
+// notModified проверяет If-None-Match (слабое сравнение) и при совпадении отвечает 304
+func notModified(c *gin.Context, etag string) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	tags, match := parseETags(header)
+	for _, tag := range tags {
+		if strings.TrimPrefix(tag, "W/") == etag {
+			match = true
+			break
+		}
+	}
+	if !match {
+		return false
+	}
+
+	c.Header("ETag", etag)
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/service"
+)
+
+// stubSongs хранит одну песню с версией version и проверяет If-Match так же, как репозиторий
+type stubSongs struct {
+	service.Song
+	version int
+	calls   int
+}
+
+func (s *stubSongs) GetSong(_ context.Context, id int, _ models.SongQuery) (models.SongDetails, error) {
+	return models.SongDetails{Song: models.Song{ID: id, GroupName: "Muse", SongName: "Uprising", Version: s.version}}, nil
+}
+
+func (s *stubSongs) change(precondition models.Precondition) (int, error) {
+	s.calls++
+	if !precondition.Matches(s.version) {
+		return 0, fmt.Errorf("song was modified (current version %d): %w", s.version, apperror.ErrPreconditionFailed)
+	}
+	s.version++
+	return s.version, nil
+}
+
+func (s *stubSongs) UpdateSong(_ context.Context, _ int, _ models.Song, precondition models.Precondition) (int, error) {
+	return s.change(precondition)
+}
+
+func (s *stubSongs) PatchSong(_ context.Context, _ int, _ models.SongPatch, precondition models.Precondition) (int, error) {
+	return s.change(precondition)
+}
+
+func (s *stubSongs) DeleteSong(_ context.Context, _ int, _ bool, precondition models.Precondition) error {
+	_, err := s.change(precondition)
+	return err
+}
+
+func newSongRouter(songs *stubSongs) *gin.Engine {
+	services := newTestServices(allowed)
+	services.Song = songs
+	h := &Handler{services: services}
+
+	_, router := gin.CreateTestContext(httptest.NewRecorder())
+	router.Use(h.authenticate)
+	group := router.Group("/songs", catalogAccess)
+	group.GET("/:id", h.GetSong)
+	group.PUT("/:id", h.UpdateSong)
+	group.PATCH("/:id", h.PatchSong)
+	group.DELETE("/:id", h.DeleteSong)
+	return router
+}
+
+func TestGetSongIfNoneMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		ifNoneMatch string
+		want        int
+		wantETag    string
+	}{
+		{name: "no header", target: "/songs/1", want: 200, wantETag: `"3"`},
+		{name: "current version", target: "/songs/1", ifNoneMatch: `"3"`, want: 304, wantETag: `"3"`},
+		{name: "weak comparison", target: "/songs/1", ifNoneMatch: `W/"3"`, want: 304, wantETag: `"3"`},
+		{name: "one of several", target: "/songs/1", ifNoneMatch: `"1", "3"`, want: 304, wantETag: `"3"`},
+		{name: "any", target: "/songs/1", ifNoneMatch: `*`, want: 304, wantETag: `"3"`},
+		{name: "stale version", target: "/songs/1", ifNoneMatch: `"2"`, want: 200, wantETag: `"3"`},
+		{name: "field subset has no etag", target: "/songs/1?fields=group", ifNoneMatch: `"3"`, want: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newSongRouter(&stubSongs{version: 3})
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+			if tt.want == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("304 with body %q", w.Body)
+			}
+		})
+	}
+}
+
+func TestSongIfMatch(t *testing.T) {
+	requests := []struct {
+		method      string
+		contentType string
+		body        string
+	}{
+		{http.MethodPut, gin.MIMEJSON, `{"group": "Muse", "song": "Uprising"}`},
+		{http.MethodPatch, "application/merge-patch+json", `{"link": null}`},
+		{http.MethodDelete, "", ""},
+	}
+
+	tests := []struct {
+		name      string
+		ifMatch   string
+		want      int
+		wantCalls int
+		wantETag  string
+	}{
+		{name: "missing", want: 428},
+		{name: "stale version", ifMatch: `"2"`, want: 412, wantCalls: 1},
+		{name: "weak etag never matches", ifMatch: `W/"3"`, want: 412, wantCalls: 1},
+		{name: "current version", ifMatch: `"3"`, want: 200, wantCalls: 1, wantETag: `"4"`},
+		{name: "one of several", ifMatch: `"2", "3"`, want: 200, wantCalls: 1, wantETag: `"4"`},
+		{name: "any", ifMatch: `*`, want: 200, wantCalls: 1, wantETag: `"4"`},
+	}
+
+	for _, r := range requests {
+		for _, tt := range tests {
+			t.Run(r.method+" "+tt.name, func(t *testing.T) {
+				songs := &stubSongs{version: 3}
+				router := newSongRouter(songs)
+
+				req := httptest.NewRequest(r.method, "/songs/1", strings.NewReader(r.body))
+				req.Header.Set("Authorization", "Bearer editor-token")
+				if r.contentType != "" {
+					req.Header.Set("Content-Type", r.contentType)
+				}
+				if tt.ifMatch != "" {
+					req.Header.Set("If-Match", tt.ifMatch)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				if w.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+				}
+				if songs.calls != tt.wantCalls {
+					t.Errorf("service calls = %d, want %d", songs.calls, tt.wantCalls)
+				}
+				// DELETE не возвращает новую версию: песня уходит в корзину
+				if r.method != http.MethodDelete {
+					if got := w.Header().Get("ETag"); got != tt.wantETag {
+						t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestHardDeleteRequiresAdmin(t *testing.T) {
+	tests := []struct {
+		who       string
+		target    string
+		want      int
+		wantCalls int
+	}{
+		{"editor", "/songs/1", 200, 1},
+		{"editor", "/songs/1?hard=true", 403, 0},
+		{"key:write", "/songs/1?hard=true", 403, 0},
+		{"admin", "/songs/1?hard=true", 200, 1},
+	}
+
+	for _, tt := range tests {
+		songs := &stubSongs{version: 3}
+		w := serve(newSongRouter(songs), http.MethodDelete, tt.target, tt.who, map[string]string{"If-Match": `"3"`})
+		if w.Code != tt.want {
+			t.Errorf("%s DELETE %s: status = %d, want %d", tt.who, tt.target, w.Code, tt.want)
+		}
+		if songs.calls != tt.wantCalls {
+			t.Errorf("%s DELETE %s: service calls = %d, want %d", tt.who, tt.target, songs.calls, tt.wantCalls)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/pkg/songexport"
+)
+
+// ExportSongs godoc
+// @Summary Export songs
+// @Description Stream the whole catalog, or the subset selected by the same filters as GET /songs/, as a file or a playlist.
+// @Description Pagination parameters are ignored. The response uses chunked transfer encoding and is produced
+// @Description from a database cursor, so the catalog is never held in memory. CSV and TSV start with a header
+// @Description row of field names, so a CSV export can be loaded back with POST /songs/import.
+// @Description M3U8, XSPF and PLS are playlists for desktop players: fields is ignored, and M3U8 and PLS skip songs without a link.
+// @Tags songs
+// @Produce text/csv,text/tab-separated-values,application/x-ndjson,application/vnd.oasis.opendocument.spreadsheet,audio/x-mpegurl,application/xspf+xml,audio/x-scpls
+// @Param format query string false "csv (default), tsv, ndjson, ods, m3u8, xspf or pls"
+// @Param filter query string false "Filter by group_name, song or lyrics"
+// @Param artistId query int false "Artist ID"
+// @Param group query string false "Group name contains"
+// @Param song query string false "Song name contains"
+// @Param releaseDateFrom query string false "Released on or after (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)"
+// @Param releaseDateTo query string false "Released on or before (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)"
+// @Param hasText query bool false "Only songs with (true) or without (false) text"
+// @Param hasLink query bool false "Only songs with (true) or without (false) link"
+// @Param genre query []string false "Genre names (subgenres included), comma-separated or repeated" collectionFormat(multi)
+// @Param genreMode query string false "Combine genres with 'or' (default) or 'and'"
+// @Param tag query []string false "Tags, comma-separated or repeated" collectionFormat(multi)
+// @Param tagMode query string false "Combine tags with 'or' (default) or 'and'"
+// @Param sort query string false "Comma-separated sort fields (id, group, song, releaseDate), '-' for descending"
+// @Param fields query string false "Comma-separated columns, e.g. group,song,releaseDate (id is always exported)"
+// @Success 200 {file} file
+// @Failure 400 {object} problemDetails "Invalid parameters"
+// @Failure 422 {object} problemDetails "Unknown format, field or sort parameter"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails "Database query timed out"
+// @Router /songs/export [get]
+// Выгрузка каталога песен
+func (h *Handler) ExportSongs(c *gin.Context) {
+	filter, ok := songFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	format := strings.ToLower(c.DefaultQuery("format", songexport.CSV))
+
+	logrus.WithFields(logrus.Fields{
+		"format":    format,
+		"filter":    filter.Query,
+		"artist_id": filter.ArtistID,
+		"genres":    filter.Genres,
+		"tags":      filter.Tags,
+		"fields":    filter.Fields,
+	}).Info("Exporting songs")
+
+	contentType := songexport.ContentTypes[format]
+	streamDownload(c, contentType, "songs."+format, func(w io.Writer) (int, error) {
+		return h.services.ExportSongs(c.Request.Context(), filter, format, w)
+	})
+}
+
+// streamDownload отдаёт файл, который write пишет прямо в ответ. Заголовки уйдут клиенту вместе
+// с первой порцией данных; Content-Length не задаётся, поэтому ответ передаётся по частям (chunked).
+// Пустой contentType означает неизвестный формат: write вернёт ошибку проверки до записи.
+func streamDownload(c *gin.Context, contentType, filename string, write func(w io.Writer) (int, error)) {
+	if contentType != "" {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	}
+
+	count, err := write(responseStream{c.Writer})
+	if err == nil {
+		return
+	}
+
+	// Пока клиенту ничего не отправлено, можно ответить ошибкой
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"file":    filename,
+		"written": count,
+	}).Errorf("Download interrupted: %v", err)
+	abortStream(c)
+}
+
+// responseStream — тело ответа выгрузки; Flush отправляет клиенту уже записанные данные
+type responseStream struct {
+	w gin.ResponseWriter
+}
+
+func (s responseStream) Write(data []byte) (int, error) {
+	return s.w.Write(data)
+}
+
+func (s responseStream) Flush() error {
+	s.w.Flush()
+	return nil
+}
+
+// abortStream разрывает соединение, не завершая chunked-ответ: так клиент увидит ошибку
+// передачи, а не примет обрезанный файл за целый
+func abortStream(c *gin.Context) {
+	c.Abort()
+	conn, _, err := http.NewResponseController(c.Writer).Hijack()
+	if err != nil {
+		logrus.Warnf("Failed to abort response: %v", err)
+		return
+	}
+	conn.Close()
+}
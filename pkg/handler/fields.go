@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// songFieldSet — ключи JSON-представления песни, которые попадают в ответ: id, выбранные поля
+// (без fields — все поля песни) и встроенные по include связанные данные
+func songFieldSet(fields, include []string) map[string]bool {
+	if len(fields) == 0 {
+		fields = models.SongFields
+	}
+
+	keep := map[string]bool{"id": true}
+	for _, field := range fields {
+		keep[field] = true
+	}
+	for _, name := range include {
+		keep[name] = true
+	}
+	return keep
+}
+
+// selectFields превращает value в JSON-объект, оставляя только ключи keep; nil оставляет все ключи
+func selectFields(value interface{}, keep map[string]bool) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// UseNumber сохраняет числа как есть, без преобразования во float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	if keep != nil {
+		for key := range object {
+			if !keep[key] {
+				delete(object, key)
+			}
+		}
+	}
+	return object, nil
+}
+
+// sparseSongPage — страница списка, в которой песни songs заменены их выбранными полями
+func sparseSongPage(page interface{}, songs []models.Song, keep map[string]bool) (map[string]interface{}, error) {
+	object, err := selectFields(page, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]map[string]interface{}, 0, len(songs))
+	for _, song := range songs {
+		item, err := selectFields(song, keep)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	object["items"] = items
+	return object, nil
+}
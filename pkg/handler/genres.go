@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// AddGenre godoc
+// @Summary Add a new genre
+// @Description Create a genre, optionally as a subgenre of parentId
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param genre body models.Genre true "Genre JSON"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 409 {object} problemDetails "Genre with the same name already exists"
+// @Failure 422 {object} problemDetails "Invalid name or unknown parent genre"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /genres/ [post]
+// Добавление жанра
+func (h *Handler) AddGenre(c *gin.Context) {
+	var genre models.Genre
+	if err := c.ShouldBindJSON(&genre); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"name":      genre.Name,
+		"parent_id": genre.ParentID,
+	}).Info("Adding new genre")
+
+	id, err := h.services.AddGenre(c.Request.Context(), genre)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Genre added successfully", "id": id})
+}
+
+// GetGenres godoc
+// @Summary Get genres
+// @Description Get all genres; the hierarchy is given by parentId
+// @Tags genres
+// @Produce json
+// @Success 200 {array} models.Genre
+// @Failure 500 {object} problemDetails
+// @Router /genres/ [get]
+// Получение списка жанров
+func (h *Handler) GetGenres(c *gin.Context) {
+	genres, err := h.services.GetGenres(c.Request.Context())
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// DeleteGenre godoc
+// @Summary Delete a genre
+// @Description Delete a genre without subgenres; it is detached from all songs
+// @Tags genres
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Genre ID"
+// @Success 200 {object} map[string]string "Genre deleted successfully"
+// @Failure 400 {object} problemDetails "Invalid genre ID"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Genre not found"
+// @Failure 409 {object} problemDetails "Genre has subgenres"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /genres/{id} [delete]
+// Удаление жанра
+func (h *Handler) DeleteGenre(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid genre ID")
+		return
+	}
+
+	logrus.Infof("Deleting genre with ID %d", id)
+	if err := h.services.DeleteGenre(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Genre deleted successfully"})
+}
+
+// AttachSongGenre godoc
+// @Summary Attach a genre to a song
+// @Tags songs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param genreId path int true "Genre ID"
+// @Success 200 {object} map[string]string "Genre attached"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song or genre not found"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/genres/{genreId} [put]
+// Привязка жанра к песне
+func (h *Handler) AttachSongGenre(c *gin.Context) {
+	songID, genreID, ok := songGenreIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.AttachGenre(c.Request.Context(), songID, genreID); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.Infof("Genre %d attached to song %d", genreID, songID)
+	c.JSON(http.StatusOK, gin.H{"message": "Genre attached"})
+}
+
+// DetachSongGenre godoc
+// @Summary Detach a genre from a song
+// @Tags songs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param genreId path int true "Genre ID"
+// @Success 200 {object} map[string]string "Genre detached"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Genre is not attached to the song"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/genres/{genreId} [delete]
+// Отвязка жанра от песни
+func (h *Handler) DetachSongGenre(c *gin.Context) {
+	songID, genreID, ok := songGenreIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.DetachGenre(c.Request.Context(), songID, genreID); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.Infof("Genre %d detached from song %d", genreID, songID)
+	c.JSON(http.StatusOK, gin.H{"message": "Genre detached"})
+}
+
+func songGenreIDs(c *gin.Context) (int, int, bool) {
+	songID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return 0, 0, false
+	}
+	genreID, err := strconv.Atoi(c.Param("genreId"))
+	if err != nil {
+		newBadRequest(c, "Invalid genre ID")
+		return 0, 0, false
+	}
+	return songID, genreID, true
+}
@@ -21,8 +21,11 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(h.authenticate)
 
-	songs := router.Group("/songs")
+	// Чтение каталога открыто всем, изменения требуют роли editor (или songs:write у ключа API);
+	// импорт файлов — роли admin (ключу — import вдобавок к songs:write), безвозвратное удаление — роли admin
+	songs := router.Group("/songs", catalogAccess)
 	{
 		// @Summary Add a new song
 		// @Description Add a new song to the library.
@@ -33,6 +36,12 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		// @Success 201 {object} string
 		// @Failure 400 {string} string
 		songs.POST("/", h.AddSong)
+		// @Summary Import songs from CSV, NDJSON or a JSON array
+		// @Tags songs
+		// @Param dry_run query bool false "Only validate"
+		// @Param on_error query string false "abort or skip"
+		// @Success 200 {object} models.ImportReport
+		songs.POST("/import", require(importFiles), h.ImportSongs)
 		// @Summary Get all songs
 		// @Description Get a list of all songs
 		// @Tags songs
@@ -40,6 +49,18 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		// @Success 200 {array} service.Song
 		// @Failure 500 {string} string
 		songs.GET("/", h.GetSongs)
+		// @Summary Full-text search
+		// @Description Search songs by name, group and lyrics
+		// @Tags songs
+		// @Produce json
+		// @Param q query string true "Search query"
+		// @Success 200 {array} models.SongSearchResult
+		songs.GET("/search", h.SearchSongs)
+		// @Summary Export songs as CSV, TSV, NDJSON, ODS or a playlist
+		// @Tags songs
+		// @Param format query string false "csv, tsv, ndjson, ods, m3u8, xspf or pls"
+		// @Success 200 {file} file
+		songs.GET("/export", h.ExportSongs)
 		// @Summary Get song text by ID
 		// @Description Get the lyrics of a song by ID
 		// @Tags songs
@@ -51,8 +72,11 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		// @Failure 400 {string} string
 		// @Failure 500 {string} string
 		songs.GET("/:id/text", h.GetSongText)
-		// @Summary Update song by ID
-		// @Description Update existing song.
+		// @Summary Get song by ID
+		// @Tags songs
+		songs.GET("/:id", h.GetSong)
+		// @Summary Replace song by ID
+		// @Description Replace existing song.
 		// @Tags songs
 		// @Accept json
 		// @Produce json
@@ -62,6 +86,15 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		// @Failure 400 {string} string
 		// @Failure 500 {string} string
 		songs.PUT("/:id", h.UpdateSong)
+		// @Summary Patch song by ID
+		// @Description Partially update a song with JSON Merge Patch or JSON Patch.
+		// @Tags songs
+		// @Accept json
+		// @Param id path int true "Song ID"
+		// @Success 200 {string} string
+		// @Failure 415 {string} string
+		// @Failure 422 {string} string
+		songs.PATCH("/:id", h.PatchSong)
 		// @Summary Delete song by ID
 		// @Description Delete existing song.
 		// @Tags songs
@@ -69,8 +102,196 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		// @Success 200 {string} string
 		// @Failure 400 {string} string
 		songs.DELETE("/:id", h.DeleteSong)
+		// @Summary Upload synchronized lyrics (LRC)
+		// @Tags songs
+		songs.PUT("/:id/lyrics/synced", h.SetSyncedLyrics)
+		// @Summary Get synchronized lyrics as LRC, JSON or WebVTT
+		// @Tags songs
+		songs.GET("/:id/lyrics/synced", h.GetSyncedLyrics)
+		// @Summary Delete synchronized lyrics
+		// @Tags songs
+		songs.DELETE("/:id/lyrics/synced", h.DeleteSyncedLyrics)
+		// @Summary List translations
+		// @Tags songs
+		songs.GET("/:id/translations", h.GetTranslations)
+		// @Summary Add or replace a translation
+		// @Tags songs
+		songs.PUT("/:id/translations/:lang", h.SetTranslation)
+		// @Summary Delete a translation
+		// @Tags songs
+		songs.DELETE("/:id/translations/:lang", h.DeleteTranslation)
+		// @Summary Restore a deleted song
+		// @Tags trash
+		songs.POST("/:id/restore", h.RestoreSong)
+		// @Summary List song revisions
+		// @Tags revisions
+		songs.GET("/:id/revisions", h.GetRevisions)
+		// @Summary Compare two revisions
+		// @Tags revisions
+		songs.GET("/:id/revisions/diff", h.DiffRevisions)
+		// @Summary Get a song revision
+		// @Tags revisions
+		songs.GET("/:id/revisions/:rev", h.GetRevision)
+		// @Summary Restore a song revision
+		// @Tags revisions
+		songs.POST("/:id/revisions/:rev/restore", h.RestoreRevision)
+		// @Summary Attach a genre to a song
+		// @Tags songs
+		songs.PUT("/:id/genres/:genreId", h.AttachSongGenre)
+		// @Summary Detach a genre from a song
+		// @Tags songs
+		songs.DELETE("/:id/genres/:genreId", h.DetachSongGenre)
+		// @Summary Tag a song
+		// @Tags songs
+		songs.PUT("/:id/tags/:tag", h.AttachSongTag)
+		// @Summary Remove a tag from a song
+		// @Tags songs
+		songs.DELETE("/:id/tags/:tag", h.DetachSongTag)
+	}
+
+	artists := router.Group("/artists", catalogAccess)
+	{
+		// @Summary Add a new artist
+		// @Tags artists
+		artists.POST("/", h.AddArtist)
+		// @Summary Get artists
+		// @Tags artists
+		artists.GET("/", h.GetArtists)
+		// @Summary Get artist by ID
+		// @Tags artists
+		artists.GET("/:id", h.GetArtist)
+		// @Summary Get songs of an artist
+		// @Tags artists
+		artists.GET("/:id/songs", h.GetArtistSongs)
+		// @Summary Rename an artist
+		// @Tags artists
+		artists.PUT("/:id", h.UpdateArtist)
+		// @Summary Delete an artist
+		// @Tags artists
+		artists.DELETE("/:id", h.DeleteArtist)
 	}
 
+	albums := router.Group("/albums", catalogAccess)
+	{
+		// @Summary Add a new album
+		// @Tags albums
+		albums.POST("/", h.AddAlbum)
+		// @Summary Get albums
+		// @Tags albums
+		albums.GET("/", h.GetAlbums)
+		// @Summary Get album with tracklist
+		// @Tags albums
+		albums.GET("/:id", h.GetAlbum)
+		// @Summary Update an album
+		// @Tags albums
+		albums.PUT("/:id", h.UpdateAlbum)
+		// @Summary Replace album tracklist
+		// @Tags albums
+		albums.PUT("/:id/tracks", h.SetAlbumTracks)
+		// @Summary Delete an album
+		// @Tags albums
+		albums.DELETE("/:id", h.DeleteAlbum)
+	}
+
+	playlists := router.Group("/playlists", catalogAccess)
+	{
+		// @Summary Create a playlist
+		// @Tags playlists
+		playlists.POST("/", h.AddPlaylist)
+		// @Summary Import a playlist from M3U8, XSPF or PLS
+		// @Tags playlists
+		// @Success 201 {object} models.PlaylistImportReport
+		playlists.POST("/import", require(importFiles), h.ImportPlaylist)
+		// @Summary Get playlists
+		// @Tags playlists
+		playlists.GET("/", h.GetPlaylists)
+		// @Summary Get playlist with songs and total duration
+		// @Tags playlists
+		playlists.GET("/:id", h.GetPlaylist)
+		// @Summary Rename a playlist
+		// @Tags playlists
+		playlists.PUT("/:id", h.RenamePlaylist)
+		// @Summary Delete a playlist
+		// @Tags playlists
+		playlists.DELETE("/:id", h.DeletePlaylist)
+		// @Summary Export a playlist as M3U8, XSPF or PLS
+		// @Tags playlists
+		// @Param format query string false "m3u8, xspf or pls"
+		// @Success 200 {file} file
+		playlists.GET("/:id/export", h.ExportPlaylist)
+		// @Summary Add a song to a playlist
+		// @Tags playlists
+		playlists.POST("/:id/entries", h.AddPlaylistEntry)
+		// @Summary Move a playlist entry
+		// @Tags playlists
+		playlists.PUT("/:id/entries/:entryId/position", h.MovePlaylistEntry)
+		// @Summary Remove a song from a playlist
+		// @Tags playlists
+		playlists.DELETE("/:id/entries/:entryId", h.RemovePlaylistEntry)
+	}
+
+	genres := router.Group("/genres", catalogAccess)
+	{
+		// @Summary Add a new genre
+		// @Tags genres
+		genres.POST("/", h.AddGenre)
+		// @Summary Get genres
+		// @Tags genres
+		genres.GET("/", h.GetGenres)
+		// @Summary Delete a genre
+		// @Tags genres
+		genres.DELETE("/:id", h.DeleteGenre)
+	}
+
+	auth := router.Group("/auth")
+	{
+		// @Summary Log in with username and password
+		// @Tags auth
+		auth.POST("/login", h.Login)
+		// @Summary Refresh access and refresh tokens
+		// @Tags auth
+		auth.POST("/refresh", h.RefreshToken)
+	}
+
+	users := router.Group("/users", require(adminOnly))
+	{
+		// @Summary Create a user
+		// @Tags users
+		users.POST("/", h.AddUser)
+		// @Summary Get users
+		// @Tags users
+		users.GET("/", h.GetUsers)
+		// @Summary Change a user's role
+		// @Tags users
+		users.PUT("/:id/role", h.SetUserRole)
+	}
+
+	apiKeys := router.Group("/api-keys", require(adminOnly))
+	{
+		// @Summary Create an API key
+		// @Tags api-keys
+		apiKeys.POST("/", h.AddAPIKey)
+		// @Summary Get API keys
+		// @Tags api-keys
+		apiKeys.GET("/", h.GetAPIKeys)
+		// @Summary Change API key rate limit
+		// @Tags api-keys
+		apiKeys.PUT("/:id/limits", h.SetAPIKeyLimits)
+		// @Summary Revoke an API key
+		// @Tags api-keys
+		apiKeys.DELETE("/:id", h.RevokeAPIKey)
+	}
+
+	// @Summary List deleted songs
+	// @Tags trash
+	router.GET("/trash", require(readCatalog), h.GetTrash)
+
+	// @Summary Get tags
+	// @Tags tags
+	router.GET("/tags/", require(readCatalog), h.GetTags)
+
+	router.GET("/info", require(readCatalog), h.GetInfo)
+
 	logrus.Info("Routes initialized successfully")
 	return router
 }
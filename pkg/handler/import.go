@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// maxImportSize — максимальный размер файла импорта
+const maxImportSize = 64 << 20
+
+// importContentTypes — формат импорта по Content-Type тела запроса
+var importContentTypes = map[string]string{
+	"text/csv":             models.ImportCSV,
+	"application/x-ndjson": models.ImportNDJSON,
+	"application/jsonl":    models.ImportNDJSON,
+	gin.MIMEJSON:           models.ImportJSON,
+}
+
+// importExtensions — формат импорта по расширению файла из multipart-формы
+var importExtensions = map[string]string{
+	".csv":    models.ImportCSV,
+	".ndjson": models.ImportNDJSON,
+	".jsonl":  models.ImportNDJSON,
+	".json":   models.ImportJSON,
+}
+
+// ImportSongs godoc
+// @Summary Import songs
+// @Description Bulk import songs from CSV (header row with song field names: group, song, releaseDate, text, lyrics, link, duration),
+// @Description NDJSON (one song object per line) or a JSON array of songs. The file is read as a stream and stored in batches,
+// @Description each batch in its own transaction. The body can also be sent as multipart form field "file".
+// @Description The report lists invalid records by line (CSV, NDJSON) or by element number (JSON array).
+// @Description If the import fails after some batches were stored, the error response contains the partial report
+// @Description in "report": savedThrough is the last line (element) whose batch is stored.
+// @Tags songs
+// @Accept text/csv,application/x-ndjson,json,mpfd
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param format query string false "csv, ndjson or json; by default taken from Content-Type or the file extension"
+// @Param dry_run query bool false "Only validate, do not store anything"
+// @Param on_error query string false "abort (default): stop at the first invalid record; skip: skip invalid records"
+// @Param batch_size query int false "Songs per transaction (default 500, at most 1000)"
+// @Param file formData file false "Import file"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} problemDetails "Invalid parameters or unreadable body"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Admin role or songs:write and import scopes required"
+// @Failure 413 {object} problemDetails "File too large"
+// @Failure 415 {object} problemDetails "Unknown file format"
+// @Failure 422 {object} problemDetails "Invalid CSV header or import options"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails "Database query timed out"
+// @Router /songs/import [post]
+// Массовый импорт песен
+func (h *Handler) ImportSongs(c *gin.Context) {
+	dryRun, err := queryBool(c, "dry_run")
+	if err != nil {
+		newBadRequest(c, "Invalid dry_run value")
+		return
+	}
+	options := models.ImportOptions{
+		Format:  c.Query("format"),
+		DryRun:  dryRun != nil && *dryRun,
+		OnError: c.Query("on_error"),
+	}
+	if raw := c.Query("batch_size"); raw != "" {
+		if options.BatchSize, err = strconv.Atoi(raw); err != nil {
+			newBadRequest(c, "Invalid batch_size value")
+			return
+		}
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	body, filename, err := importBody(c)
+	if err != nil {
+		logrus.Warnf("Failed to read import upload: %v", err)
+		newBadRequest(c, "Failed to read import file")
+		return
+	}
+
+	if options.Format == "" {
+		var ok bool
+		if filename != "" {
+			options.Format, ok = importExtensions[strings.ToLower(filepath.Ext(filename))]
+		} else {
+			options.Format, ok = importContentTypes[c.ContentType()]
+		}
+		if !ok {
+			abortWithProblem(c, problemDetails{
+				Status: http.StatusUnsupportedMediaType,
+				Detail: "Send text/csv, application/x-ndjson or application/json, or pass the format parameter",
+			})
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"format":     options.Format,
+		"dry_run":    options.DryRun,
+		"on_error":   options.OnError,
+		"batch_size": options.BatchSize,
+	}).Info("Importing songs")
+
+	report, err := h.services.ImportSongs(c.Request.Context(), body, options)
+	if err != nil {
+		// Пачки до ошибки уже сохранены: отчёт показывает клиенту, с какой строки продолжать
+		var problem problemDetails
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem = problemDetails{Status: http.StatusRequestEntityTooLarge, Detail: "Import file must not exceed 64 MiB"}
+		} else {
+			problem = errorProblem(c, err)
+		}
+		if report.Aborted {
+			problem.Report = &report
+		}
+		abortWithProblem(c, problem)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// importBody возвращает поток файла: поле "file" multipart-формы (читается потоково, без сохранения
+// на диск) или всё тело запроса. Для формы также возвращается имя файла.
+func importBody(c *gin.Context) (io.Reader, string, error) {
+	if !strings.HasPrefix(c.ContentType(), gin.MIMEMultipartPOSTForm) {
+		return c.Request.Body, "", nil
+	}
+
+	form, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		part, err := form.NextPart()
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FormName() == "file" {
+			return part, part.FileName(), nil
+		}
+	}
+}
@@ -1,14 +1,15 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/skorpsrgvch/music-lib/models"
-	"github.com/skorpsrgvch/music-lib/pkg/service"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+	"github.com/skorpsrgvch/music-lib/pkg/patch"
 )
 
 // @Summary Get song info
@@ -18,19 +19,28 @@ import (
 // @Param group query string true "Group name"
 // @Param song query string true "Song name"
 // @Success 200 {object} service.SongDetail "Ok"
-// @Failure 400 "Bad request"
-// @Failure 500 "Internal server error"
+// @Failure 400 {object} problemDetails "Bad request"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 500 {object} problemDetails "Internal server error"
+// @Failure 503 {object} problemDetails "Music info service unavailable"
 // @Router /info [get]
 func (h *Handler) GetInfo(c *gin.Context) {
 	group := c.Query("group")
 	song := c.Query("song")
 	logrus.Debugf("GetInfo request for group %s and song %s", group, song)
 
-	songDetail := service.SongDetail{
-		ReleaseDate: "16.07.2006",
-		Text:        "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\nYou caught me under false pretenses\nHow long before you let me go?\n\nOoh\nYou set my soul alight\nOoh\nYou set my soul alight",
-		Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	if group == "" || song == "" {
+		logrus.Warn("GetInfo called without group or song")
+		newBadRequest(c, "group and song are required")
+		return
+	}
+
+	songDetail, err := h.services.GetInfo(c.Request.Context(), group, song)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
 	}
+
 	c.JSON(http.StatusOK, songDetail)
 }
 
@@ -40,10 +50,19 @@ func (h *Handler) GetInfo(c *gin.Context) {
 // @Tags songs
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param song body models.Song true "Song JSON"
-// @Success 201 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 409 {object} problemDetails
+// @Failure 422 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 503 {object} problemDetails
+// @Failure 504 {object} problemDetails
 // @Router /songs/ [post]
 // Добавление песни
 func (h *Handler) AddSong(c *gin.Context) {
@@ -53,95 +72,340 @@ func (h *Handler) AddSong(c *gin.Context) {
 	logrus.Info("Received request to add a new song")
 
 	if err := c.ShouldBindJSON(&song); err != nil {
-		logrus.Warnf("Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		bindingError(c, err)
 		return
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"group_name":   song.GroupName,
 		"song":         song.SongName,
-		"release_date": song.ReleaseDate,
+		"release_date": song.ReleaseDate.String(),
 	}).Info("Adding new song")
 
-	if err := h.services.AddSong(song); err != nil {
-		logrus.Errorf("Failed to add song: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add song"})
+	id, err := h.services.AddSong(c.Request.Context(), song)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
-	logrus.Info("Song added successfully")
-	c.JSON(http.StatusCreated, gin.H{"message": "Song added successfully"})
+	logrus.WithField("song_id", id).Info("Song added successfully")
+	c.JSON(http.StatusCreated, gin.H{"message": "Song added successfully", "id": id})
 }
 
 // GetSongs godoc
 // @Summary Get all songs
-// @Description Get a list of all songs with optional filtering
+// @Description Get a page of songs with per-field filters, multi-field sorting and total count
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param filter query string false "Filter by group_name, song or lyrics"
+// @Param artistId query int false "Artist ID"
+// @Param group query string false "Group name contains"
+// @Param song query string false "Song name contains"
+// @Param releaseDateFrom query string false "Released on or after (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)"
+// @Param releaseDateTo query string false "Released on or before (YYYY, YYYY-MM, YYYY-MM-DD, DD.MM.YYYY or RFC 3339)"
+// @Param hasText query bool false "Only songs with (true) or without (false) text"
+// @Param hasLink query bool false "Only songs with (true) or without (false) link"
+// @Param genre query []string false "Genre names (subgenres included), comma-separated or repeated" collectionFormat(multi)
+// @Param genreMode query string false "Combine genres with 'or' (default) or 'and'"
+// @Param tag query []string false "Tags, comma-separated or repeated" collectionFormat(multi)
+// @Param tagMode query string false "Combine tags with 'or' (default) or 'and'"
+// @Param facets query bool false "Include counts per genre, tag and decade for the whole selection"
+// @Param sort query string false "Comma-separated sort fields (id, group, song, releaseDate), '-' for descending"
+// @Param fields query string false "Comma-separated song fields to return, e.g. group,song,releaseDate (id is always returned)"
 // @Param page query int false "Page number"
 // @Param limit query int false "Number of results per page"
-// @Success 200 {array} models.Song
-// @Failure 500 {object} map[string]string
+// @Param cursor query string false "Keyset pagination: pass empty value for the first page, then next_cursor from the previous response"
+// @Success 200 {object} models.SongPage "Offset pagination (with cursor the body is models.SongCursorPage)"
+// @Failure 400 {object} problemDetails
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Failure 503 {object} problemDetails
+// @Failure 504 {object} problemDetails
 // @Router /songs/ [get]
 // Получение списка песен с фильтрацией и пагинацией
 func (h *Handler) GetSongs(c *gin.Context) {
-	filter := c.Query("filter")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))    // По умолчанию page = 1
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10")) // По умолчанию limit = 10
+	filter, ok := songFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	h.listSongs(c, filter)
+}
+
+// songFilterFromQuery разбирает параметры фильтрации, сортировки и пагинации списка песен.
+// При ошибке ответ уже отправлен и возвращается false.
+func songFilterFromQuery(c *gin.Context) (models.SongFilter, bool) {
+	page, limit, ok := queryPage(c)
+	if !ok {
+		return models.SongFilter{}, false
+	}
+
+	filter := models.SongFilter{
+		Query:           c.Query("filter"),
+		Group:           c.Query("group"),
+		Song:            c.Query("song"),
+		ReleaseDateFrom: c.Query("releaseDateFrom"),
+		ReleaseDateTo:   c.Query("releaseDateTo"),
+		Genres:          queryList(c, "genre"),
+		GenreMode:       c.Query("genreMode"),
+		Tags:            queryList(c, "tag"),
+		TagMode:         c.Query("tagMode"),
+		Fields:          queryList(c, "fields"),
+		Sort:            parseSort(c.Query("sort")),
+		Page:            page,
+		Limit:           limit,
+	}
+
+	var err error
+	if raw := c.Query("artistId"); raw != "" {
+		if filter.ArtistID, err = strconv.Atoi(raw); err != nil || filter.ArtistID < 1 {
+			newBadRequest(c, "Invalid artistId value")
+			return models.SongFilter{}, false
+		}
+	}
+	if filter.HasText, err = queryBool(c, "hasText"); err != nil {
+		newBadRequest(c, "Invalid hasText value")
+		return models.SongFilter{}, false
+	}
+	if filter.HasLink, err = queryBool(c, "hasLink"); err != nil {
+		newBadRequest(c, "Invalid hasLink value")
+		return models.SongFilter{}, false
+	}
+	return filter, true
+}
+
+// listSongs отдаёт страницу песен: постранично или, при наличии параметра cursor, по курсору.
+// С facets=true к странице добавляются количества по жанрам, тегам и десятилетиям для всей выборки.
+func (h *Handler) listSongs(c *gin.Context, filter models.SongFilter) {
+	withFacets, err := queryBool(c, "facets")
+	if err != nil {
+		newBadRequest(c, "Invalid facets value")
+		return
+	}
 
 	// Логируем параметры запроса
 	logrus.WithFields(logrus.Fields{
-		"filter": filter,
-		"page":   page,
-		"limit":  limit,
+		"filter":    filter.Query,
+		"artist_id": filter.ArtistID,
+		"group":     filter.Group,
+		"song":      filter.Song,
+		"genres":    filter.Genres,
+		"tags":      filter.Tags,
+		"sort":      c.Query("sort"),
+		"fields":    filter.Fields,
+		"page":      filter.Page,
+		"limit":     filter.Limit,
 	}).Info("Fetching songs with filters")
 
-	songs, err := h.services.GetSongs(filter, page, limit)
+	var facets *models.SongFacets
+	if withFacets != nil && *withFacets {
+		counts, err := h.services.GetSongFacets(c.Request.Context(), filter)
+		if err != nil {
+			newErrorResponse(c, err)
+			return
+		}
+		facets = &counts
+	}
+
+	// При наличии параметра cursor (даже пустого) используем keyset-пагинацию
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		cursorPage, err := h.services.GetSongsByCursor(c.Request.Context(), filter, cursor)
+		if err != nil {
+			newErrorResponse(c, err)
+			return
+		}
+		cursorPage.Links = cursorLinks(c, cursorPage.NextCursor)
+		cursorPage.Facets = facets
+
+		logrus.Infof("Successfully retrieved %d songs by cursor", len(cursorPage.Items))
+		respondSongPage(c, cursorPage, cursorPage.Items, filter.Fields)
+		return
+	}
+
+	songPage, err := h.services.GetSongs(c.Request.Context(), filter)
 	if err != nil {
-		logrus.Errorf("Failed to get songs: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get songs"})
+		newErrorResponse(c, err)
 		return
 	}
+	songPage.Links = pageLinks(c, songPage.Page, songPage.TotalPages)
+	songPage.Facets = facets
 
-	logrus.Infof("Successfully retrieved %d songs", len(songs))
-	c.JSON(http.StatusOK, songs)
+	logrus.Infof("Successfully retrieved %d of %d songs", len(songPage.Items), songPage.Total)
+	respondSongPage(c, songPage, songPage.Items, filter.Fields)
+}
+
+// respondSongPage отдаёт страницу песен; с параметром fields у песен остаются только выбранные поля
+func respondSongPage(c *gin.Context, page interface{}, songs []models.Song, fields []string) {
+	if len(fields) == 0 {
+		c.JSON(http.StatusOK, page)
+		return
+	}
+
+	sparse, err := sparseSongPage(page, songs, songFieldSet(fields, nil))
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sparse)
+}
+
+// SearchSongs godoc
+// @Summary Full-text search
+// @Description Search songs by name, group and lyrics with Russian/English stemming, ordered by relevance.
+// @Description Matches in the lyrics are highlighted with <mark></mark>.
+// @Tags songs
+// @Produce json
+// @Param q query string true "Search query (websearch syntax: quotes, OR, -word)"
+// @Param lang query string false "Search language: ru, en (default: both)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {array} models.SongSearchResult
+// @Failure 400 {object} problemDetails "Invalid page or limit"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /songs/search [get]
+// Полнотекстовый поиск песен
+func (h *Handler) SearchSongs(c *gin.Context) {
+	page, limit, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	search := models.SongSearch{
+		Query:    c.Query("q"),
+		Language: c.Query("lang"),
+		Page:     page,
+		Limit:    limit,
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"q":     search.Query,
+		"lang":  search.Language,
+		"page":  page,
+		"limit": limit,
+	}).Info("Searching songs")
+
+	results, err := h.services.SearchSongs(c.Request.Context(), search)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.Infof("Search returned %d songs", len(results))
+	c.JSON(http.StatusOK, results)
+}
+
+// GetSong godoc
+// @Summary Get song by ID
+// @Description Get a song. The ETag header carries the song version; send it back in If-None-Match to get 304 when nothing changed.
+// @Description fields limits the response to the listed song fields (id is always returned); include embeds related data.
+// @Description Responses with fields or include carry no ETag: a field subset is a different representation,
+// @Description and related data is not versioned with the song.
+// @Tags songs
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param fields query string false "Comma-separated song fields, e.g. group,song,releaseDate"
+// @Param include query string false "Comma-separated related data: artist, albums, tags, genres"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.SongDetails
+// @Success 304 "Not modified"
+// @Failure 400 {object} problemDetails "Invalid song ID"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 422 {object} problemDetails "Unknown field or relation"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails "Database query timed out"
+// @Router /songs/{id} [get]
+// Получение песни
+func (h *Handler) GetSong(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	query := models.SongQuery{
+		Fields:  queryList(c, "fields"),
+		Include: queryList(c, "include"),
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": id,
+		"fields":  query.Fields,
+		"include": query.Include,
+	}).Debug("Fetching song")
+
+	song, err := h.services.GetSong(c.Request.Context(), id, query)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	// ETag с версией описывает только полную песню: выборка полей — другое представление,
+	// а связанные данные не входят в версию, поэтому с fields и include ETag не отдаётся
+	if len(query.Fields) == 0 && len(query.Include) == 0 {
+		etag := songETag(song.Version)
+		if notModified(c, etag) {
+			return
+		}
+		c.Header("ETag", etag)
+		c.JSON(http.StatusOK, song.Song)
+		return
+	}
+
+	resource, err := selectFields(song, songFieldSet(query.Fields, query.Include))
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resource)
 }
 
 // GetSongText godoc
 // @Summary Get song text
-// @Description Get the lyrics of a song by its ID with optional pagination
+// @Description Get the lyrics of a song by its ID, paged by section (verse, chorus, bridge, intro, outro).
+// @Description Plain text by default; with format=json (or Accept: application/json) sections are returned with their types and labels.
 // @Tags songs
 // @Accept json
-// @Produce text/plain
+// @Produce plain,json
 // @Param id path int true "Song ID"
 // @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Number of verses per page (default: 5)"
-// @Success 200 {string} string "Song text"
-// @Failure 400 {object} map[string]string "Invalid song ID or page number"
-// @Failure 500 {object} map[string]string "Failed to get song text"
+// @Param limit query int false "Number of sections per page (default: 5)"
+// @Param format query string false "Response format: text (default) or json"
+// @Param lang query string false "Translation language (BCP 47 tag); 404 if there is no such translation"
+// @Param Accept-Language header string false "Preferred languages; the original text is returned if no translation matches"
+// @Param translit query string false "Transliterate Cyrillic to Latin: iso9 (ISO 9, System A) or gost (GOST 7.79, System B)"
+// @Success 200 {object} models.SongLyrics "Song text (plain text unless format=json)"
+// @Failure 400 {object} problemDetails "Invalid song ID or page number"
+// @Failure 404 {object} problemDetails "Song or requested translation not found"
+// @Failure 422 {object} problemDetails "Invalid paging parameters, language tag or transliteration"
+// @Failure 500 {object} problemDetails "Failed to get song text"
+// @Failure 504 {object} problemDetails "Database query timed out"
 // @Router /songs/{id}/text [get]
-// Получение текста песни с пагинацией
+// Получение текста песни с пагинацией по секциям
 func (h *Handler) GetSongText(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.Warnf("Invalid song ID: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid song ID"})
+		newBadRequest(c, "Invalid song ID")
 		return
 	}
 
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil {
-		logrus.Warnf("Invalid page number: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+	if err != nil || page < 1 {
+		logrus.Warnf("Invalid page number: %s", c.Query("page"))
+		newBadRequest(c, "Invalid page number")
 		return
 	}
 	pageSize, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
-	if err != nil {
-		logrus.Warnf("Invalid page size: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page size"})
+	if err != nil || pageSize < 1 {
+		logrus.Warnf("Invalid page size: %s", c.Query("limit"))
+		newBadRequest(c, "Invalid page size")
+		return
+	}
+
+	asJSON, ok := lyricsFormat(c)
+	if !ok {
 		return
 	}
 
@@ -149,69 +413,93 @@ func (h *Handler) GetSongText(c *gin.Context) {
 		"song_id":  id,
 		"page":     page,
 		"pageSize": pageSize,
+		"json":     asJSON,
+		"lang":     c.Query("lang"),
+		"translit": c.Query("translit"),
 	}).Info("Fetching song text")
 
-	text, err := h.services.GetSongText(id)
-	if err != nil {
-		logrus.Errorf("Failed to get text from service: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get text"})
-		return
+	query := models.LyricsQuery{
+		Lang:           c.Query("lang"),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+		Translit:       c.Query("translit"),
+		Page:           page,
+		Limit:          pageSize,
 	}
 
-	text = strings.ReplaceAll(text, "\\n", "\n")
-	verses := splitVerses(text)
-	start := (page - 1) * pageSize
-	end := start + pageSize
-
-	if start >= len(verses) {
-		logrus.Infof("Requested page %d exceeds available verses", page)
-		c.JSON(http.StatusOK, gin.H{"text": ""})
+	songLyrics, err := h.services.GetSongLyrics(c.Request.Context(), id, query)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
-
-	if end > len(verses) {
-		end = len(verses)
+	if songLyrics.Language != "" {
+		c.Header("Content-Language", songLyrics.Language)
 	}
+	c.Header("Vary", "Accept, Accept-Language")
 
-	logrus.Infof("Returning verses %d to %d", start, end)
+	logrus.Infof("Returning %d of %d sections", len(songLyrics.Sections), songLyrics.Total)
+	if asJSON {
+		c.JSON(http.StatusOK, songLyrics)
+		return
+	}
 	c.Header("Content-Type", "text/plain; charset=utf-8")
-	c.String(http.StatusOK, strings.Join(verses[start:end], "\n\n")) // Разделение куплетов двойным переносом
+	c.String(http.StatusOK, lyrics.Text(songLyrics.Sections)) // Секции разделяются пустой строкой
 }
 
-// Разбивает текст на куплеты
-func splitVerses(text string) []string {
-	verses := strings.Split(text, "\\n\\n")
-	for i := range verses {
-		verses[i] = strings.TrimSuffix(verses[i], "\\n")
+// lyricsFormat определяет формат ответа с текстом: параметр format важнее заголовка Accept
+func lyricsFormat(c *gin.Context) (asJSON bool, ok bool) {
+	switch c.Query("format") {
+	case "json":
+		return true, true
+	case "text":
+		return false, true
+	case "":
+		return c.NegotiateFormat(gin.MIMEPlain, gin.MIMEJSON) == gin.MIMEJSON, true
 	}
-	return verses
+	newBadRequest(c, "Invalid format value, expected text or json")
+	return false, false
 }
 
 // UpdateSong godoc
-// @Summary Update a song
-// @Description Update details of an existing song by its ID
+// @Summary Replace a song
+// @Description Replace all editable fields of a song. Optional fields that are omitted or empty are cleared.
 // @Tags songs
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Song ID"
+// @Param If-Match header string true "Current song ETag"
 // @Param song body models.Song true "Updated song data"
 // @Success 200 {object} map[string]string "Song updated successfully"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 500 {object} map[string]string "Failed to update song"
+// @Failure 400 {object} problemDetails "Invalid request body"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 409 {object} problemDetails "Conflicting song data"
+// @Failure 412 {object} problemDetails "Song was modified since the ETag was issued"
+// @Failure 422 {object} problemDetails "Validation failed"
+// @Failure 428 {object} problemDetails "If-Match header is missing"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails "Failed to update song"
+// @Failure 504 {object} problemDetails "Database query timed out"
 // @Router /songs/{id} [put]
-// Обновление информации о песне
+// Замена песни целиком
 func (h *Handler) UpdateSong(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.Warnf("Invalid song ID for update: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid song ID"})
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	precondition, ok := requirePrecondition(c)
+	if !ok {
 		return
 	}
 
 	var song models.Song
 	if err := c.ShouldBindJSON(&song); err != nil {
-		logrus.Warnf("Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		bindingError(c, err)
 		return
 	}
 
@@ -219,42 +507,154 @@ func (h *Handler) UpdateSong(c *gin.Context) {
 		"song_id":     id,
 		"group_name":  song.GroupName,
 		"song_name":   song.SongName,
-		"releaseDate": song.ReleaseDate,
-	}).Info("Updating song")
+		"releaseDate": song.ReleaseDate.String(),
+	}).Info("Replacing song")
 
-	if err := h.services.UpdateSong(id, song); err != nil {
-		logrus.Errorf("Failed to update song: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update song"})
+	version, err := h.services.UpdateSong(c.Request.Context(), id, song, precondition)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
 	logrus.Infof("Song with ID %d updated successfully", id)
+	c.Header("ETag", songETag(version))
+	c.JSON(http.StatusOK, gin.H{"message": "Song updated successfully"})
+}
+
+// PatchSong godoc
+// @Summary Patch a song
+// @Description Partially update a song with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902).
+// @Description null or a removed field clears it; group and song cannot be cleared.
+// @Tags songs
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param If-Match header string true "Current song ETag"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Success 200 {object} map[string]string "Song updated successfully"
+// @Failure 400 {object} problemDetails "Invalid request body"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 409 {object} problemDetails "JSON Patch test operation failed"
+// @Failure 412 {object} problemDetails "Song was modified since the ETag was issued"
+// @Failure 415 {object} problemDetails "Unsupported patch format"
+// @Failure 422 {object} problemDetails "Invalid patch or validation failed"
+// @Failure 428 {object} problemDetails "If-Match header is missing"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails "Failed to update song"
+// @Failure 504 {object} problemDetails "Database query timed out"
+// @Router /songs/{id} [patch]
+// Частичное изменение песни
+func (h *Handler) PatchSong(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.Warnf("Invalid song ID for patch: %v", err)
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	precondition, ok := requirePrecondition(c)
+	if !ok {
+		return
+	}
+
+	// Формат патча определяется по Content-Type; обычный JSON считается merge patch
+	var format string
+	switch c.ContentType() {
+	case patch.MergePatchContentType, gin.MIMEJSON:
+		format = models.PatchMerge
+	case patch.JSONPatchContentType:
+		format = models.PatchJSON
+	default:
+		abortWithProblem(c, problemDetails{
+			Status: http.StatusUnsupportedMediaType,
+			Detail: fmt.Sprintf("Content-Type must be %s or %s", patch.MergePatchContentType, patch.JSONPatchContentType),
+		})
+		return
+	}
+
+	document, err := c.GetRawData()
+	if err != nil || len(document) == 0 {
+		newBadRequest(c, "Request body must contain a patch")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": id,
+		"format":  format,
+		"size":    len(document),
+	}).Info("Patching song")
+
+	version, err := h.services.PatchSong(c.Request.Context(), id, models.SongPatch{Format: format, Document: document}, precondition)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.Infof("Song with ID %d patched successfully", id)
+	c.Header("ETag", songETag(version))
 	c.JSON(http.StatusOK, gin.H{"message": "Song updated successfully"})
 }
 
 // DeleteSong godoc
 // @Summary Delete a song
-// @Description Remove a song from the database by its ID
+// @Description Move a song to the trash. With hard=true the song is removed permanently (also from the trash).
 // @Tags songs
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Song ID"
+// @Param hard query bool false "Delete permanently"
+// @Param If-Match header string true "Current song ETag"
 // @Success 200 {object} map[string]string "Song deleted successfully"
-// @Failure 500 {object} map[string]string "Failed to delete song"
+// @Failure 400 {object} problemDetails "Invalid song ID"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required, admin for hard=true"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 412 {object} problemDetails "Song was modified since the ETag was issued"
+// @Failure 428 {object} problemDetails "If-Match header is missing"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails "Failed to delete song"
+// @Failure 504 {object} problemDetails "Database query timed out"
 // @Router /songs/{id} [delete]
 // Удаление песни
 func (h *Handler) DeleteSong(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.Warnf("Invalid song ID for deletion: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid song ID"})
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	hard, err := queryBool(c, "hard")
+	if err != nil {
+		newBadRequest(c, "Invalid hard value")
+		return
+	}
+
+	// Безвозвратное удаление проверяется здесь, а не на маршруте: без него удалить песню может editor
+	permanently := hard != nil && *hard
+	if permanently && !authorize(c, adminOnly) {
 		return
 	}
 
-	logrus.Infof("Deleting song with ID %d", id)
-	if err := h.services.DeleteSong(id); err != nil {
-		logrus.Errorf("Failed to delete song: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete song"})
+	precondition, ok := requirePrecondition(c)
+	if !ok {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": id,
+		"hard":    permanently,
+	}).Info("Deleting song")
+	if err := h.services.DeleteSong(c.Request.Context(), id, permanently, precondition); err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
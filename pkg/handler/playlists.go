@@ -0,0 +1,500 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/playlistfile"
+)
+
+// maxPlaylistImportSize — максимальный размер импортируемого файла плейлиста
+const maxPlaylistImportSize = 8 << 20
+
+// playlistContentTypes — формат файла плейлиста по Content-Type тела запроса
+var playlistContentTypes = map[string]string{
+	"audio/x-mpegurl":               models.PlaylistM3U8,
+	"audio/mpegurl":                 models.PlaylistM3U8,
+	"application/vnd.apple.mpegurl": models.PlaylistM3U8,
+	"application/xspf+xml":          models.PlaylistXSPF,
+	"audio/x-scpls":                 models.PlaylistPLS,
+}
+
+// playlistExtensions — формат файла плейлиста по расширению файла из multipart-формы
+var playlistExtensions = map[string]string{
+	".m3u8": models.PlaylistM3U8,
+	".m3u":  models.PlaylistM3U8,
+	".xspf": models.PlaylistXSPF,
+	".pls":  models.PlaylistPLS,
+}
+
+// AddPlaylist godoc
+// @Summary Create a playlist
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param playlist body models.Playlist true "Playlist JSON (only name is used)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 422 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/ [post]
+// Создание плейлиста
+func (h *Handler) AddPlaylist(c *gin.Context) {
+	var playlist models.Playlist
+	if err := c.ShouldBindJSON(&playlist); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"name": playlist.Name,
+	}).Info("Adding new playlist")
+
+	id, err := h.services.AddPlaylist(c.Request.Context(), playlist.Name)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.WithField("playlist_id", id).Info("Playlist added successfully")
+	c.JSON(http.StatusCreated, gin.H{"message": "Playlist added successfully", "id": id})
+}
+
+// GetPlaylists godoc
+// @Summary Get playlists
+// @Description Get a page of playlists, recently changed first, with entry count and total duration
+// @Tags playlists
+// @Produce json
+// @Param name query string false "Name contains"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {object} models.PlaylistPage
+// @Failure 400 {object} problemDetails "Invalid page or limit"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/ [get]
+// Получение списка плейлистов
+func (h *Handler) GetPlaylists(c *gin.Context) {
+	page, limit, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	filter := models.PlaylistFilter{
+		Name:  c.Query("name"),
+		Page:  page,
+		Limit: limit,
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"name":  filter.Name,
+		"page":  page,
+		"limit": limit,
+	}).Info("Fetching playlists")
+
+	playlistPage, err := h.services.GetPlaylists(c.Request.Context(), filter)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	playlistPage.Links = pageLinks(c, playlistPage.Page, playlistPage.TotalPages)
+
+	c.JSON(http.StatusOK, playlistPage)
+}
+
+// GetPlaylist godoc
+// @Summary Get playlist by ID
+// @Description Get a playlist with its songs in order and the total duration in seconds.
+// @Description Songs without a known duration are not included in the total and are counted in unknownDurations.
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {object} problemDetails "Invalid playlist ID"
+// @Failure 404 {object} problemDetails "Playlist not found"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/{id} [get]
+// Получение плейлиста с песнями
+func (h *Handler) GetPlaylist(c *gin.Context) {
+	id, ok := playlistID(c)
+	if !ok {
+		return
+	}
+
+	playlist, err := h.services.GetPlaylist(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// RenamePlaylist godoc
+// @Summary Rename a playlist
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Playlist ID"
+// @Param playlist body models.Playlist true "Playlist JSON (only name is used)"
+// @Success 200 {object} map[string]string "Playlist updated successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Playlist not found"
+// @Failure 422 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/{id} [put]
+// Переименование плейлиста
+func (h *Handler) RenamePlaylist(c *gin.Context) {
+	id, ok := playlistID(c)
+	if !ok {
+		return
+	}
+
+	var playlist models.Playlist
+	if err := c.ShouldBindJSON(&playlist); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"name":        playlist.Name,
+	}).Info("Renaming playlist")
+
+	if err := h.services.RenamePlaylist(c.Request.Context(), id, playlist.Name); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Playlist updated successfully"})
+}
+
+// DeletePlaylist godoc
+// @Summary Delete a playlist
+// @Description Delete a playlist and its entries; the songs themselves are kept
+// @Tags playlists
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} map[string]string "Playlist deleted successfully"
+// @Failure 400 {object} problemDetails "Invalid playlist ID"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Playlist not found"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/{id} [delete]
+// Удаление плейлиста
+func (h *Handler) DeletePlaylist(c *gin.Context) {
+	id, ok := playlistID(c)
+	if !ok {
+		return
+	}
+
+	logrus.Infof("Deleting playlist with ID %d", id)
+	if err := h.services.DeletePlaylist(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Playlist deleted successfully"})
+}
+
+// AddPlaylistEntry godoc
+// @Summary Add a song to a playlist
+// @Description Insert a song at the given position (1-based); the following entries move down by one.
+// @Description Without position the song is appended. The same song may be added several times.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Playlist ID"
+// @Param entry body models.NewPlaylistEntry true "Song and position"
+// @Success 201 {object} map[string]interface{} "Entry ID and position"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Playlist not found"
+// @Failure 422 {object} problemDetails "Unknown song or position out of range"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/{id}/entries [post]
+// Добавление песни в плейлист
+func (h *Handler) AddPlaylistEntry(c *gin.Context) {
+	id, ok := playlistID(c)
+	if !ok {
+		return
+	}
+
+	var entry models.NewPlaylistEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"song_id":     entry.SongID,
+		"position":    entry.Position,
+	}).Info("Adding song to playlist")
+
+	entryID, position, err := h.services.AddPlaylistEntry(c.Request.Context(), id, entry)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Song added to playlist", "id": entryID, "position": position})
+}
+
+// MovePlaylistEntry godoc
+// @Summary Move a playlist entry
+// @Description Move an entry to another position; the entries in between shift towards the freed place
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Playlist ID"
+// @Param entryId path int true "Entry ID"
+// @Param move body models.PlaylistEntryMove true "New position"
+// @Success 200 {object} map[string]string "Entry moved successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Playlist or entry not found"
+// @Failure 422 {object} problemDetails "Position out of range"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/{id}/entries/{entryId}/position [put]
+// Перемещение песни в плейлисте
+func (h *Handler) MovePlaylistEntry(c *gin.Context) {
+	id, entryID, ok := playlistEntryID(c)
+	if !ok {
+		return
+	}
+
+	var move models.PlaylistEntryMove
+	if err := c.ShouldBindJSON(&move); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"entry_id":    entryID,
+		"position":    move.Position,
+	}).Info("Moving playlist entry")
+
+	if err := h.services.MovePlaylistEntry(c.Request.Context(), id, entryID, move.Position); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Entry moved successfully"})
+}
+
+// RemovePlaylistEntry godoc
+// @Summary Remove a song from a playlist
+// @Description Remove an entry; the following entries move up by one
+// @Tags playlists
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Playlist ID"
+// @Param entryId path int true "Entry ID"
+// @Success 200 {object} map[string]string "Entry removed successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Playlist or entry not found"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/{id}/entries/{entryId} [delete]
+// Удаление песни из плейлиста
+func (h *Handler) RemovePlaylistEntry(c *gin.Context) {
+	id, entryID, ok := playlistEntryID(c)
+	if !ok {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"entry_id":    entryID,
+	}).Info("Removing playlist entry")
+
+	if err := h.services.RemovePlaylistEntry(c.Request.Context(), id, entryID); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Entry removed successfully"})
+}
+
+// ExportPlaylist godoc
+// @Summary Export a playlist
+// @Description Download a playlist for desktop players: extended M3U8 (#EXTINF with "Artist - Title"), XSPF or PLS.
+// @Description Songs in the trash are left out; M3U8 and PLS list files, so songs without a link are left out too.
+// @Tags playlists
+// @Produce audio/x-mpegurl,application/xspf+xml,audio/x-scpls
+// @Param id path int true "Playlist ID"
+// @Param format query string false "m3u8 (default), xspf or pls"
+// @Success 200 {file} file
+// @Failure 400 {object} problemDetails "Invalid playlist ID"
+// @Failure 404 {object} problemDetails "Playlist not found"
+// @Failure 422 {object} problemDetails "Unknown format"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/{id}/export [get]
+// Выгрузка плейлиста в файл для плееров
+func (h *Handler) ExportPlaylist(c *gin.Context) {
+	id, ok := playlistID(c)
+	if !ok {
+		return
+	}
+	format := strings.ToLower(c.DefaultQuery("format", models.PlaylistM3U8))
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"format":      format,
+	}).Info("Exporting playlist")
+
+	contentType := playlistfile.ContentTypes[format]
+	streamDownload(c, contentType, fmt.Sprintf("playlist-%d.%s", id, format), func(w io.Writer) (int, error) {
+		return h.services.ExportPlaylist(c.Request.Context(), id, format, w)
+	})
+}
+
+// ImportPlaylist godoc
+// @Summary Import a playlist
+// @Description Create a playlist from an M3U/M3U8, XSPF or PLS file. Entries are matched to songs in the catalog
+// @Description by artist and title (case-insensitive), falling back to the link; songs in the trash are not matched.
+// @Description Unmatched entries are skipped and listed in the report with their line in the file.
+// @Description The body can also be sent as multipart form field "file".
+// @Tags playlists
+// @Accept audio/x-mpegurl,application/xspf+xml,audio/x-scpls,mpfd
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param format query string false "m3u8, xspf or pls; by default taken from Content-Type or the file extension"
+// @Param name query string false "Playlist name; by default the title from the file or the file name"
+// @Param file formData file false "Playlist file"
+// @Success 201 {object} models.PlaylistImportReport
+// @Failure 400 {object} problemDetails "Unreadable body"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Admin role or songs:write and import scopes required"
+// @Failure 413 {object} problemDetails "File too large"
+// @Failure 415 {object} problemDetails "Unknown file format"
+// @Failure 422 {object} problemDetails "Malformed file or invalid name"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /playlists/import [post]
+// Импорт плейлиста из файла
+func (h *Handler) ImportPlaylist(c *gin.Context) {
+	options := models.PlaylistImportOptions{
+		Format: strings.ToLower(c.Query("format")),
+		Name:   c.Query("name"),
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPlaylistImportSize)
+	body, filename, err := importBody(c)
+	if err != nil {
+		logrus.Warnf("Failed to read playlist upload: %v", err)
+		newBadRequest(c, "Failed to read playlist file")
+		return
+	}
+	options.Filename = filename
+
+	if options.Format == "" {
+		var ok bool
+		if filename != "" {
+			options.Format, ok = playlistExtensions[strings.ToLower(filepath.Ext(filename))]
+		} else {
+			options.Format, ok = playlistContentTypes[c.ContentType()]
+		}
+		if !ok {
+			abortWithProblem(c, problemDetails{
+				Status: http.StatusUnsupportedMediaType,
+				Detail: "Send audio/x-mpegurl, application/xspf+xml or audio/x-scpls, or pass the format parameter",
+			})
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"format":   options.Format,
+		"name":     options.Name,
+		"filename": filename,
+	}).Info("Importing playlist")
+
+	report, err := h.services.ImportPlaylist(c.Request.Context(), body, options)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			abortWithProblem(c, problemDetails{Status: http.StatusRequestEntityTooLarge, Detail: "Playlist file must not exceed 8 MiB"})
+			return
+		}
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": report.PlaylistID,
+		"total":       report.Total,
+		"matched":     report.Matched,
+	}).Info("Playlist imported successfully")
+	c.JSON(http.StatusCreated, report)
+}
+
+func playlistID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.Warnf("Invalid playlist ID: %v", err)
+		newBadRequest(c, "Invalid playlist ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func playlistEntryID(c *gin.Context) (int, int, bool) {
+	id, ok := playlistID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	entryID, err := strconv.Atoi(c.Param("entryId"))
+	if err != nil {
+		logrus.Warnf("Invalid playlist entry ID: %v", err)
+		newBadRequest(c, "Invalid entry ID")
+		return 0, 0, false
+	}
+	return id, entryID, true
+}
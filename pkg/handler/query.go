@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// parseSort разбирает `sort=-releaseDate,group`: минус перед полем — сортировка по убыванию
+func parseSort(raw string) []models.SortField {
+	if raw == "" {
+		return nil
+	}
+
+	fields := make([]models.SortField, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := models.SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = models.SortField{Field: part[1:], Desc: true}
+		} else if strings.HasPrefix(part, "+") {
+			field.Field = part[1:]
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// queryBool возвращает nil, если параметр не передан
+func queryBool(c *gin.Context, key string) (*bool, error) {
+	raw, ok := c.GetQuery(key)
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// queryPage читает page и limit (по умолчанию 1 и 10). Нечисловое значение — 400, допустимый диапазон
+// проверяет сервис. При ошибке ответ уже отправлен и возвращается false.
+func queryPage(c *gin.Context) (page, limit int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		newBadRequest(c, "Invalid page value")
+		return 0, 0, false
+	}
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		newBadRequest(c, "Invalid limit value")
+		return 0, 0, false
+	}
+	return page, limit, true
+}
+
+// queryList собирает значения параметра, переданного несколько раз или через запятую:
+// `genre=rock&genre=jazz` и `genre=rock,jazz` равнозначны
+func queryList(c *gin.Context, key string) []string {
+	values := make([]string, 0)
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// pageLinks строит ссылки на текущую, следующую и предыдущую страницы с сохранением остальных параметров
+func pageLinks(c *gin.Context, page, totalPages int) models.PageLinks {
+	link := func(page int) string {
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set("page", strconv.Itoa(page))
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+
+	links := models.PageLinks{Self: link(page)}
+	if page < totalPages {
+		links.Next = link(page + 1)
+	}
+	if page > 1 {
+		links.Prev = link(min(page-1, max(totalPages, 1)))
+	}
+	return links
+}
+
+// cursorLinks строит ссылки для keyset-пагинации
+func cursorLinks(c *gin.Context, nextCursor string) models.CursorPageLinks {
+	links := models.CursorPageLinks{Self: c.Request.URL.RequestURI()}
+	if nextCursor != "" {
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set("cursor", nextCursor)
+		query.Del("page")
+		u.RawQuery = query.Encode()
+		links.Next = u.RequestURI()
+	}
+	return links
+}
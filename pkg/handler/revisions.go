@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// GetRevisions godoc
+// @Summary List song revisions
+// @Description Change history of a song, newest first. Available for deleted songs too.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {object} models.RevisionPage
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} problemDetails "Song has no revisions"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/revisions [get]
+// Получение истории изменений песни
+func (h *Handler) GetRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	page, limit, ok := queryPage(c)
+	if !ok {
+		return
+	}
+
+	revisionPage, err := h.services.GetRevisions(c.Request.Context(), models.RevisionFilter{
+		SongID: id,
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	revisionPage.Links = pageLinks(c, revisionPage.Page, revisionPage.TotalPages)
+
+	c.JSON(http.StatusOK, revisionPage)
+}
+
+// GetRevision godoc
+// @Summary Get a song revision
+// @Description Revision with the snapshot of song fields it produced
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.SongRevision
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} problemDetails "Revision not found"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/revisions/{rev} [get]
+// Получение ревизии песни
+func (h *Handler) GetRevision(c *gin.Context) {
+	id, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.services.GetRevision(c.Request.Context(), id, revision)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DiffRevisions godoc
+// @Summary Compare two revisions
+// @Description Changed fields between two revisions; text and lyrics are also compared line by line
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param from query int true "Older revision"
+// @Param to query int true "Newer revision"
+// @Success 200 {object} models.RevisionDiff
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} problemDetails "Revision not found"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/revisions/diff [get]
+// Сравнение двух ревизий песни
+func (h *Handler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		newBadRequest(c, "Query parameter from must be a revision number")
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		newBadRequest(c, "Query parameter to must be a revision number")
+		return
+	}
+
+	diff, err := h.services.DiffRevisions(c.Request.Context(), id, from, to)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision godoc
+// @Summary Restore a song revision
+// @Description Roll the song back to the state of a revision. A deleted song is recreated with the same ID.
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} map[string]string "Song restored"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Revision not found"
+// @Failure 409 {object} problemDetails
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/revisions/{rev}/restore [post]
+// Откат песни к ревизии
+func (h *Handler) RestoreRevision(c *gin.Context) {
+	id, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  id,
+		"revision": revision,
+	}).Info("Restoring song revision")
+
+	if err := h.services.RestoreRevision(c.Request.Context(), id, revision); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Song restored"})
+}
+
+func revisionParams(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return 0, 0, false
+	}
+	revision, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		newBadRequest(c, "Invalid revision number")
+		return 0, 0, false
+	}
+	return id, revision, true
+}
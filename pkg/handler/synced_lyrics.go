@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+)
+
+// maxLRCSize — максимальный размер загружаемого LRC-файла
+const maxLRCSize = 1 << 20
+
+const mimeWebVTT = "text/vtt"
+
+// SetSyncedLyrics godoc
+// @Summary Upload synchronized lyrics
+// @Description Upload an LRC or enhanced LRC (word-level <mm:ss.xx> tags) file for a song, replacing the previous one.
+// @Description The file can be sent as the raw request body or as multipart form field "file".
+// @Description Timestamps must not go backwards; errors are reported per file line.
+// @Tags songs
+// @Accept plain,mpfd
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param file formData file false "LRC file"
+// @Success 200 {object} map[string]interface{} "Number of stored lines"
+// @Failure 400 {object} problemDetails "Invalid song ID or unreadable body"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 413 {object} problemDetails "File too large"
+// @Failure 422 {object} problemDetails "LRC errors by line"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/lyrics/synced [put]
+// Загрузка синхронизированного текста
+func (h *Handler) SetSyncedLyrics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	lrc, err := readLRC(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			abortWithProblem(c, problemDetails{Status: http.StatusRequestEntityTooLarge, Detail: "LRC file must not exceed 1 MiB"})
+			return
+		}
+		logrus.Warnf("Failed to read LRC upload: %v", err)
+		newBadRequest(c, "Failed to read LRC file")
+		return
+	}
+	if !utf8.ValidString(lrc) {
+		newErrorResponse(c, apperror.NewValidationError("file", "must be UTF-8 encoded"))
+		return
+	}
+
+	synced, err := h.services.SetSyncedLyrics(c.Request.Context(), id, lrc)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": id,
+		"lines":   len(synced.Lines),
+	}).Info("Synced lyrics uploaded")
+	c.JSON(http.StatusOK, gin.H{"message": "Synced lyrics saved", "lines": len(synced.Lines)})
+}
+
+// readLRC читает файл из поля multipart-формы "file" или, если это не форма, из тела запроса
+func readLRC(c *gin.Context) (string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxLRCSize)
+
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), gin.MIMEMultipartPOSTForm) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return "", err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetSyncedLyrics godoc
+// @Summary Get synchronized lyrics
+// @Description Get the synchronized lyrics of a song as LRC (default), JSON or WebVTT.
+// @Description The format is taken from the format parameter or, if absent, from the Accept header.
+// @Tags songs
+// @Produce plain,json,text/vtt
+// @Param id path int true "Song ID"
+// @Param format query string false "lrc (default), json or vtt"
+// @Success 200 {object} lyrics.SyncedLyrics
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} problemDetails "Song has no synced lyrics"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/lyrics/synced [get]
+// Получение синхронизированного текста
+func (h *Handler) GetSyncedLyrics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		switch c.NegotiateFormat(gin.MIMEPlain, gin.MIMEJSON, mimeWebVTT) {
+		case gin.MIMEJSON:
+			format = "json"
+		case mimeWebVTT:
+			format = "vtt"
+		default:
+			format = "lrc"
+		}
+	}
+	if format != "lrc" && format != "json" && format != "vtt" {
+		newBadRequest(c, "Invalid format value, expected lrc, json or vtt")
+		return
+	}
+
+	synced, err := h.services.GetSyncedLyrics(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	switch format {
+	case "json":
+		c.JSON(http.StatusOK, synced)
+	case "vtt":
+		c.Data(http.StatusOK, mimeWebVTT+"; charset=utf-8", []byte(lyrics.FormatWebVTT(synced)))
+	default:
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(lyrics.FormatLRC(synced)))
+	}
+}
+
+// DeleteSyncedLyrics godoc
+// @Summary Delete synchronized lyrics
+// @Tags songs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Success 200 {object} map[string]string "Synced lyrics deleted"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song has no synced lyrics"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/lyrics/synced [delete]
+// Удаление синхронизированного текста
+func (h *Handler) DeleteSyncedLyrics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	if err := h.services.DeleteSyncedLyrics(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Synced lyrics deleted"})
+}
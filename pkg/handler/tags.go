@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GetTags godoc
+// @Summary Get tags
+// @Description Get tags in use, most popular first
+// @Tags tags
+// @Produce json
+// @Success 200 {array} models.Tag
+// @Failure 500 {object} problemDetails
+// @Router /tags/ [get]
+// Получение списка тегов
+func (h *Handler) GetTags(c *gin.Context) {
+	tags, err := h.services.GetTags(c.Request.Context())
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// AttachSongTag godoc
+// @Summary Tag a song
+// @Description Add a free-form tag to a song; the tag is created on first use
+// @Tags songs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param tag path string true "Tag"
+// @Success 200 {object} map[string]string "Tag attached"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 422 {object} problemDetails "Invalid tag"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/tags/{tag} [put]
+// Добавление тега к песне
+func (h *Handler) AttachSongTag(c *gin.Context) {
+	songID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+	tag := c.Param("tag")
+
+	if err := h.services.AttachTag(c.Request.Context(), songID, tag); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.Infof("Tag %q attached to song %d", tag, songID)
+	c.JSON(http.StatusOK, gin.H{"message": "Tag attached"})
+}
+
+// DetachSongTag godoc
+// @Summary Remove a tag from a song
+// @Tags songs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param tag path string true "Tag"
+// @Success 200 {object} map[string]string "Tag detached"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Tag is not attached to the song"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/tags/{tag} [delete]
+// Удаление тега у песни
+func (h *Handler) DetachSongTag(c *gin.Context) {
+	songID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+	tag := c.Param("tag")
+
+	if err := h.services.DetachTag(c.Request.Context(), songID, tag); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	logrus.Infof("Tag %q detached from song %d", tag, songID)
+	c.JSON(http.StatusOK, gin.H{"message": "Tag detached"})
+}
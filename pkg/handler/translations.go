@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// SetTranslation godoc
+// @Summary Add or replace a translation
+// @Description Attach a translation of the song text keyed by BCP 47 language tag
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param lang path string true "BCP 47 language tag"
+// @Param translation body models.Translation true "Translation (only text is used)"
+// @Success 200 {object} map[string]string "Translation saved"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 422 {object} problemDetails "Invalid language tag or blank text"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/translations/{lang} [put]
+// Добавление перевода текста
+func (h *Handler) SetTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	var translation models.Translation
+	if err := c.ShouldBindJSON(&translation); err != nil {
+		bindingError(c, err)
+		return
+	}
+	translation.Language = c.Param("lang")
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  id,
+		"language": translation.Language,
+	}).Info("Saving translation")
+
+	if err := h.services.SetTranslation(c.Request.Context(), id, translation); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation saved"})
+}
+
+// GetTranslations godoc
+// @Summary List translations
+// @Description List languages the song text is translated to
+// @Tags songs
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {array} models.Translation
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/translations [get]
+// Список переводов песни
+func (h *Handler) GetTranslations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	translations, err := h.services.GetTranslations(c.Request.Context(), id)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// DeleteTranslation godoc
+// @Summary Delete a translation
+// @Tags songs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Param lang path string true "BCP 47 language tag"
+// @Success 200 {object} map[string]string "Translation deleted"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Translation not found"
+// @Failure 422 {object} problemDetails "Invalid language tag"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Router /songs/{id}/translations/{lang} [delete]
+// Удаление перевода
+func (h *Handler) DeleteTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	if err := h.services.DeleteTranslation(c.Request.Context(), id, c.Param("lang")); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted"})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// GetTrash godoc
+// @Summary List deleted songs
+// @Description Songs in the trash, most recently deleted first. Supports the same filters, sorting and pagination as GET /songs/.
+// @Tags trash
+// @Produce json
+// @Param group query string false "Group name contains"
+// @Param song query string false "Song name contains"
+// @Param sort query string false "Comma-separated sort fields (id, group, song, releaseDate, deletedAt), '-' for descending"
+// @Param fields query string false "Comma-separated song fields to return, e.g. group,song,releaseDate (id is always returned)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Param cursor query string false "Keyset pagination cursor"
+// @Success 200 {object} models.SongPage "Offset pagination (with cursor the body is models.SongCursorPage)"
+// @Failure 400 {object} problemDetails
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /trash [get]
+// Получение списка песен в корзине
+func (h *Handler) GetTrash(c *gin.Context) {
+	filter, ok := songFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	filter.Trashed = true
+	if len(filter.Sort) == 0 {
+		filter.Sort = []models.SortField{{Field: "deletedAt", Desc: true}}
+	}
+
+	h.listSongs(c, filter)
+}
+
+// RestoreSong godoc
+// @Summary Restore a deleted song
+// @Description Move a song out of the trash
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Song ID"
+// @Success 200 {object} map[string]string "Song restored"
+// @Failure 400 {object} problemDetails "Invalid song ID"
+// @Failure 401 {object} problemDetails "Missing or invalid token or API key"
+// @Failure 403 {object} problemDetails "Editor role or songs:write scope required"
+// @Failure 404 {object} problemDetails "Song not found"
+// @Failure 409 {object} problemDetails "Song is not in the trash"
+// @Failure 429 {object} problemDetails "API key rate limit exceeded"
+// @Failure 500 {object} problemDetails
+// @Failure 504 {object} problemDetails
+// @Router /songs/{id}/restore [post]
+// Восстановление песни из корзины
+func (h *Handler) RestoreSong(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newBadRequest(c, "Invalid song ID")
+		return
+	}
+
+	logrus.WithField("song_id", id).Info("Restoring song from trash")
+
+	if err := h.services.RestoreSong(c.Request.Context(), id); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Song restored"})
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// AddUser godoc
+// @Summary Create a user
+// @Description Create a user with a role (viewer by default). Usernames are stored in lower case;
+// @Description passwords must be 8 to 72 bytes long and are stored as bcrypt hashes.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body models.NewUser true "User"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token"
+// @Failure 403 {object} problemDetails "Admin role required"
+// @Failure 409 {object} problemDetails "Username is taken"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /users/ [post]
+// Создание пользователя
+func (h *Handler) AddUser(c *gin.Context) {
+	var user models.NewUser
+	if err := c.ShouldBindJSON(&user); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username": user.Username,
+		"role":     user.Role,
+	}).Info("Adding new user")
+
+	id, err := h.services.CreateUser(c.Request.Context(), user)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "User added successfully", "id": id})
+}
+
+// GetUsers godoc
+// @Summary Get users
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.User
+// @Failure 401 {object} problemDetails "Missing or invalid token"
+// @Failure 403 {object} problemDetails "Admin role required"
+// @Failure 500 {object} problemDetails
+// @Router /users/ [get]
+// Получение списка пользователей
+func (h *Handler) GetUsers(c *gin.Context) {
+	users, err := h.services.GetUsers(c.Request.Context())
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// SetUserRole godoc
+// @Summary Change a user's role
+// @Description Change the role; the user's refresh tokens are revoked, access tokens expire on their own
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param role body models.UserRoleChange true "New role: viewer, editor or admin"
+// @Success 200 {object} map[string]string "Role changed successfully"
+// @Failure 400 {object} problemDetails
+// @Failure 401 {object} problemDetails "Missing or invalid token"
+// @Failure 403 {object} problemDetails "Admin role required"
+// @Failure 404 {object} problemDetails "User not found"
+// @Failure 422 {object} problemDetails
+// @Failure 500 {object} problemDetails
+// @Router /users/{id}/role [put]
+// Смена роли пользователя
+func (h *Handler) SetUserRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.Warnf("Invalid user ID: %v", err)
+		newBadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var change models.UserRoleChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		bindingError(c, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": id,
+		"role":    change.Role,
+	}).Info("Changing user role")
+
+	if err := h.services.SetUserRole(c.Request.Context(), id, change.Role); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role changed successfully"})
+}
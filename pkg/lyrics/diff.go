@@ -0,0 +1,111 @@
+package lyrics
+
+import "strings"
+
+// Операции построчного сравнения
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffCells ограничивает размер таблицы LCS; при большем объёме изменившаяся середина
+// отдаётся целиком как удаление старых строк и вставка новых
+const maxDiffCells = 4_000_000
+
+// DiffLine — строка построчного сравнения. OldLine и NewLine — номера строк (с 1)
+// в старом и новом тексте; у вставленной строки нет старого номера, у удалённой — нового.
+type DiffLine struct {
+	Op      string `json:"op" example:"insert"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine,omitempty"`
+	NewLine int    `json:"newLine,omitempty"`
+}
+
+// Lines разбивает текст на строки; у пустого текста строк нет
+func Lines(text string) []string {
+	text = strings.TrimRight(Normalize(text), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// DiffLines сравнивает два текста построчно через наибольшую общую подпоследовательность
+func DiffLines(old, new []string) []DiffLine {
+	// Общие начало и конец не участвуют в LCS — обычно правка затрагивает пару строк
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix &&
+		old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]DiffLine, 0, len(old)+len(new))
+	for i := 0; i < prefix; i++ {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: old[i], OldLine: i + 1, NewLine: i + 1})
+	}
+
+	diff = appendMiddle(diff, old[prefix:len(old)-suffix], new[prefix:len(new)-suffix], prefix, prefix)
+
+	for i := 0; i < suffix; i++ {
+		oi, ni := len(old)-suffix+i, len(new)-suffix+i
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: old[oi], OldLine: oi + 1, NewLine: ni + 1})
+	}
+	return diff
+}
+
+// appendMiddle сравнивает изменившуюся часть; oldOffset и newOffset — её начало в исходных текстах
+func appendMiddle(diff []DiffLine, old, new []string, oldOffset, newOffset int) []DiffLine {
+	n, m := len(old), len(new)
+	if n == 0 || m == 0 || n*m > maxDiffCells {
+		for i, line := range old {
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: line, OldLine: oldOffset + i + 1})
+		}
+		for j, line := range new {
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: line, NewLine: newOffset + j + 1})
+		}
+		return diff
+	}
+
+	// lcs[i][j] — длина LCS суффиксов old[i:] и new[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case old[i] == new[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: old[i], OldLine: oldOffset + i + 1, NewLine: newOffset + j + 1})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: old[i], OldLine: oldOffset + i + 1})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: new[j], NewLine: newOffset + j + 1})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		diff = append(diff, DiffLine{Op: DiffDelete, Text: old[i], OldLine: oldOffset + i + 1})
+	}
+	for ; j < m; j++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: new[j], NewLine: newOffset + j + 1})
+	}
+	return diff
+}
@@ -0,0 +1,80 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"\n\n", nil},
+		{"one\r\ntwo\n", []string{"one", "two"}},
+		{`one\ntwo`, []string{"one", "two"}},
+		{"one\n\nthree", []string{"one", "", "three"}},
+	}
+
+	for _, tt := range tests {
+		if got := Lines(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lines(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new []string
+		want     []DiffLine
+	}{
+		{
+			name: "equal",
+			old:  []string{"a", "b"},
+			new:  []string{"a", "b"},
+			want: []DiffLine{
+				{Op: DiffEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: DiffEqual, Text: "b", OldLine: 2, NewLine: 2},
+			},
+		},
+		{
+			name: "changed middle line",
+			old:  []string{"a", "b", "c"},
+			new:  []string{"a", "B", "c"},
+			want: []DiffLine{
+				{Op: DiffEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: DiffDelete, Text: "b", OldLine: 2},
+				{Op: DiffInsert, Text: "B", NewLine: 2},
+				{Op: DiffEqual, Text: "c", OldLine: 3, NewLine: 3},
+			},
+		},
+		{
+			name: "insert and delete around common lines",
+			old:  []string{"x", "a", "b", "y"},
+			new:  []string{"a", "new", "b"},
+			want: []DiffLine{
+				{Op: DiffDelete, Text: "x", OldLine: 1},
+				{Op: DiffEqual, Text: "a", OldLine: 2, NewLine: 1},
+				{Op: DiffInsert, Text: "new", NewLine: 2},
+				{Op: DiffEqual, Text: "b", OldLine: 3, NewLine: 3},
+				{Op: DiffDelete, Text: "y", OldLine: 4},
+			},
+		},
+		{
+			name: "from empty text",
+			old:  nil,
+			new:  []string{"a"},
+			want: []DiffLine{{Op: DiffInsert, Text: "a", NewLine: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
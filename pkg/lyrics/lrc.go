@@ -0,0 +1,327 @@
+package lyrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SyncedLyrics — текст с временными метками строк (LRC). Tags — метаданные файла ([ar:], [ti:], ...).
+type SyncedLyrics struct {
+	Tags  map[string]string `json:"tags,omitempty"`
+	Lines []SyncedLine      `json:"lines"`
+}
+
+// SyncedLine — строка, которая начинается в StartMs миллисекунд от начала трека.
+// Words заполняется для enhanced LRC с пословными метками.
+type SyncedLine struct {
+	StartMs int64        `json:"startMs"`
+	Text    string       `json:"text"`
+	Words   []SyncedWord `json:"words,omitempty"`
+}
+
+type SyncedWord struct {
+	StartMs int64  `json:"startMs"`
+	Text    string `json:"text"`
+}
+
+// LineError — ошибка в конкретной строке LRC-файла (нумерация с 1)
+type LineError struct {
+	Line    int
+	Message string
+}
+
+// ParseError собирает все ошибки файла, чтобы клиент мог исправить их за один раз
+type ParseError struct {
+	Lines []LineError
+}
+
+func (e *ParseError) Error() string {
+	messages := make([]string, 0, len(e.Lines))
+	for _, line := range e.Lines {
+		messages = append(messages, fmt.Sprintf("line %d: %s", line.Line, line.Message))
+	}
+	return "invalid LRC: " + strings.Join(messages, "; ")
+}
+
+var (
+	// timeTagPattern — метка времени строки: [mm:ss], [mm:ss.xx], [mm:ss.xxx]
+	timeTagPattern = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	// metaTagPattern — метаданные: [ar:Исполнитель], [offset:+250]
+	metaTagPattern = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+	// wordTagPattern — пословная метка enhanced LRC: <mm:ss.xx>
+	wordTagPattern = regexp.MustCompile(`<(\d+):(\d{1,2})(?:[.:](\d{1,3}))?>`)
+)
+
+// ParseLRC разбирает LRC и enhanced LRC. Метки строк должны идти по неубыванию, пословные
+// метки — не раньше начала строки и тоже по неубыванию. Несколько меток в начале строки
+// ([00:12.00][01:05.00]Припев) разворачиваются в несколько строк.
+// Тег [offset:] применяется к меткам и в результат не попадает.
+func ParseLRC(data string) (SyncedLyrics, error) {
+	result := SyncedLyrics{Tags: make(map[string]string), Lines: make([]SyncedLine, 0)}
+	errs := &ParseError{}
+
+	var offset int64
+	var previous int64 = -1
+
+	lines := strings.Split(strings.ReplaceAll(strings.TrimPrefix(data, "\uFEFF"), "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		number := i + 1
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		starts := make([]int64, 0, 1)
+		valid := true
+		for {
+			match := timeTagPattern.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			start, err := lrcTime(match[1], match[2], match[3])
+			if err != nil {
+				errs.Lines = append(errs.Lines, LineError{Line: number, Message: err.Error()})
+				valid = false
+			}
+			starts = append(starts, start)
+			line = line[len(match[0]):]
+		}
+		if !valid {
+			continue
+		}
+
+		if len(starts) == 0 {
+			if match := metaTagPattern.FindStringSubmatch(line); match != nil {
+				key := strings.ToLower(match[1])
+				value := strings.TrimSpace(match[2])
+				if key == "offset" {
+					parsed, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						errs.Lines = append(errs.Lines, LineError{Line: number, Message: fmt.Sprintf("invalid offset %q", value)})
+						continue
+					}
+					offset = parsed
+					continue
+				}
+				result.Tags[key] = value
+				continue
+			}
+			errs.Lines = append(errs.Lines, LineError{Line: number, Message: "missing timestamp"})
+			continue
+		}
+
+		for j := 1; j < len(starts); j++ {
+			if starts[j] < starts[j-1] {
+				errs.Lines = append(errs.Lines, LineError{Line: number, Message: "timestamps within the line are not in ascending order"})
+				break
+			}
+		}
+		if starts[0] < previous {
+			errs.Lines = append(errs.Lines, LineError{
+				Line:    number,
+				Message: fmt.Sprintf("timestamp %s is earlier than the previous line (%s)", FormatTimestamp(starts[0]), FormatTimestamp(previous)),
+			})
+		}
+		previous = starts[0]
+
+		text, words, err := parseWords(line, starts[0])
+		if err != nil {
+			errs.Lines = append(errs.Lines, LineError{Line: number, Message: err.Error()})
+		}
+
+		for _, start := range starts {
+			synced := SyncedLine{StartMs: start, Text: text}
+			// Пословные метки относятся только к первому вхождению строки
+			if start == starts[0] {
+				synced.Words = words
+			}
+			result.Lines = append(result.Lines, synced)
+		}
+	}
+
+	if len(result.Lines) == 0 && len(errs.Lines) == 0 {
+		errs.Lines = append(errs.Lines, LineError{Line: 1, Message: "no timed lines found"})
+	}
+	if len(errs.Lines) > 0 {
+		return SyncedLyrics{}, errs
+	}
+
+	sort.SliceStable(result.Lines, func(i, j int) bool {
+		return result.Lines[i].StartMs < result.Lines[j].StartMs
+	})
+
+	// Положительный offset означает, что текст должен появляться раньше
+	if offset != 0 {
+		for i := range result.Lines {
+			result.Lines[i].StartMs = max(result.Lines[i].StartMs-offset, 0)
+			for j := range result.Lines[i].Words {
+				result.Lines[i].Words[j].StartMs = max(result.Lines[i].Words[j].StartMs-offset, 0)
+			}
+		}
+	}
+	if len(result.Tags) == 0 {
+		result.Tags = nil
+	}
+	return result, nil
+}
+
+// parseWords разбирает пословные метки enhanced LRC и возвращает текст строки без них
+func parseWords(line string, lineStart int64) (string, []SyncedWord, error) {
+	matches := wordTagPattern.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 {
+		return strings.TrimSpace(line), nil, nil
+	}
+
+	words := make([]SyncedWord, 0, len(matches))
+	previous := lineStart
+	var text strings.Builder
+	text.WriteString(line[:matches[0][0]])
+
+	for i, m := range matches {
+		start, err := lrcTime(line[m[2]:m[3]], line[m[4]:m[5]], submatch(line, m[6], m[7]))
+		if err != nil {
+			return "", nil, err
+		}
+		if start < previous {
+			return "", nil, fmt.Errorf("word timestamp %s is earlier than %s", FormatTimestamp(start), FormatTimestamp(previous))
+		}
+		previous = start
+
+		end := len(line)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		word := line[m[1]:end]
+		text.WriteString(word)
+		if trimmed := strings.TrimSpace(word); trimmed != "" {
+			words = append(words, SyncedWord{StartMs: start, Text: trimmed})
+		}
+	}
+	return strings.Join(strings.Fields(text.String()), " "), words, nil
+}
+
+func submatch(s string, from, to int) string {
+	if from < 0 {
+		return ""
+	}
+	return s[from:to]
+}
+
+// lrcTime переводит минуты, секунды и дробную часть метки в миллисекунды
+func lrcTime(minutes, seconds, fraction string) (int64, error) {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+	if s >= 60 {
+		return 0, fmt.Errorf("invalid timestamp %s:%s: seconds must be below 60", minutes, seconds)
+	}
+
+	var ms int64
+	if fraction != "" {
+		f, _ := strconv.ParseInt(fraction, 10, 64)
+		switch len(fraction) {
+		case 1:
+			ms = f * 100
+		case 2:
+			ms = f * 10
+		default:
+			ms = f
+		}
+	}
+	return (m*60+s)*1000 + ms, nil
+}
+
+// FormatTimestamp возвращает метку в формате LRC: mm:ss.xx
+func FormatTimestamp(ms int64) string {
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
+
+// FormatLRC собирает LRC-файл; при наличии пословных меток получается enhanced LRC
+func FormatLRC(synced SyncedLyrics) string {
+	var b strings.Builder
+
+	keys := make([]string, 0, len(synced.Tags))
+	for key := range synced.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "[%s:%s]\n", key, synced.Tags[key])
+	}
+
+	for _, line := range synced.Lines {
+		fmt.Fprintf(&b, "[%s]", FormatTimestamp(line.StartMs))
+		if len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		} else {
+			for i, word := range line.Words {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				fmt.Fprintf(&b, "<%s>%s", FormatTimestamp(word.StartMs), word.Text)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// lastCueMs — длительность последней реплики WebVTT, если длина трека неизвестна
+const lastCueMs = 5000
+
+// FormatWebVTT собирает субтитры WebVTT: каждая строка длится до начала следующей.
+// Пустые строки LRC (паузы) только завершают предыдущую реплику.
+func FormatWebVTT(synced SyncedLyrics) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	length := int64(-1)
+	if raw, ok := synced.Tags["length"]; ok {
+		if parts := timeTagPattern.FindStringSubmatch("[" + strings.TrimSpace(raw) + "]"); parts != nil {
+			length, _ = lrcTime(parts[1], parts[2], parts[3])
+		}
+	}
+
+	cue := 0
+	for i, line := range synced.Lines {
+		if line.Text == "" {
+			continue
+		}
+
+		end := line.StartMs + lastCueMs
+		if i+1 < len(synced.Lines) {
+			end = synced.Lines[i+1].StartMs
+		} else if length > line.StartMs {
+			end = length
+		}
+
+		cue++
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n", cue, vttTimestamp(line.StartMs), vttTimestamp(end))
+		if len(line.Words) == 0 {
+			b.WriteString(vttText(line.Text))
+		} else {
+			for j, word := range line.Words {
+				if j > 0 {
+					b.WriteByte(' ')
+				}
+				if word.StartMs > line.StartMs && word.StartMs < end {
+					fmt.Fprintf(&b, "<%s>", vttTimestamp(word.StartMs))
+				}
+				b.WriteString(vttText(word.Text))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func vttTimestamp(ms int64) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// vttText экранирует символы, которые в WebVTT начинают разметку
+func vttText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
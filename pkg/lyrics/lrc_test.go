@@ -0,0 +1,134 @@
+package lyrics
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want SyncedLyrics
+	}{
+		{
+			name: "timestamps with different precision",
+			data: "\ufeff[ar:Muse]\r\n[ti:Uprising]\r\n[00:01]One\r\n[00:02.5]Two\r\n[00:03.25]Three\r\n[01:04.125]Four",
+			want: SyncedLyrics{
+				Tags: map[string]string{"ar": "Muse", "ti": "Uprising"},
+				Lines: []SyncedLine{
+					{StartMs: 1000, Text: "One"},
+					{StartMs: 2500, Text: "Two"},
+					{StartMs: 3250, Text: "Three"},
+					{StartMs: 64125, Text: "Four"},
+				},
+			},
+		},
+		{
+			name: "repeated line and pause",
+			data: "[00:10.00][00:30.00]Chorus\n[00:20.00]\n[00:25.00]Verse",
+			want: SyncedLyrics{Lines: []SyncedLine{
+				{StartMs: 10000, Text: "Chorus"},
+				{StartMs: 20000, Text: ""},
+				{StartMs: 25000, Text: "Verse"},
+				{StartMs: 30000, Text: "Chorus"},
+			}},
+		},
+		{
+			name: "enhanced LRC with offset",
+			data: "[offset:+500]\n[00:01.00]<00:01.00>Hello <00:01.50>big  <00:02.00>world",
+			want: SyncedLyrics{Lines: []SyncedLine{{
+				StartMs: 500,
+				Text:    "Hello big world",
+				Words: []SyncedWord{
+					{StartMs: 500, Text: "Hello"},
+					{StartMs: 1000, Text: "big"},
+					{StartMs: 1500, Text: "world"},
+				},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLRC(tt.data)
+			if err != nil {
+				t.Fatalf("ParseLRC() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLRC() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLRCErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []LineError
+	}{
+		{
+			name: "empty file",
+			data: "\n\n",
+			want: []LineError{{Line: 1, Message: "no timed lines found"}},
+		},
+		{
+			name: "all errors are collected",
+			data: "[00:05.00]Later\n[00:01.00]Earlier\nno timestamp\n[00:75.00]Bad seconds\n[offset:soon]",
+			want: []LineError{
+				{Line: 2, Message: "timestamp 00:01.00 is earlier than the previous line (00:05.00)"},
+				{Line: 3, Message: "missing timestamp"},
+				{Line: 4, Message: "invalid timestamp 00:75: seconds must be below 60"},
+				{Line: 5, Message: `invalid offset "soon"`},
+			},
+		},
+		{
+			name: "word before line start",
+			data: "[00:02.00]<00:01.00>Early",
+			want: []LineError{{Line: 1, Message: "word timestamp 00:01.00 is earlier than 00:02.00"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLRC(tt.data)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseLRC() error = %v, want *ParseError", err)
+			}
+			if !reflect.DeepEqual(parseErr.Lines, tt.want) {
+				t.Errorf("errors = %#v, want %#v", parseErr.Lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLRCRoundTrip(t *testing.T) {
+	data := "[ar:Muse]\n[ti:Uprising]\n[00:01.00]One\n[00:02.50]<00:02.50>Two <00:03.00>words\n"
+	synced, err := ParseLRC(data)
+	if err != nil {
+		t.Fatalf("ParseLRC() error = %v", err)
+	}
+	if got := FormatLRC(synced); got != data {
+		t.Errorf("FormatLRC() = %q, want %q", got, data)
+	}
+}
+
+func TestFormatWebVTT(t *testing.T) {
+	synced := SyncedLyrics{
+		Tags: map[string]string{"length": "00:10.00"},
+		Lines: []SyncedLine{
+			{StartMs: 1000, Text: "Rock & <roll>"},
+			{StartMs: 4000, Text: ""},
+			{StartMs: 6000, Text: "Last words", Words: []SyncedWord{{StartMs: 6000, Text: "Last"}, {StartMs: 7500, Text: "words"}}},
+		},
+	}
+	want := "WEBVTT\n" +
+		"\n1\n00:00:01.000 --> 00:00:04.000\nRock &amp; &lt;roll&gt;\n" +
+		"\n2\n00:00:06.000 --> 00:00:10.000\nLast <00:00:07.500>words\n"
+	if got := FormatWebVTT(synced); got != want {
+		t.Errorf("FormatWebVTT() = %q, want %q", got, want)
+	}
+}
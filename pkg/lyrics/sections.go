@@ -0,0 +1,165 @@
+// Package lyrics разбирает тексты песен на структурированные части.
+// Пакет не зависит от хранилища и HTTP: на вход — текст, на выход — секции.
+package lyrics
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Типы секций текста
+const (
+	SectionVerse  = "verse"
+	SectionChorus = "chorus"
+	SectionBridge = "bridge"
+	SectionIntro  = "intro"
+	SectionOutro  = "outro"
+)
+
+// Section — часть песни (куплет, припев и т. д.) со своими строками
+type Section struct {
+	Type  string   `json:"type" example:"chorus"`
+	Label string   `json:"label" example:"Chorus"`
+	Lines []string `json:"lines"`
+}
+
+// sectionTypes сопоставляет заголовки секций (на английском и русском) с их типом
+var sectionTypes = map[string]string{
+	"verse":       SectionVerse,
+	"куплет":      SectionVerse,
+	"chorus":      SectionChorus,
+	"refrain":     SectionChorus,
+	"hook":        SectionChorus,
+	"припев":      SectionChorus,
+	"bridge":      SectionBridge,
+	"бридж":       SectionBridge,
+	"intro":       SectionIntro,
+	"интро":       SectionIntro,
+	"вступление":  SectionIntro,
+	"outro":       SectionOutro,
+	"аутро":       SectionOutro,
+	"концовка":    SectionOutro,
+	"pre-chorus":  SectionBridge,
+	"предприпев":  SectionBridge,
+	"post-chorus": SectionChorus,
+}
+
+var sectionLabels = map[string]string{
+	SectionVerse:  "Verse",
+	SectionChorus: "Chorus",
+	SectionBridge: "Bridge",
+	SectionIntro:  "Intro",
+	SectionOutro:  "Outro",
+}
+
+// headerPattern — строка-заголовок секции: «[Chorus]», «Verse 2:», «Припев», «[Куплет 1: Исполнитель]»
+var headerPattern = regexp.MustCompile(`^[\[(]?\s*([\p{L}-]+)\s*(\d+)?\s*(?::[^\])]*)?[\])]?\s*:?$`)
+
+// Normalize приводит переводы строк к \n. Старые записи хранят переводы строк
+// в виде экранированной последовательности «\n», она тоже заменяется.
+func Normalize(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.ReplaceAll(text, `\n`, "\n")
+}
+
+// Parse разбивает текст на секции. Секции разделяются пустыми строками; заголовок в первой
+// строке блока задаёт тип секции. Блоки без заголовка считаются куплетами, а повторяющиеся
+// без изменений блоки — припевом.
+func Parse(text string) []Section {
+	blocks := splitBlocks(Normalize(text))
+
+	sections := make([]Section, 0, len(blocks))
+	repeats := make(map[string]int, len(blocks))
+	pending := ""
+	pendingLabel := ""
+
+	for _, block := range blocks {
+		sectionType, label := headerType(block[0])
+		if sectionType != "" {
+			block = block[1:]
+		} else if pending != "" {
+			// Заголовок отдельным блоком относится к следующему блоку
+			sectionType, label = pending, pendingLabel
+		}
+
+		if len(block) == 0 {
+			pending, pendingLabel = sectionType, label
+			continue
+		}
+		pending, pendingLabel = "", ""
+
+		sections = append(sections, Section{Type: sectionType, Label: label, Lines: block})
+		repeats[blockKey(block)]++
+	}
+
+	verses := 0
+	for i := range sections {
+		if sections[i].Type == "" {
+			sections[i].Type = SectionVerse
+			if repeats[blockKey(sections[i].Lines)] > 1 {
+				sections[i].Type = SectionChorus
+			}
+		}
+		if sections[i].Type == SectionVerse {
+			verses++
+		}
+		if sections[i].Label == "" {
+			sections[i].Label = sectionLabels[sections[i].Type]
+			if sections[i].Type == SectionVerse {
+				sections[i].Label += " " + strconv.Itoa(verses)
+			}
+		}
+	}
+	return sections
+}
+
+// Text собирает секции обратно в текст: строки через перевод строки, секции через пустую строку
+func Text(sections []Section) string {
+	blocks := make([]string, 0, len(sections))
+	for _, section := range sections {
+		blocks = append(blocks, strings.Join(section.Lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// splitBlocks делит текст на блоки непустых строк
+func splitBlocks(text string) [][]string {
+	blocks := make([][]string, 0)
+	current := make([]string, 0)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = make([]string, 0)
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+// headerType распознаёт заголовок секции и возвращает её тип и подпись в исходном написании
+func headerType(line string) (string, string) {
+	match := headerPattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", ""
+	}
+	sectionType, ok := sectionTypes[strings.ToLower(match[1])]
+	if !ok {
+		return "", ""
+	}
+	label := strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "[]():"))
+	return sectionType, label
+}
+
+func blockKey(lines []string) string {
+	return strings.ToLower(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,86 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Section
+	}{
+		{
+			name: "blocks without headers are numbered verses",
+			text: "Line one\nLine two\n\nLine three",
+			want: []Section{
+				{Type: SectionVerse, Label: "Verse 1", Lines: []string{"Line one", "Line two"}},
+				{Type: SectionVerse, Label: "Verse 2", Lines: []string{"Line three"}},
+			},
+		},
+		{
+			name: "repeated block is a chorus",
+			text: "Verse line\n\nOoh baby\nDon't you know\n\nAnother verse\n\nooh baby\ndon't you know",
+			want: []Section{
+				{Type: SectionVerse, Label: "Verse 1", Lines: []string{"Verse line"}},
+				{Type: SectionChorus, Label: "Chorus", Lines: []string{"Ooh baby", "Don't you know"}},
+				{Type: SectionVerse, Label: "Verse 2", Lines: []string{"Another verse"}},
+				{Type: SectionChorus, Label: "Chorus", Lines: []string{"ooh baby", "don't you know"}},
+			},
+		},
+		{
+			name: "headers keep their spelling",
+			text: "[Intro]\nHey\n\nVerse 2:\nWords\n\n(Pre-Chorus)\nRising\n\nOutro\nBye",
+			want: []Section{
+				{Type: SectionIntro, Label: "Intro", Lines: []string{"Hey"}},
+				{Type: SectionVerse, Label: "Verse 2", Lines: []string{"Words"}},
+				{Type: SectionBridge, Label: "Pre-Chorus", Lines: []string{"Rising"}},
+				{Type: SectionOutro, Label: "Outro", Lines: []string{"Bye"}},
+			},
+		},
+		{
+			name: "russian headers and performer",
+			text: "[Куплет 1: Исполнитель]\nСлова\n\nПрипев:\nЛа-ла",
+			want: []Section{
+				{Type: SectionVerse, Label: "Куплет 1: Исполнитель", Lines: []string{"Слова"}},
+				{Type: SectionChorus, Label: "Припев", Lines: []string{"Ла-ла"}},
+			},
+		},
+		{
+			name: "header in its own block applies to the next block",
+			text: "[Chorus]\n\nSing along",
+			want: []Section{
+				{Type: SectionChorus, Label: "Chorus", Lines: []string{"Sing along"}},
+			},
+		},
+		{
+			name: "escaped and CRLF line breaks",
+			text: `First\nSecond` + "\r\n\r\nThird",
+			want: []Section{
+				{Type: SectionVerse, Label: "Verse 1", Lines: []string{"First", "Second"}},
+				{Type: SectionVerse, Label: "Verse 2", Lines: []string{"Third"}},
+			},
+		},
+		{
+			name: "empty text",
+			text: " \n\n ",
+			want: []Section{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	sections := Parse("[Chorus]\nA\nB\n\n\n\nC")
+	if got, want := Text(sections), "A\nB\n\nC"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package lyrics
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Системы транслитерации кириллицы латиницей
+const (
+	// TranslitISO9 — ISO 9:1995 (ГОСТ 7.79-2000, система А): одна буква — один знак, с диакритикой
+	TranslitISO9 = "iso9"
+	// TranslitGOST — ГОСТ 7.79-2000, система Б: только ASCII, буквосочетания вместо диакритики
+	TranslitGOST = "gost"
+)
+
+var iso9 = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "ë", 'ж': "ž", 'з': "z",
+	'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'ш': "š", 'щ': "ŝ",
+	'ъ': "ʺ", 'ы': "y", 'ь': "ʹ", 'э': "è", 'ю': "û", 'я': "â",
+	'і': "ì", 'ї': "ï", 'є': "ê", 'ґ': "g̀", 'ў': "ǔ",
+}
+
+var gost = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "x", 'ц': "cz", 'ч': "ch", 'ш': "sh", 'щ': "shh",
+	'ъ': "``", 'ы': "y`", 'ь': "`", 'э': "e`", 'ю': "yu", 'я': "ya",
+	'і': "i`", 'ї': "yi", 'є': "ye", 'ґ': "g`", 'ў': "u`",
+}
+
+// ValidTranslit сообщает, поддерживается ли система транслитерации
+func ValidTranslit(scheme string) bool {
+	return scheme == TranslitISO9 || scheme == TranslitGOST
+}
+
+// Transliterate переводит кириллицу в латиницу по выбранной системе; остальные символы не меняются.
+// Регистр сохраняется: «Щука» → «Shhuka», «ЩУКА» → «SHHUKA».
+func Transliterate(text, scheme string) string {
+	table := iso9
+	if scheme == TranslitGOST {
+		table = gost
+	}
+
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i, r := range runes {
+		lower := unicode.ToLower(r)
+		latin, ok := table[lower]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		// В системе Б «ц» пишется как «c» перед е, и, ы, й (и і, є), в остальных случаях — «cz»
+		if scheme == TranslitGOST && lower == 'ц' && i+1 < len(runes) {
+			switch unicode.ToLower(runes[i+1]) {
+			case 'е', 'и', 'ы', 'й', 'і', 'є':
+				latin = "c"
+			}
+		}
+
+		if r != lower {
+			latin = upperLatin(latin, runes, i)
+		}
+		b.WriteString(latin)
+	}
+	return b.String()
+}
+
+// upperLatin переводит в верхний регистр замену заглавной буквы: целиком, если соседние буквы
+// тоже заглавные (слово написано капсом), иначе только первый знак
+func upperLatin(latin string, runes []rune, i int) string {
+	allCaps := (i+1 < len(runes) && unicode.IsUpper(runes[i+1])) || (i > 0 && unicode.IsUpper(runes[i-1]))
+	if allCaps {
+		return strings.ToUpper(latin)
+	}
+	first := []rune(latin)
+	first[0] = unicode.ToUpper(first[0])
+	return string(first)
+}
+
+// IsCyrillic сообщает, что кириллических букв в тексте больше, чем остальных
+func IsCyrillic(text string) bool {
+	cyrillic, letters := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Cyrillic, r) {
+			cyrillic++
+		}
+	}
+	return letters > 0 && cyrillic*2 > letters
+}
@@ -0,0 +1,50 @@
+package lyrics
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		text   string
+		scheme string
+		want   string
+	}{
+		{"Щука", TranslitGOST, "Shhuka"},
+		{"ЩУКА", TranslitGOST, "SHHUKA"},
+		{"Щука", TranslitISO9, "Ŝuka"},
+		{"цирк и цапля", TranslitGOST, "cirk i czaplya"},
+		{"Цой жив", TranslitGOST, "Czoj zhiv"},
+		{"Ёжик в тумане", TranslitISO9, "Ëžik v tumane"},
+		{"Ёжик в тумане", TranslitGOST, "Yozhik v tumane"},
+		{"съешь же ещё", TranslitGOST, "s``esh` zhe eshhyo"},
+		{"ЦИРК", TranslitGOST, "CIRK"},
+		{"Царь", TranslitGOST, "Czar`"},
+		{"отец", TranslitGOST, "otecz"},
+		{"Rock-н-ролл 2000!", TranslitGOST, "Rock-n-roll 2000!"},
+	}
+
+	for _, tt := range tests {
+		if got := Transliterate(tt.text, tt.scheme); got != tt.want {
+			t.Errorf("Transliterate(%q, %s) = %q, want %q", tt.text, tt.scheme, got, tt.want)
+		}
+	}
+}
+
+func TestIsCyrillic(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Группа крови", true},
+		{"Supermassive Black Hole", false},
+		{"Rock-н-ролл", true},
+		{"Рок-n-roll forever", false},
+		{"2000 — 2024", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsCyrillic(tt.text); got != tt.want {
+			t.Errorf("IsCyrillic(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
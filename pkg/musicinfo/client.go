@@ -0,0 +1,173 @@
+package musicinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/pkg/service"
+)
+
+type Config struct {
+	BaseURL         string
+	Timeout         time.Duration
+	Retries         int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// Client — клиент внешнего API с информацией о песнях (GET /info?group=&song=)
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+type songDetailResponse struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+func NewClient(cfg Config) *Client {
+	cfg = cfg.withDefaults()
+	return NewClientWithHTTP(cfg, &http.Client{Timeout: cfg.Timeout})
+}
+
+// NewClientWithHTTP позволяет подменить http.Client (например, на клиент httptest.Server)
+func NewClientWithHTTP(cfg Config, httpClient *http.Client) *Client {
+	return &Client{cfg: cfg.withDefaults(), httpClient: httpClient}
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 200 * time.Millisecond
+	}
+	if cfg.MaxRetryBackoff < cfg.RetryBackoff {
+		cfg.MaxRetryBackoff = cfg.RetryBackoff
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return cfg
+}
+
+func (c *Client) GetSongDetail(ctx context.Context, group, song string) (service.SongDetail, error) {
+	if c.cfg.BaseURL == "" {
+		return service.SongDetail{}, fmt.Errorf("music info base url is not configured: %w", service.ErrSongDetailUnavailable)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.Retries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return service.SongDetail{}, err
+			}
+		}
+
+		detail, err := c.fetch(ctx, group, song)
+		if err == nil {
+			return detail, nil
+		}
+		lastErr = err
+
+		var retryErr *retryableError
+		if !errors.As(err, &retryErr) {
+			return service.SongDetail{}, err
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"group":   group,
+			"song":    song,
+			"attempt": attempt + 1,
+		}).Warnf("Music info request failed: %v", err)
+	}
+
+	return service.SongDetail{}, fmt.Errorf("music info is unavailable after %d attempts: %v: %w",
+		c.cfg.Retries+1, lastErr, service.ErrSongDetailUnavailable)
+}
+
+func (c *Client) fetch(ctx context.Context, group, song string) (service.SongDetail, error) {
+	params := url.Values{}
+	params.Set("group", group)
+	params.Set("song", song)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.BaseURL+"/info?"+params.Encode(), nil)
+	if err != nil {
+		return service.SongDetail{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return service.SongDetail{}, ctx.Err()
+		}
+		return service.SongDetail{}, &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return service.SongDetail{}, fmt.Errorf("group %q song %q: %w", group, song, service.ErrSongDetailNotFound)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return service.SongDetail{}, &retryableError{err: fmt.Errorf("unexpected status %d", resp.StatusCode)}
+	default:
+		// Остальные ответы повтор не исправит, но для клиента это тоже недоступность музыкального сервиса
+		return service.SongDetail{}, fmt.Errorf("music info responded with status %d: %w", resp.StatusCode, service.ErrSongDetailUnavailable)
+	}
+
+	var body songDetailResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return service.SongDetail{}, &retryableError{err: fmt.Errorf("failed to decode response: %w", err)}
+	}
+
+	return service.SongDetail{
+		ReleaseDate: body.ReleaseDate,
+		Text:        body.Text,
+		Link:        body.Link,
+	}, nil
+}
+
+// backoff — экспоненциальная задержка перед повторной попыткой attempt (с 1), не больше MaxRetryBackoff
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.cfg.RetryBackoff << (attempt - 1)
+	if delay <= 0 || delay > c.cfg.MaxRetryBackoff {
+		delay = c.cfg.MaxRetryBackoff
+	}
+	return delay
+}
+
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(c.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,129 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skorpsrgvch/music-lib/pkg/service"
+)
+
+// newTestClient поднимает httptest-сервер с ответами из statuses по порядку (последний повторяется)
+// и возвращает клиент к нему и счётчик запросов
+func newTestClient(t *testing.T, retries int, statuses ...int) (*Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if r.URL.Path != "/info" || r.URL.Query().Get("group") != "Muse" || r.URL.Query().Get("song") != "Supermassive Black Hole" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"releaseDate": "16.07.2006", "text": "Ooh baby", "link": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClientWithHTTP(Config{
+		BaseURL:         server.URL + "/",
+		Retries:         retries,
+		RetryBackoff:    time.Millisecond,
+		MaxRetryBackoff: 2 * time.Millisecond,
+	}, server.Client())
+	return client, &calls
+}
+
+func TestGetSongDetail(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   int
+		statuses  []int
+		wantErr   error
+		wantCalls int32
+	}{
+		{name: "ok", retries: 2, statuses: []int{200}, wantCalls: 1},
+		{name: "retried until ok", retries: 2, statuses: []int{503, 429, 200}, wantCalls: 3},
+		{name: "not found is not retried", retries: 2, statuses: []int{404}, wantErr: service.ErrSongDetailNotFound, wantCalls: 1},
+		{name: "bad request is not retried", retries: 2, statuses: []int{400}, wantErr: service.ErrSongDetailUnavailable, wantCalls: 1},
+		{name: "forbidden is not retried", retries: 2, statuses: []int{403}, wantErr: service.ErrSongDetailUnavailable, wantCalls: 1},
+		{name: "unprocessable is not retried", retries: 2, statuses: []int{422}, wantErr: service.ErrSongDetailUnavailable, wantCalls: 1},
+		{name: "upstream down", retries: 2, statuses: []int{500}, wantErr: service.ErrSongDetailUnavailable, wantCalls: 3},
+		{name: "no retries", retries: 0, statuses: []int{502, 200}, wantErr: service.ErrSongDetailUnavailable, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := newTestClient(t, tt.retries, tt.statuses...)
+
+			detail, err := client.GetSongDetail(context.Background(), "Muse", "Supermassive Black Hole")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantErr == nil && detail.ReleaseDate != "16.07.2006" {
+				t.Errorf("detail = %+v", detail)
+			}
+		})
+	}
+}
+
+func TestGetSongDetailUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := NewClientWithHTTP(Config{BaseURL: server.URL, Retries: 1, RetryBackoff: time.Millisecond}, http.DefaultClient)
+	if _, err := client.GetSongDetail(context.Background(), "Muse", "Uprising"); !errors.Is(err, service.ErrSongDetailUnavailable) {
+		t.Fatalf("error = %v, want ErrSongDetailUnavailable", err)
+	}
+
+	client = NewClient(Config{})
+	if _, err := client.GetSongDetail(context.Background(), "Muse", "Uprising"); !errors.Is(err, service.ErrSongDetailUnavailable) {
+		t.Fatalf("error without base url = %v, want ErrSongDetailUnavailable", err)
+	}
+}
+
+func TestGetSongDetailCanceledDuringBackoff(t *testing.T) {
+	client, _ := newTestClient(t, 3, http.StatusServiceUnavailable)
+	client.cfg.RetryBackoff = time.Hour
+	client.cfg.MaxRetryBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetSongDetail(ctx, "Muse", "Supermassive Black Hole"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	client := NewClient(Config{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: time.Second})
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{80, time.Second}, // переполнение сдвига
+	}
+	for _, tt := range tests {
+		if got := client.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
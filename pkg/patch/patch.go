@@ -0,0 +1,365 @@
+// Package patch применяет к JSON-документам изменения в форматах JSON Merge Patch (RFC 7396)
+// и JSON Patch (RFC 6902). Документ — результат json.Unmarshal в interface{}:
+// объекты — map[string]interface{}, массивы — []interface{}.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Типы содержимого патчей
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrPathNotFound = errors.New("path not found")
+	ErrTestFailed   = errors.New("test failed")
+)
+
+// OperationError — ошибка операции JSON Patch с её номером (с 0) и путём
+type OperationError struct {
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d (%s %s): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// MergePatch применяет merge patch к документу по алгоритму RFC 7396: null удаляет ключ,
+// объекты сливаются рекурсивно, любое другое значение заменяет прежнее целиком
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	result := make(map[string]interface{}, len(targetObject))
+	for key, value := range targetObject {
+		result[key] = value
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = MergePatch(result[key], value)
+	}
+	return result
+}
+
+// Operation — операция JSON Patch. Value хранится как есть, чтобы отличать null от отсутствия значения.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// DecodeJSONPatch разбирает документ JSON Patch — массив операций
+func DecodeJSONPatch(data []byte) ([]Operation, error) {
+	var ops []Operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("%w: JSON Patch must be an array of operations", ErrInvalidPatch)
+	}
+	return ops, nil
+}
+
+// ApplyJSONPatch применяет операции по порядку. Патч применяется целиком или не применяется вовсе:
+// исходный документ не изменяется, при ошибке возвращается *OperationError.
+func ApplyJSONPatch(doc interface{}, ops []Operation) (interface{}, error) {
+	doc = deepCopy(doc)
+
+	for i, op := range ops {
+		var err error
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			return nil, &OperationError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+	}
+	return doc, nil
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		value, err := Decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrTestFailed
+			}
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return add(doc, path, deepCopy(value))
+		}
+		if isProperPrefix(from, path) {
+			return nil, fmt.Errorf("%w: cannot move a value into one of its children", ErrInvalidPatch)
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer разбирает JSON Pointer (RFC 6901): "/a/b~1c" → ["a", "b/c"]; "" — весь документ
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: JSON Pointer %q must start with '/'", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isProperPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex разбирает индекс массива; "-" (за последним элементом) допустим только при вставке
+func arrayIndex(token string, length int, insert bool) (int, error) {
+	if token == "-" && insert {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPathNotFound
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, ErrPathNotFound
+	}
+	limit := length - 1
+	if insert {
+		limit = length
+	}
+	if index > limit {
+		return 0, ErrPathNotFound
+	}
+	return index, nil
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			value, ok := n[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			node = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[index]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return node, nil
+}
+
+// update находит родителя последнего элемента пути и применяет к нему change;
+// возвращает документ, в котором изменённый родитель подставлен на своё место
+func update(node interface{}, path []string, change func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return change(node, path[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[path[0]]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		updated, err := update(child, path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = updated
+		return n, nil
+	case []interface{}:
+		index, err := arrayIndex(path[0], len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := update(n[index], path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = updated
+		return n, nil
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[key] = value
+			return p, nil
+		case []interface{}:
+			index, err := arrayIndex(key, len(p), true)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[index+1:], p[index:])
+			p[index] = value
+			return p, nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidPatch)
+	}
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[key]; !ok {
+				return nil, ErrPathNotFound
+			}
+			delete(p, key)
+			return p, nil
+		case []interface{}:
+			index, err := arrayIndex(key, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:index], p[index+1:]...), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+func replace(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[key]; !ok {
+				return nil, ErrPathNotFound
+			}
+			p[key] = value
+			return p, nil
+		case []interface{}:
+			index, err := arrayIndex(key, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			p[index] = value
+			return p, nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+func deepCopy(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(n))
+		for key, value := range n {
+			result[key] = deepCopy(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(n))
+		for i, value := range n {
+			result[i] = deepCopy(value)
+		}
+		return result
+	default:
+		return node
+	}
+}
+
+// Decode разбирает JSON-документ или merge patch в interface{}
+func Decode(data []byte) (interface{}, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: malformed JSON", ErrInvalidPatch)
+	}
+	return doc, nil
+}
@@ -0,0 +1,134 @@
+package patch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func mustDecode(t *testing.T, data string) interface{} {
+	t.Helper()
+	doc, err := Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("Decode(%s) error = %v", data, err)
+	}
+	return doc
+}
+
+// Примеры из приложения A RFC 7396
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		got := MergePatch(mustDecode(t, tt.target), mustDecode(t, tt.patch))
+		if want := mustDecode(t, tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("MergePatch(%s, %s) = %v, want %s", tt.target, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"append to array", `{"foo":[1]}`, `[{"op":"add","path":"/foo/-","value":2}]`, `{"foo":[1,2]}`},
+		{"add null", `{}`, `[{"op":"add","path":"/a","value":null}]`, `{"a":null}`},
+		{"remove member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"copy", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{"test then replace", `{"a":[1,{"b":"c"}]}`, `[{"op":"test","path":"/a","value":[1,{"b":"c"}]},{"op":"replace","path":"/a/0","value":0}]`, `{"a":[0,{"b":"c"}]}`},
+		{"escaped pointer", `{"a/b":1,"m~n":2}`, `[{"op":"remove","path":"/a~1b"},{"op":"replace","path":"/m~0n","value":3}]`, `{"m~n":3}`},
+		{"replace whole document", `{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := DecodeJSONPatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("DecodeJSONPatch() error = %v", err)
+			}
+			doc := mustDecode(t, tt.doc)
+			got, err := ApplyJSONPatch(doc, ops)
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch() error = %v", err)
+			}
+			if want := mustDecode(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("ApplyJSONPatch() = %v, want %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(doc, mustDecode(t, tt.doc)) {
+				t.Errorf("source document was modified: %v", doc)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, patch string
+		wantErr          error
+		wantIndex        int
+	}{
+		{"test failed", `{"a":1}`, `[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":1}]`, ErrTestFailed, 1},
+		{"missing member", `{"a":1}`, `[{"op":"remove","path":"/b"}]`, ErrPathNotFound, 0},
+		{"replace missing member", `{"a":1}`, `[{"op":"replace","path":"/b","value":1}]`, ErrPathNotFound, 0},
+		{"index out of range", `{"a":[1]}`, `[{"op":"add","path":"/a/2","value":1}]`, ErrPathNotFound, 0},
+		{"leading zero index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`, ErrPathNotFound, 0},
+		{"dash outside add", `{"a":[1]}`, `[{"op":"remove","path":"/a/-"}]`, ErrPathNotFound, 0},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`, ErrPathNotFound, 0},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`, ErrInvalidPatch, 0},
+		{"pointer without slash", `{}`, `[{"op":"add","path":"a","value":1}]`, ErrInvalidPatch, 0},
+		{"unknown op", `{}`, `[{"op":"merge","path":"/a","value":1}]`, ErrInvalidPatch, 0},
+		{"move into child", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`, ErrInvalidPatch, 0},
+		{"remove whole document", `{}`, `[{"op":"remove","path":""}]`, ErrInvalidPatch, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := DecodeJSONPatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("DecodeJSONPatch() error = %v", err)
+			}
+			_, err = ApplyJSONPatch(mustDecode(t, tt.doc), ops)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyJSONPatch() error = %v, want %v", err, tt.wantErr)
+			}
+			var opErr *OperationError
+			if !errors.As(err, &opErr) || opErr.Index != tt.wantIndex {
+				t.Errorf("error = %#v, want operation %d", err, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONPatchInvalid(t *testing.T) {
+	for _, data := range []string{`{"op":"add"}`, `not json`} {
+		if _, err := DecodeJSONPatch([]byte(data)); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("DecodeJSONPatch(%s) error = %v, want ErrInvalidPatch", data, err)
+		}
+	}
+}
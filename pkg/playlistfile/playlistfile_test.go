@@ -0,0 +1,195 @@
+package playlistfile
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+var testSongs = []models.Song{
+	{GroupName: "Muse", SongName: "Uprising", Link: "https://example.com/uprising.mp3", Duration: 305},
+	{GroupName: "Queen", SongName: "Innuendo"},
+	{SongName: "Untitled\nDemo", Link: "file:///music/demo.ogg"},
+}
+
+func write(t *testing.T, format, title string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, title)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, song := range testSongs {
+		if err := w.Write(song); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriters(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: models.PlaylistM3U8,
+			want: "#EXTM3U\n#PLAYLIST:Road trip\n" +
+				"#EXTINF:305,Muse - Uprising\nhttps://example.com/uprising.mp3\n" +
+				"#EXTINF:-1,Untitled Demo\nfile:///music/demo.ogg\n",
+		},
+		{
+			format: models.PlaylistPLS,
+			want: "[playlist]\nX-Title=Road trip\n" +
+				"File1=https://example.com/uprising.mp3\nTitle1=Muse - Uprising\nLength1=305\n" +
+				"File2=file:///music/demo.ogg\nTitle2=Untitled Demo\nLength2=-1\n" +
+				"NumberOfEntries=2\nVersion=2\n",
+		},
+		{
+			format: models.PlaylistXSPF,
+			want: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<playlist version="1" xmlns="http://xspf.org/ns/0/">` + "\n" +
+				"  <title>Road trip</title>\n  <trackList>\n" +
+				"    <track>\n      <location>https://example.com/uprising.mp3</location>\n      <creator>Muse</creator>\n      <title>Uprising</title>\n      <duration>305000</duration>\n    </track>\n" +
+				"    <track>\n      <creator>Queen</creator>\n      <title>Innuendo</title>\n    </track>\n" +
+				"    <track>\n      <location>file:///music/demo.ogg</location>\n      <title>Untitled&#xA;Demo</title>\n    </track>\n" +
+				"  </trackList>\n</playlist>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := write(t, tt.format, "Road trip"); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []models.PlaylistItem
+	}{
+		{
+			format: models.PlaylistM3U8,
+			want: []models.PlaylistItem{
+				{Line: 3, Group: "Muse", Song: "Uprising", Location: "https://example.com/uprising.mp3", Duration: 305},
+				{Line: 5, Song: "Untitled Demo", Location: "file:///music/demo.ogg"},
+			},
+		},
+		{
+			format: models.PlaylistPLS,
+			want: []models.PlaylistItem{
+				{Line: 3, Group: "Muse", Song: "Uprising", Location: "https://example.com/uprising.mp3", Duration: 305},
+				{Line: 6, Song: "Untitled Demo", Location: "file:///music/demo.ogg"},
+			},
+		},
+		{
+			format: models.PlaylistXSPF,
+			want: []models.PlaylistItem{
+				{Line: 5, Group: "Muse", Song: "Uprising", Location: "https://example.com/uprising.mp3", Duration: 305},
+				{Line: 11, Group: "Queen", Song: "Innuendo"},
+				{Line: 15, Song: "Untitled\nDemo", Location: "file:///music/demo.ogg"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			title, items, err := Read(tt.format, strings.NewReader(write(t, tt.format, "Road trip")))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if title != "Road trip" {
+				t.Errorf("title = %q", title)
+			}
+			if !reflect.DeepEqual(items, tt.want) {
+				t.Errorf("items = %+v, want %+v", items, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadM3U(t *testing.T) {
+	data := "\ufeff#EXTM3U\r\n# comment\r\nplain.mp3\r\n#EXTINF:12.6 tvg-id=\"x\",Artist - Title - Live\r\n#EXTGRP:Rock\r\nhttp://a/b.mp3\r\n#EXTINF:0,Only title\r\n"
+	_, items, err := Read(models.PlaylistM3U8, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := []models.PlaylistItem{
+		{Line: 3, Location: "plain.mp3"},
+		{Line: 4, Group: "Artist", Song: "Title - Live", Location: "http://a/b.mp3", Duration: 13},
+		{Line: 7, Song: "Only title"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+}
+
+func TestReadPLSOrder(t *testing.T) {
+	data := "[Playlist]\nFile10=ten.mp3\nfile2=two.mp3\nTitle2=Two\nLength2=-1\nNumberOfEntries=2\n"
+	_, items, err := Read(models.PlaylistPLS, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := []models.PlaylistItem{
+		{Line: 3, Song: "Two", Location: "two.mp3"},
+		{Line: 2, Location: "ten.mp3"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name, format, data, field string
+	}{
+		{"unknown format", "wpl", "", "format"},
+		{"m3u extinf without comma", models.PlaylistM3U8, "#EXTM3U\n#EXTINF:10 Title\n", "line 2"},
+		{"m3u invalid duration", models.PlaylistM3U8, "#EXTINF:ten,Title\n", "line 1"},
+		{"m3u line too long", models.PlaylistM3U8, "#EXTM3U\n" + strings.Repeat("a", maxLineLength+1), "line 2"},
+		{"pls without header", models.PlaylistPLS, "File1=a.mp3\n", "line 1"},
+		{"pls invalid entry number", models.PlaylistPLS, "[playlist]\nFileX=a.mp3\n", "line 2"},
+		{"pls invalid length", models.PlaylistPLS, "[playlist]\nLength1=long\n", "line 2"},
+		{"pls empty", models.PlaylistPLS, "\n; comment\n", "file"},
+		{"xspf wrong root", models.PlaylistXSPF, "<rss/>", "file"},
+		{"xspf empty", models.PlaylistXSPF, "", "file"},
+		{"xspf unclosed", models.PlaylistXSPF, "<playlist><trackList>", "line 1"},
+		{"xspf syntax", models.PlaylistXSPF, "<playlist>\n<trackList>\n<track></trak>", "line 3"},
+		{"xspf invalid duration", models.PlaylistXSPF, "<playlist><trackList>\n<track><duration>1s</duration></track></trackList></playlist>", "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(tt.format, strings.NewReader(tt.data))
+			var validationErr *apperror.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Read() error = %v, want a validation error", err)
+			}
+			if _, ok := validationErr.Fields[tt.field]; !ok {
+				t.Errorf("error fields = %v, want %q", validationErr.Fields, tt.field)
+			}
+		})
+	}
+}
+
+func TestReadStreamError(t *testing.T) {
+	streamErr := errors.New("connection reset")
+	for _, format := range []string{models.PlaylistM3U8, models.PlaylistPLS, models.PlaylistXSPF} {
+		_, _, err := Read(format, iotest.ErrReader(streamErr))
+		if !errors.Is(err, streamErr) {
+			t.Errorf("%s: Read() error = %v, want the stream error", format, err)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package playlistfile
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// maxLineLength — максимальная длина строки M3U и PLS
+const maxLineLength = 64 << 10
+
+// Read разбирает файл плейлиста и возвращает его название (если оно есть в файле) и записи по порядку.
+// Ошибки формата возвращаются как *apperror.ValidationError: поле "line N" или "file";
+// ошибки чтения потока возвращаются как есть.
+func Read(format string, r io.Reader) (string, []models.PlaylistItem, error) {
+	switch format {
+	case models.PlaylistM3U8:
+		return readM3U(r)
+	case models.PlaylistXSPF:
+		return readXSPF(r)
+	case models.PlaylistPLS:
+		return readPLS(r)
+	default:
+		return "", nil, apperror.NewValidationError("format", fmt.Sprintf("unsupported playlist format %q, expected m3u8, xspf or pls", format))
+	}
+}
+
+// splitTitle делит «Исполнитель - Название» по первому « - »; без разделителя вся строка — название
+func splitTitle(display string) (string, string) {
+	group, song, ok := strings.Cut(display, " - ")
+	if !ok {
+		return "", strings.TrimSpace(display)
+	}
+	return strings.TrimSpace(group), strings.TrimSpace(song)
+}
+
+// lineError — ошибка формата в строке файла (нумерация с 1)
+func lineError(line int, message string) error {
+	return apperror.NewValidationError(fmt.Sprintf("line %d", line), message)
+}
+
+// scanLines читает файл по строкам без BOM, пробелов по краям и \r; fn получает номер строки
+func scanLines(r io.Reader, fn func(number int, line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineLength)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := scanner.Text()
+		if number == 1 {
+			line = strings.TrimPrefix(line, "\ufeff") // BOM
+		}
+		if err := fn(number, strings.TrimSpace(line)); err != nil {
+			return err
+		}
+	}
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		return lineError(number+1, fmt.Sprintf("line is longer than %d bytes", maxLineLength))
+	}
+	return scanner.Err()
+}
+
+// readM3U читает M3U и расширенный M3U: #EXTINF:<секунды>[ атрибуты],<Исполнитель - Название>
+// относится к следующей строке с путём или URL. Прочие директивы пропускаются; #EXTINF без пути
+// в конце файла тоже становится записью, её можно сопоставить по названию.
+func readM3U(r io.Reader) (string, []models.PlaylistItem, error) {
+	var title string
+	items := make([]models.PlaylistItem, 0)
+	var pending *models.PlaylistItem
+
+	err := scanLines(r, func(number int, line string) error {
+		switch {
+		case line == "":
+			return nil
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info, display, ok := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			if !ok {
+				return lineError(number, "#EXTINF must be followed by duration and a comma")
+			}
+			if pending != nil {
+				items = append(items, *pending)
+			}
+			item := models.PlaylistItem{Line: number}
+			if fields := strings.Fields(info); len(fields) > 0 {
+				seconds, err := strconv.ParseFloat(fields[0], 64)
+				if err != nil {
+					return lineError(number, fmt.Sprintf("invalid duration %q", fields[0]))
+				}
+				if seconds > 0 {
+					item.Duration = int(math.Round(seconds))
+				}
+			}
+			item.Group, item.Song = splitTitle(display)
+			pending = &item
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#"):
+			// #EXTM3U, #EXTGRP, комментарии и прочие директивы
+		default:
+			item := models.PlaylistItem{Line: number}
+			if pending != nil {
+				item = *pending
+				pending = nil
+			}
+			item.Location = line
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if pending != nil {
+		items = append(items, *pending)
+	}
+	return title, items, nil
+}
+
+// readPLS читает PLS: секция [playlist] с ключами FileN, TitleN и LengthN. Записи упорядочены по N;
+// номера могут идти с пропусками. NumberOfEntries и Version не проверяются.
+func readPLS(r io.Reader) (string, []models.PlaylistItem, error) {
+	var title string
+	started := false
+	entries := make(map[int]*models.PlaylistItem)
+
+	err := scanLines(r, func(number int, line string) error {
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			return nil
+		}
+		if !started {
+			if !strings.EqualFold(line, "[playlist]") {
+				return lineError(number, "PLS file must start with [playlist]")
+			}
+			started = true
+			return nil
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return lineError(number, "expected Key=Value")
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if key == "x-title" {
+			title = value
+			return nil
+		}
+
+		var name string
+		for _, prefix := range []string{"file", "title", "length"} {
+			if strings.HasPrefix(key, prefix) {
+				name = prefix
+				break
+			}
+		}
+		if name == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(key, name))
+		if err != nil || n < 1 {
+			return lineError(number, fmt.Sprintf("invalid entry number in %q", key))
+		}
+
+		entry, ok := entries[n]
+		if !ok {
+			entry = &models.PlaylistItem{Line: number}
+			entries[n] = entry
+		}
+		switch name {
+		case "file":
+			entry.Location = value
+		case "title":
+			entry.Group, entry.Song = splitTitle(value)
+		case "length":
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return lineError(number, fmt.Sprintf("invalid length %q", value))
+			}
+			if seconds > 0 {
+				entry.Duration = seconds
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if !started {
+		return "", nil, apperror.NewValidationError("file", "PLS file is empty")
+	}
+
+	numbers := make([]int, 0, len(entries))
+	for n := range entries {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	items := make([]models.PlaylistItem, 0, len(numbers))
+	for _, n := range numbers {
+		items = append(items, *entries[n])
+	}
+	return title, items, nil
+}
+
+// xspfTrack — элемент <track>; из нескольких <location> берётся первый
+type xspfTrack struct {
+	Locations []string `xml:"location"`
+	Creator   string   `xml:"creator"`
+	Title     string   `xml:"title"`
+	Duration  string   `xml:"duration"`
+}
+
+// readXSPF читает XSPF: <playlist><title/><trackList><track/>...</trackList></playlist>.
+// Длительность в файле — в миллисекундах, в записи — в секундах.
+func readXSPF(r io.Reader) (string, []models.PlaylistItem, error) {
+	var title string
+	items := make([]models.PlaylistItem, 0)
+	stream := &streamReader{r: r}
+	dec := xml.NewDecoder(stream)
+	path := make([]string, 0, 4)
+
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, xmlError(dec, stream, err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if len(path) == 0 && element.Name.Local != "playlist" {
+				return "", nil, apperror.NewValidationError("file", "XSPF root element must be <playlist>")
+			}
+			switch {
+			case len(path) == 1 && element.Name.Local == "title":
+				if err := dec.DecodeElement(&title, &element); err != nil {
+					return "", nil, xmlError(dec, stream, err)
+				}
+				title = strings.TrimSpace(title)
+				continue
+			case len(path) == 2 && path[1] == "trackList" && element.Name.Local == "track":
+				line, _ := dec.InputPos()
+				var track xspfTrack
+				if err := dec.DecodeElement(&track, &element); err != nil {
+					return "", nil, xmlError(dec, stream, err)
+				}
+				item, err := xspfItem(line, track)
+				if err != nil {
+					return "", nil, err
+				}
+				items = append(items, item)
+				continue
+			}
+			path = append(path, element.Name.Local)
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+
+	if dec.InputOffset() == 0 || len(path) != 0 {
+		return "", nil, apperror.NewValidationError("file", "XSPF file is empty or incomplete")
+	}
+	return title, items, nil
+}
+
+func xspfItem(line int, track xspfTrack) (models.PlaylistItem, error) {
+	item := models.PlaylistItem{
+		Line:  line,
+		Group: strings.TrimSpace(track.Creator),
+		Song:  strings.TrimSpace(track.Title),
+	}
+	if len(track.Locations) > 0 {
+		item.Location = strings.TrimSpace(track.Locations[0])
+	}
+	if raw := strings.TrimSpace(track.Duration); raw != "" {
+		ms, err := strconv.Atoi(raw)
+		if err != nil {
+			return models.PlaylistItem{}, lineError(line, fmt.Sprintf("invalid duration %q", raw))
+		}
+		if ms > 0 {
+			item.Duration = (ms + 500) / 1000
+		}
+	}
+	return item, nil
+}
+
+// streamReader запоминает ошибку чтения, чтобы отличить её от ошибок разметки
+type streamReader struct {
+	r   io.Reader
+	err error
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != nil && err != io.EOF {
+		s.err = err
+	}
+	return n, err
+}
+
+// xmlError возвращает ошибку чтения потока как есть, а ошибки разметки и кодировки — как ошибку файла
+func xmlError(dec *xml.Decoder, stream *streamReader, err error) error {
+	if stream.err != nil {
+		return stream.err
+	}
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return lineError(syntaxErr.Line, syntaxErr.Msg)
+	}
+	line, _ := dec.InputPos()
+	return lineError(line, err.Error())
+}
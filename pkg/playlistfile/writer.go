@@ -0,0 +1,182 @@
+// Package playlistfile записывает и читает файлы плейлистов для плееров: расширенный M3U8, XSPF и PLS.
+package playlistfile
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// ContentTypes — Content-Type ответа для каждого формата
+var ContentTypes = map[string]string{
+	models.PlaylistM3U8: "audio/x-mpegurl; charset=utf-8",
+	models.PlaylistXSPF: "application/xspf+xml",
+	models.PlaylistPLS:  "audio/x-scpls",
+}
+
+// Writer записывает песни плейлиста по одной; набор методов совпадает с songexport.Writer
+type Writer interface {
+	Write(song models.Song) error
+	Flush() error
+	Close() error
+}
+
+// flusher — поток, который умеет отправлять буферизованные данные клиенту
+type flusher interface {
+	Flush() error
+}
+
+// NewWriter создаёт Writer для формата m3u8, xspf или pls; title — название плейлиста, может быть пустым.
+// M3U8 и PLS перечисляют файлы, поэтому песни без ссылки в них пропускаются; в XSPF ссылка необязательна.
+func NewWriter(format string, w io.Writer, title string) (Writer, error) {
+	base := baseWriter{w: bufio.NewWriter(w), dst: w}
+	switch format {
+	case models.PlaylistM3U8:
+		return newM3UWriter(base, title)
+	case models.PlaylistXSPF:
+		return newXSPFWriter(base, title)
+	case models.PlaylistPLS:
+		return newPLSWriter(base, title)
+	default:
+		return nil, apperror.NewValidationError("format", fmt.Sprintf("unsupported playlist format %q, expected m3u8, xspf or pls", format))
+	}
+}
+
+// displayTitle — строка «Исполнитель - Название», как её показывают плееры
+func displayTitle(song models.Song) string {
+	if song.GroupName == "" {
+		return song.SongName
+	}
+	return song.GroupName + " - " + song.SongName
+}
+
+// lineBreaks заменяет переводы строк: в M3U и PLS значение занимает ровно одну строку
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+type baseWriter struct {
+	w   *bufio.Writer
+	dst io.Writer
+}
+
+func (w baseWriter) Flush() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	if f, ok := w.dst.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// m3uWriter пишет расширенный M3U в UTF-8: #EXTINF с длительностью (-1 — неизвестна) и «Исполнитель - Название»
+type m3uWriter struct {
+	baseWriter
+}
+
+func newM3UWriter(base baseWriter, title string) (*m3uWriter, error) {
+	base.w.WriteString("#EXTM3U\n")
+	if title != "" {
+		base.w.WriteString("#PLAYLIST:" + lineBreaks.Replace(title) + "\n")
+	}
+	return &m3uWriter{base}, nil
+}
+
+func (w *m3uWriter) Write(song models.Song) error {
+	if song.Link == "" {
+		return nil
+	}
+	duration := -1
+	if song.Duration > 0 {
+		duration = song.Duration
+	}
+	fmt.Fprintf(w.w, "#EXTINF:%d,%s\n", duration, lineBreaks.Replace(displayTitle(song)))
+	_, err := w.w.WriteString(lineBreaks.Replace(song.Link) + "\n")
+	return err
+}
+
+func (w *m3uWriter) Close() error {
+	return w.Flush()
+}
+
+// plsWriter пишет PLS версии 2; NumberOfEntries известно только в конце, поэтому идёт после записей
+type plsWriter struct {
+	baseWriter
+	count int
+}
+
+func newPLSWriter(base baseWriter, title string) (*plsWriter, error) {
+	base.w.WriteString("[playlist]\n")
+	if title != "" {
+		base.w.WriteString("X-Title=" + lineBreaks.Replace(title) + "\n")
+	}
+	return &plsWriter{baseWriter: base}, nil
+}
+
+func (w *plsWriter) Write(song models.Song) error {
+	if song.Link == "" {
+		return nil
+	}
+	w.count++
+	duration := -1
+	if song.Duration > 0 {
+		duration = song.Duration
+	}
+	fmt.Fprintf(w.w, "File%d=%s\n", w.count, lineBreaks.Replace(song.Link))
+	fmt.Fprintf(w.w, "Title%d=%s\n", w.count, lineBreaks.Replace(displayTitle(song)))
+	_, err := fmt.Fprintf(w.w, "Length%d=%d\n", w.count, duration)
+	return err
+}
+
+func (w *plsWriter) Close() error {
+	fmt.Fprintf(w.w, "NumberOfEntries=%d\nVersion=2\n", w.count)
+	return w.Flush()
+}
+
+// xspfWriter пишет XSPF 1: исполнитель в <creator>, длительность в миллисекундах
+type xspfWriter struct {
+	baseWriter
+}
+
+func newXSPFWriter(base baseWriter, title string) (*xspfWriter, error) {
+	base.w.WriteString(xml.Header)
+	base.w.WriteString(`<playlist version="1" xmlns="http://xspf.org/ns/0/">` + "\n")
+	if title != "" {
+		writeElement(base.w, "  ", "title", title)
+	}
+	base.w.WriteString("  <trackList>\n")
+	return &xspfWriter{base}, nil
+}
+
+// writeElement пишет <name>value</name> с экранированием значения
+func writeElement(w *bufio.Writer, indent, name, value string) {
+	w.WriteString(indent + "<" + name + ">")
+	xml.EscapeText(w, []byte(value))
+	w.WriteString("</" + name + ">\n")
+}
+
+func (w *xspfWriter) Write(song models.Song) error {
+	w.w.WriteString("    <track>\n")
+	if song.Link != "" {
+		writeElement(w.w, "      ", "location", song.Link)
+	}
+	if song.GroupName != "" {
+		writeElement(w.w, "      ", "creator", song.GroupName)
+	}
+	writeElement(w.w, "      ", "title", song.SongName)
+	if song.Duration > 0 {
+		writeElement(w.w, "      ", "duration", strconv.Itoa(song.Duration*1000))
+	}
+	_, err := w.w.WriteString("    </track>\n")
+	return err
+}
+
+func (w *xspfWriter) Close() error {
+	w.w.WriteString("  </trackList>\n</playlist>\n")
+	return w.Flush()
+}
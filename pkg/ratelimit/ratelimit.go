@@ -0,0 +1,109 @@
+// Package ratelimit ограничивает частоту запросов алгоритмом token bucket.
+// Состояние ведёр хранит Store: MemoryStore держит его в памяти процесса, для нескольких
+// экземпляров сервиса можно подключить общее хранилище с той же семантикой.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit — параметры ведра: PerMinute токенов пополняется в минуту, Burst — ёмкость ведра
+// (сколько запросов можно сделать подряд после паузы)
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+// Result — итог попытки взять токен. Reset — через сколько ведро снова будет полным,
+// RetryAfter — через сколько появится токен, если запрос отклонён.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store хранит ведра по ключу. Take пополняет ведро key за прошедшее время и берёт из него один токен.
+// Параметры limit передаются при каждом вызове, поэтому их изменение действует сразу.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	rate     float64
+	capacity float64
+}
+
+// MemoryStore — Store в памяти процесса. Ведра, которые не использовались дольше idleTTL
+// и уже заполнились, удаляются.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	idleTTL   time.Duration
+	lastSweep time.Time
+	now       func() time.Time // подменяется в тестах
+}
+
+func NewMemoryStore(idleTTL time.Duration) *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		idleTTL: idleTTL,
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	rate := float64(limit.PerMinute) / 60 // токенов в секунду
+	capacity := float64(limit.Burst)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	// Ёмкость могла уменьшиться после смены лимита
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated, b.rate, b.capacity = now, rate, capacity
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((capacity - b.tokens) / rate)
+	return result, nil
+}
+
+// sweep удаляет ведра, которые уже заполнились и не использовались дольше idleTTL:
+// новое ведро для того же ключа начнёт с того же полного состояния
+func (s *MemoryStore) sweep(now time.Time) {
+	if s.idleTTL <= 0 || now.Sub(s.lastSweep) < s.idleTTL {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		idle := now.Sub(b.updated)
+		if idle > s.idleTTL && b.tokens+idle.Seconds()*b.rate >= b.capacity {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock — управляемое время для MemoryStore
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestStore(idleTTL time.Duration) (*MemoryStore, *clock) {
+	c := &clock{now: time.Date(2025, 4, 30, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore(idleTTL)
+	store.now = func() time.Time { return c.now }
+	return store, c
+}
+
+func take(t *testing.T, store *MemoryStore, key string, limit Limit) Result {
+	t.Helper()
+	result, err := store.Take(context.Background(), key, limit)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	return result
+}
+
+func TestTake(t *testing.T) {
+	store, clock := newTestStore(0)
+	limit := Limit{PerMinute: 60, Burst: 3} // токен в секунду
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		want    Result
+	}{
+		{"full bucket", 0, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+		{"second", 0, Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}},
+		{"last token", 0, Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}},
+		{"empty bucket", 0, Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}},
+		{"partially refilled", 500 * time.Millisecond, Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 2500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}},
+		{"refilled token", 500 * time.Millisecond, Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}},
+		{"refill is capped at burst", time.Hour, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+	}
+
+	for _, step := range steps {
+		clock.advance(step.advance)
+		if got := take(t, store, "key", limit); got != step.want {
+			t.Errorf("%s: Take() = %+v, want %+v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestTakeKeysAreIndependent(t *testing.T) {
+	store, _ := newTestStore(0)
+	limit := Limit{PerMinute: 1, Burst: 1}
+
+	if !take(t, store, "a", limit).Allowed || take(t, store, "a", limit).Allowed {
+		t.Fatal("key a: want one allowed request")
+	}
+	if !take(t, store, "b", limit).Allowed {
+		t.Error("key b is limited by key a")
+	}
+}
+
+func TestTakeLimitChange(t *testing.T) {
+	store, clock := newTestStore(0)
+
+	take(t, store, "key", Limit{PerMinute: 600, Burst: 100})
+	// Ёмкость уменьшилась: лишние токены не сохраняются
+	got := take(t, store, "key", Limit{PerMinute: 60, Burst: 2})
+	if want := (Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}); got != want {
+		t.Errorf("after lowering the limit Take() = %+v, want %+v", got, want)
+	}
+
+	take(t, store, "key", Limit{PerMinute: 60, Burst: 2})
+	clock.advance(100 * time.Millisecond)
+	// Скорость пополнения выросла: токен появляется быстрее
+	got = take(t, store, "key", Limit{PerMinute: 600, Burst: 2})
+	if !got.Allowed {
+		t.Errorf("after raising the rate Take() = %+v, want allowed", got)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	store, clock := newTestStore(time.Minute)
+	fast := Limit{PerMinute: 60, Burst: 2}
+	slow := Limit{PerMinute: 1, Burst: 10}
+
+	take(t, store, "fast", fast)
+	for i := 0; i < 5; i++ {
+		take(t, store, "slow", slow)
+	}
+	clock.advance(2 * time.Minute)
+	take(t, store, "other", fast) // запускает очистку
+
+	if _, ok := store.buckets["fast"]; ok {
+		t.Error("idle full bucket was not removed")
+	}
+	// Ведро slow ещё не пополнилось: после удаления ключ получил бы полный лимит раньше времени
+	if _, ok := store.buckets["slow"]; !ok {
+		t.Error("idle bucket that is not full yet was removed")
+	}
+}
@@ -0,0 +1,432 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+type AlbumPostgres struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewAlbumPostgres(db *sqlx.DB, queryTimeout time.Duration) *AlbumPostgres {
+	return &AlbumPostgres{db: db, queryTimeout: queryTimeout}
+}
+
+// albumColumns — колонки альбома в порядке, который ожидает scanAlbum (выборка из albums al JOIN artists ar)
+const albumColumns = `al.id, al.title, al.artist_id, ar.name, al.release_date, al.release_date_precision,
+    al.type, COALESCE(al.cover_link, ''), (
+        SELECT COUNT(*) FROM album_tracks t JOIN songs s ON s.id = t.song_id
+        WHERE t.album_id = al.id AND s.deleted_at IS NULL
+    )`
+
+// scanAlbum читает строку, выбранную по albumColumns; extra — дополнительные колонки после них
+func scanAlbum(row rowScanner, album *models.Album, extra ...interface{}) error {
+	var releaseDate sql.NullTime
+	var precision sql.NullString
+
+	dest := []interface{}{&album.ID, &album.Title, &album.ArtistID, &album.Artist, &releaseDate, &precision,
+		&album.Type, &album.CoverLink, &album.TrackCount}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	album.ReleaseDate = models.ReleaseDate{}
+	if releaseDate.Valid {
+		album.ReleaseDate = models.NewReleaseDate(releaseDate.Time, models.DatePrecision(precision.String))
+	}
+	return nil
+}
+
+func (r *AlbumPostgres) AddAlbum(ctx context.Context, album models.Album) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return 0, queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	artistID, _, err := upsertArtist(ctx, tx, album.Artist)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"artist": album.Artist,
+		}).Errorf("Failed to resolve artist: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	query := `INSERT INTO albums (title, artist_id, release_date, release_date_precision, type, cover_link)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')) RETURNING id`
+
+	releaseDate, precision := releaseDateArgs(album.ReleaseDate)
+
+	var id int
+	err = tx.QueryRowContext(ctx, query, album.Title, artistID, releaseDate, precision, album.Type, album.CoverLink).Scan(&id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"title":  album.Title,
+			"artist": album.Artist,
+		}).Errorf("Failed to add album: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.Errorf("Failed to commit album: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id":  id,
+		"artist_id": artistID,
+		"title":     album.Title,
+		"type":      album.Type,
+	}).Debug("Album added successfully")
+	return id, nil
+}
+
+func (r *AlbumPostgres) GetAlbums(ctx context.Context, filter models.AlbumFilter) ([]models.Album, int, error) {
+	conditions := make([]string, 0)
+	values := make([]interface{}, 0)
+
+	if filter.ArtistID != 0 {
+		values = append(values, filter.ArtistID)
+		conditions = append(conditions, fmt.Sprintf("al.artist_id = $%d", len(values)))
+	}
+	if filter.Title != "" {
+		values = append(values, containsPattern(filter.Title))
+		conditions = append(conditions, fmt.Sprintf("al.title ILIKE $%d", len(values)))
+	}
+	if filter.Type != "" {
+		values = append(values, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("al.type = $%d", len(values)))
+	}
+	where := whereClause(conditions)
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM albums al %s", where)
+	if err := r.db.QueryRowContext(ctx, countQuery, values...).Scan(&total); err != nil {
+		logrus.Errorf("Failed to count albums: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM albums al
+        JOIN artists ar ON ar.id = al.artist_id
+        %s
+        ORDER BY al.release_date NULLS LAST, al.id
+        LIMIT $%d OFFSET $%d
+    `, albumColumns, where, len(values)+1, len(values)+2)
+
+	logrus.WithFields(logrus.Fields{
+		"where":  where,
+		"page":   filter.Page,
+		"limit":  filter.Limit,
+		"offset": offset,
+	}).Debug("Executing query to fetch albums")
+
+	rows, err := r.db.QueryContext(ctx, query, append(values, filter.Limit, offset)...)
+	if err != nil {
+		logrus.Errorf("Failed to execute albums query: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	albums := make([]models.Album, 0, filter.Limit)
+	for rows.Next() {
+		var album models.Album
+		if err := scanAlbum(rows, &album); err != nil {
+			logrus.Errorf("Failed to scan album: %v", err)
+			return nil, 0, queryError(ctx, err)
+		}
+		albums = append(albums, album)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating albums: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"retrieved_albums": len(albums),
+		"total":            total,
+	}).Debug("Successfully retrieved albums")
+
+	return albums, total, nil
+}
+
+// GetAlbum возвращает альбом вместе с трек-листом, упорядоченным по номеру диска и трека
+func (r *AlbumPostgres) GetAlbum(ctx context.Context, id int) (models.Album, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM albums al
+        JOIN artists ar ON ar.id = al.artist_id
+        WHERE al.id = $1
+    `, albumColumns)
+
+	var album models.Album
+	if err := scanAlbum(r.db.QueryRowContext(ctx, query, id), &album); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logrus.WithFields(logrus.Fields{
+				"album_id": id,
+			}).Warn("Album does not exist")
+			return models.Album{}, fmt.Errorf("album with id %d: %w", id, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to get album: %v", err)
+		return models.Album{}, queryError(ctx, err)
+	}
+
+	tracksQuery := fmt.Sprintf(`
+        SELECT %s, t.disc_number, t.track_number
+        FROM album_tracks t
+        JOIN songs ON songs.id = t.song_id
+        WHERE t.album_id = $1 AND songs.deleted_at IS NULL
+        ORDER BY t.disc_number, t.track_number
+    `, songColumns)
+
+	rows, err := r.db.QueryContext(ctx, tracksQuery, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to get album tracks: %v", err)
+		return models.Album{}, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	album.Tracks = make([]models.AlbumTrack, 0, album.TrackCount)
+	for rows.Next() {
+		var track models.AlbumTrack
+		if err := scanSong(rows, &track.Song, &track.Disc, &track.Track); err != nil {
+			logrus.Errorf("Failed to scan album track: %v", err)
+			return models.Album{}, queryError(ctx, err)
+		}
+		album.Tracks = append(album.Tracks, track)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating album tracks: %v", err)
+		return models.Album{}, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+		"tracks":   len(album.Tracks),
+	}).Debug("Successfully retrieved album")
+	return album, nil
+}
+
+// UpdateAlbum обновляет только переданные (непустые) поля альбома, аналогично UpdateSong
+func (r *AlbumPostgres) UpdateAlbum(ctx context.Context, id int, album models.Album) error {
+	setClauses := make([]string, 0)
+	values := make([]interface{}, 0)
+	valueIndex := 1
+
+	if album.Title != "" {
+		setClauses = append(setClauses, fmt.Sprintf("title = $%d", valueIndex))
+		values = append(values, album.Title)
+		valueIndex++
+	}
+	if !album.ReleaseDate.IsZero() {
+		releaseDate, precision := releaseDateArgs(album.ReleaseDate)
+		setClauses = append(setClauses, fmt.Sprintf("release_date = $%d, release_date_precision = $%d", valueIndex, valueIndex+1))
+		values = append(values, releaseDate, precision)
+		valueIndex += 2
+	}
+	if album.Type != "" {
+		setClauses = append(setClauses, fmt.Sprintf("type = $%d", valueIndex))
+		values = append(values, album.Type)
+		valueIndex++
+	}
+	if album.CoverLink != "" {
+		setClauses = append(setClauses, fmt.Sprintf("cover_link = $%d", valueIndex))
+		values = append(values, album.CoverLink)
+		valueIndex++
+	}
+
+	if len(setClauses) == 0 && album.Artist == "" {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Warn("No fields provided for update")
+		return apperror.NewValidationError("body", "no fields provided for update")
+	}
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	if album.Artist != "" {
+		artistID, _, err := upsertArtist(ctx, tx, album.Artist)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"album_id": id,
+				"artist":   album.Artist,
+			}).Errorf("Failed to resolve artist: %v", err)
+			return queryError(ctx, err)
+		}
+		setClauses = append(setClauses, fmt.Sprintf("artist_id = $%d", valueIndex))
+		values = append(values, artistID)
+		valueIndex++
+	}
+
+	query := fmt.Sprintf("UPDATE albums SET %s WHERE id = $%d", strings.Join(setClauses, ", "), valueIndex)
+	values = append(values, id)
+
+	res, err := tx.ExecContext(ctx, query, values...)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to update album: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Warn("No album found with the given ID")
+		return fmt.Errorf("album with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to commit album update: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id":       id,
+		"updated_fields": setClauses,
+	}).Info("Album updated successfully")
+	return nil
+}
+
+// SetAlbumTracks заменяет трек-лист альбома целиком
+func (r *AlbumPostgres) SetAlbumTracks(ctx context.Context, id int, tracks []models.TrackPosition) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем альбом, чтобы параллельные замены трек-листа не перемешались
+	var locked int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM albums WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("album with id %d: %w", id, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to lock album: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM album_tracks WHERE album_id = $1`, id); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to clear album tracks: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if len(tracks) > 0 {
+		songIDs := make([]int64, 0, len(tracks))
+		discs := make([]int64, 0, len(tracks))
+		numbers := make([]int64, 0, len(tracks))
+		for _, track := range tracks {
+			songIDs = append(songIDs, int64(track.SongID))
+			discs = append(discs, int64(track.Disc))
+			numbers = append(numbers, int64(track.Track))
+		}
+
+		query := `
+            INSERT INTO album_tracks (album_id, song_id, disc_number, track_number)
+            SELECT $1, t.song_id, t.disc_number, t.track_number
+            FROM unnest($2::int[], $3::int[], $4::int[]) AS t(song_id, disc_number, track_number)
+        `
+		_, err := tx.ExecContext(ctx, query, id, pq.Array(songIDs), pq.Array(discs), pq.Array(numbers))
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"album_id": id,
+			}).Errorf("Failed to insert album tracks: %v", err)
+			return queryError(ctx, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to commit album tracks: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+		"tracks":   len(tracks),
+	}).Info("Album tracklist replaced")
+	return nil
+}
+
+func (r *AlbumPostgres) DeleteAlbum(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM albums WHERE id = $1`, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Errorf("Failed to delete album: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"album_id": id,
+		}).Warn("No album found with the given ID")
+		return fmt.Errorf("album with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+	}).Info("Album deleted successfully")
+	return nil
+}
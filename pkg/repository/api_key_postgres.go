@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+type APIKeyPostgres struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewAPIKeyPostgres(db *sqlx.DB, queryTimeout time.Duration) *APIKeyPostgres {
+	return &APIKeyPostgres{db: db, queryTimeout: queryTimeout}
+}
+
+// apiKeyColumns — колонки ключа в порядке, который ожидает scanAPIKey
+const apiKeyColumns = `id, name, prefix, scopes, rate_limit, burst, created_by, created_at, last_used_at, revoked_at`
+
+func scanAPIKey(row rowScanner, key *models.APIKey) error {
+	return row.Scan(&key.ID, &key.Name, &key.Prefix, pq.Array(&key.Scopes), &key.RateLimit, &key.Burst,
+		&key.CreatedBy, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt)
+}
+
+// AddAPIKey сохраняет ключ и возвращает его с id и временем создания
+func (r *APIKeyPostgres) AddAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+        INSERT INTO api_keys (name, prefix, key_hash, scopes, rate_limit, burst, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING %s
+    `, apiKeyColumns)
+
+	var created models.APIKey
+	err := scanAPIKey(r.db.QueryRowContext(ctx, query, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes),
+		key.RateLimit, key.Burst, key.CreatedBy), &created)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name": key.Name,
+		}).Errorf("Failed to add API key: %v", err)
+		return models.APIKey{}, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"api_key_id": created.ID,
+		"name":       created.Name,
+		"scopes":     created.Scopes,
+	}).Info("API key added successfully")
+	return created, nil
+}
+
+// GetAPIKeys возвращает все ключи, включая отозванные, новые первыми
+func (r *APIKeyPostgres) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM api_keys ORDER BY id DESC`, apiKeyColumns))
+	if err != nil {
+		logrus.Errorf("Failed to execute API keys query: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	keys := make([]models.APIKey, 0)
+	for rows.Next() {
+		var key models.APIKey
+		if err := scanAPIKey(rows, &key); err != nil {
+			logrus.Errorf("Failed to scan API key: %v", err)
+			return nil, queryError(ctx, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating API keys: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	return keys, nil
+}
+
+// GetActiveAPIKey ищет неотозванный ключ по хешу и отмечает время его использования.
+// Время обновляется не чаще раза в минуту, чтобы не писать в базу на каждый запрос.
+func (r *APIKeyPostgres) GetActiveAPIKey(ctx context.Context, keyHash string) (models.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var key models.APIKey
+	query := fmt.Sprintf(`SELECT %s FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`, apiKeyColumns)
+	if err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash), &key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, fmt.Errorf("api key: %w", apperror.ErrNotFound)
+		}
+		logrus.Errorf("Failed to get API key: %v", err)
+		return models.APIKey{}, queryError(ctx, err)
+	}
+
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > time.Minute {
+		if _, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = now() WHERE id = $1`, key.ID); err != nil {
+			// Время использования справочное, из-за него запрос не отклоняем
+			logrus.WithFields(logrus.Fields{
+				"api_key_id": key.ID,
+			}).Warnf("Failed to update API key usage time: %v", err)
+		}
+	}
+	return key, nil
+}
+
+// RevokeAPIKey отзывает ключ; отозванный ключ остаётся в списке с revokedAt
+func (r *APIKeyPostgres) RevokeAPIKey(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"api_key_id": id,
+		}).Errorf("Failed to revoke API key: %v", err)
+		return queryError(ctx, err)
+	}
+	return r.requireAffected(res, id)
+}
+
+func (r *APIKeyPostgres) SetAPIKeyLimits(ctx context.Context, id, rateLimit, burst int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE api_keys SET rate_limit = $1, burst = $2 WHERE id = $3 AND revoked_at IS NULL
+    `, rateLimit, burst, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"api_key_id": id,
+		}).Errorf("Failed to set API key limits: %v", err)
+		return queryError(ctx, err)
+	}
+	return r.requireAffected(res, id)
+}
+
+// requireAffected превращает изменение ни одной строки в ErrNotFound: ключа нет или он уже отозван
+func (r *APIKeyPostgres) requireAffected(res sql.Result, id int) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"api_key_id": id,
+		}).Warn("No active API key found with the given ID")
+		return fmt.Errorf("active api key with id %d: %w", id, apperror.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+type ArtistPostgres struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewArtistPostgres(db *sqlx.DB, queryTimeout time.Duration) *ArtistPostgres {
+	return &ArtistPostgres{db: db, queryTimeout: queryTimeout}
+}
+
+// artistColumns — колонки исполнителя в порядке, который ожидает Scan, вместе с числом песен
+const artistColumns = `a.id, a.name, (SELECT COUNT(*) FROM songs s WHERE s.artist_id = a.id AND s.deleted_at IS NULL)`
+
+// upsertArtist возвращает исполнителя с таким же нормализованным названием, создавая его при необходимости.
+// Название существующего исполнителя не меняется — оно и становится group_name песни.
+func upsertArtist(ctx context.Context, q sqlx.QueryerContext, name string) (int, string, error) {
+	query := `
+        INSERT INTO artists (name) VALUES ($1)
+        ON CONFLICT (normalized_name) DO UPDATE SET name = artists.name
+        RETURNING id, name
+    `
+
+	var id int
+	var canonical string
+	if err := q.QueryRowxContext(ctx, query, name).Scan(&id, &canonical); err != nil {
+		return 0, "", err
+	}
+	return id, canonical, nil
+}
+
+func (r *ArtistPostgres) AddArtist(ctx context.Context, name string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `INSERT INTO artists (name) VALUES ($1) RETURNING id`, name).Scan(&id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name": name,
+		}).Errorf("Failed to add artist: %v", err)
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("artist %q already exists: %w", name, apperror.ErrConflict)
+		}
+		return 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": id,
+		"name":      name,
+	}).Debug("Artist added successfully")
+	return id, nil
+}
+
+func (r *ArtistPostgres) GetArtists(ctx context.Context, filter models.ArtistFilter) ([]models.Artist, int, error) {
+	where := ""
+	values := make([]interface{}, 0)
+	if filter.Name != "" {
+		where = "WHERE a.name ILIKE $1"
+		values = append(values, containsPattern(filter.Name))
+	}
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM artists a %s", where)
+	if err := r.db.QueryRowContext(ctx, countQuery, values...).Scan(&total); err != nil {
+		logrus.Errorf("Failed to count artists: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM artists a
+        %s
+        ORDER BY a.normalized_name, a.id
+        LIMIT $%d OFFSET $%d
+    `, artistColumns, where, len(values)+1, len(values)+2)
+
+	logrus.WithFields(logrus.Fields{
+		"name":   filter.Name,
+		"page":   filter.Page,
+		"limit":  filter.Limit,
+		"offset": offset,
+	}).Debug("Executing query to fetch artists")
+
+	rows, err := r.db.QueryContext(ctx, query, append(values, filter.Limit, offset)...)
+	if err != nil {
+		logrus.Errorf("Failed to execute artists query: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	artists := make([]models.Artist, 0, filter.Limit)
+	for rows.Next() {
+		var artist models.Artist
+		if err := rows.Scan(&artist.ID, &artist.Name, &artist.SongCount); err != nil {
+			logrus.Errorf("Failed to scan artist: %v", err)
+			return nil, 0, queryError(ctx, err)
+		}
+		artists = append(artists, artist)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating artists: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"retrieved_artists": len(artists),
+		"total":             total,
+	}).Debug("Successfully retrieved artists")
+
+	return artists, total, nil
+}
+
+func (r *ArtistPostgres) GetArtist(ctx context.Context, id int) (models.Artist, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT %s FROM artists a WHERE a.id = $1`, artistColumns)
+
+	var artist models.Artist
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&artist.ID, &artist.Name, &artist.SongCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logrus.WithFields(logrus.Fields{
+				"artist_id": id,
+			}).Warn("Artist does not exist")
+			return models.Artist{}, fmt.Errorf("artist with id %d: %w", id, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Errorf("Failed to get artist: %v", err)
+		return models.Artist{}, queryError(ctx, err)
+	}
+	return artist, nil
+}
+
+// UpdateArtist переименовывает исполнителя и обновляет group_name всех его песен в одной транзакции
+func (r *ArtistPostgres) UpdateArtist(ctx context.Context, id int, name string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE artists SET name = $1 WHERE id = $2`, name, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+			"name":      name,
+		}).Errorf("Failed to rename artist: %v", err)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("artist %q already exists: %w", name, apperror.ErrConflict)
+		}
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Warn("No artist found with the given ID")
+		return fmt.Errorf("artist with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	before, err := lockArtistSongs(ctx, tx, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Errorf("Failed to load songs of renamed artist: %v", err)
+		return queryError(ctx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE songs SET group_name = $1, version = version + 1 WHERE artist_id = $2`, name, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Errorf("Failed to update songs of renamed artist: %v", err)
+		return queryError(ctx, err)
+	}
+
+	// Переименование меняет group_name каждой песни, поэтому у каждой записывается ревизия
+	after, err := lockArtistSongs(ctx, tx, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Errorf("Failed to load songs of renamed artist: %v", err)
+		return queryError(ctx, err)
+	}
+	for songID, snapshot := range after {
+		if err := recordRevision(ctx, tx, songID, models.RevisionUpdate, before[songID], snapshot, nil); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"artist_id": id,
+				"song_id":   songID,
+			}).Errorf("Failed to record revision: %v", err)
+			return queryError(ctx, err)
+		}
+	}
+	songs := len(after)
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Errorf("Failed to commit artist rename: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id":     id,
+		"name":          name,
+		"updated_songs": songs,
+	}).Info("Artist renamed successfully")
+	return nil
+}
+
+// DeleteArtist удаляет исполнителя без песен; если песни есть, возвращается ErrConflict
+func (r *ArtistPostgres) DeleteArtist(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM artists WHERE id = $1`, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Errorf("Failed to delete artist: %v", err)
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("artist with id %d still has songs: %w", id, apperror.ErrConflict)
+		}
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"artist_id": id,
+		}).Warn("No artist found with the given ID")
+		return fmt.Errorf("artist with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": id,
+	}).Info("Artist deleted successfully")
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+type GenrePostgres struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewGenrePostgres(db *sqlx.DB, queryTimeout time.Duration) *GenrePostgres {
+	return &GenrePostgres{db: db, queryTimeout: queryTimeout}
+}
+
+func (r *GenrePostgres) AddGenre(ctx context.Context, genre models.Genre) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `INSERT INTO genres (name, parent_id) VALUES ($1, $2) RETURNING id`,
+		genre.Name, genre.ParentID).Scan(&id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name":      genre.Name,
+			"parent_id": genre.ParentID,
+		}).Errorf("Failed to add genre: %v", err)
+		if isForeignKeyViolation(err) {
+			return 0, apperror.NewValidationError("parentId", "parent genre does not exist")
+		}
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("genre %q already exists: %w", genre.Name, apperror.ErrConflict)
+		}
+		return 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"genre_id": id,
+		"name":     genre.Name,
+	}).Debug("Genre added successfully")
+	return id, nil
+}
+
+func (r *GenrePostgres) GetGenres(ctx context.Context) ([]models.Genre, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, parent_id FROM genres ORDER BY lower(name), id`)
+	if err != nil {
+		logrus.Errorf("Failed to get genres: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	genres := make([]models.Genre, 0)
+	for rows.Next() {
+		var genre models.Genre
+		if err := rows.Scan(&genre.ID, &genre.Name, &genre.ParentID); err != nil {
+			logrus.Errorf("Failed to scan genre: %v", err)
+			return nil, queryError(ctx, err)
+		}
+		genres = append(genres, genre)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return genres, nil
+}
+
+// DeleteGenre удаляет жанр вместе с его привязками к песням; жанр с поджанрами удалить нельзя
+func (r *GenrePostgres) DeleteGenre(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM genres WHERE id = $1`, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"genre_id": id,
+		}).Errorf("Failed to delete genre: %v", err)
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("genre with id %d has subgenres: %w", id, apperror.ErrConflict)
+		}
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("genre with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"genre_id": id,
+	}).Info("Genre deleted successfully")
+	return nil
+}
+
+// AttachGenre привязывает жанр к песне; повторная привязка ничего не меняет
+func (r *GenrePostgres) AttachGenre(ctx context.Context, songID, genreID int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO song_genres (song_id, genre_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, songID, genreID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":  songID,
+			"genre_id": genreID,
+		}).Errorf("Failed to attach genre: %v", err)
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			if pqErr.Constraint == "song_genres_genre_id_fkey" {
+				return fmt.Errorf("genre with id %d: %w", genreID, apperror.ErrNotFound)
+			}
+			return fmt.Errorf("song with id %d: %w", songID, apperror.ErrNotFound)
+		}
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  songID,
+		"genre_id": genreID,
+	}).Debug("Genre attached to song")
+	return nil
+}
+
+func (r *GenrePostgres) DetachGenre(ctx context.Context, songID, genreID int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM song_genres WHERE song_id = $1 AND genre_id = $2`, songID, genreID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":  songID,
+			"genre_id": genreID,
+		}).Errorf("Failed to detach genre: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("genre %d on song %d: %w", genreID, songID, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  songID,
+		"genre_id": genreID,
+	}).Debug("Genre detached from song")
+	return nil
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
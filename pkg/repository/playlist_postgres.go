@@ -0,0 +1,587 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+type PlaylistPostgres struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewPlaylistPostgres(db *sqlx.DB, queryTimeout time.Duration) *PlaylistPostgres {
+	return &PlaylistPostgres{db: db, queryTimeout: queryTimeout}
+}
+
+// playlistSelect выбирает плейлисты с числом записей и общей длительностью в порядке, который ожидает scanPlaylist
+const playlistSelect = `
+    SELECT p.id, p.name, p.created_at, p.updated_at, stats.entries, stats.duration, stats.unknown
+    FROM playlists p
+    CROSS JOIN LATERAL (
+        SELECT COUNT(*) AS entries, COALESCE(SUM(s.duration), 0) AS duration,
+            COUNT(*) FILTER (WHERE s.duration IS NULL) AS unknown
+        FROM playlist_entries e
+        JOIN songs s ON s.id = e.song_id
+        WHERE e.playlist_id = p.id
+    ) stats`
+
+func scanPlaylist(row rowScanner, playlist *models.Playlist) error {
+	return row.Scan(&playlist.ID, &playlist.Name, &playlist.CreatedAt, &playlist.UpdatedAt,
+		&playlist.EntryCount, &playlist.Duration, &playlist.UnknownDurations)
+}
+
+func (r *PlaylistPostgres) AddPlaylist(ctx context.Context, name string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `INSERT INTO playlists (name) VALUES ($1) RETURNING id`, name).Scan(&id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name": name,
+		}).Errorf("Failed to add playlist: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"name":        name,
+	}).Debug("Playlist added successfully")
+	return id, nil
+}
+
+func (r *PlaylistPostgres) GetPlaylists(ctx context.Context, filter models.PlaylistFilter) ([]models.Playlist, int, error) {
+	where := ""
+	values := make([]interface{}, 0)
+	if filter.Name != "" {
+		where = "WHERE p.name ILIKE $1"
+		values = append(values, containsPattern(filter.Name))
+	}
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM playlists p %s", where)
+	if err := r.db.QueryRowContext(ctx, countQuery, values...).Scan(&total); err != nil {
+		logrus.Errorf("Failed to count playlists: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query := fmt.Sprintf(`%s
+        %s
+        ORDER BY p.updated_at DESC, p.id DESC
+        LIMIT $%d OFFSET $%d
+    `, playlistSelect, where, len(values)+1, len(values)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(values, filter.Limit, offset)...)
+	if err != nil {
+		logrus.Errorf("Failed to execute playlists query: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	playlists := make([]models.Playlist, 0, filter.Limit)
+	for rows.Next() {
+		var playlist models.Playlist
+		if err := scanPlaylist(rows, &playlist); err != nil {
+			logrus.Errorf("Failed to scan playlist: %v", err)
+			return nil, 0, queryError(ctx, err)
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating playlists: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"retrieved_playlists": len(playlists),
+		"total":               total,
+	}).Debug("Successfully retrieved playlists")
+	return playlists, total, nil
+}
+
+// GetPlaylist возвращает плейлист вместе с записями по порядку позиций.
+// Песни из корзины остаются в плейлисте (с deletedAt), пока их не удалят окончательно.
+func (r *PlaylistPostgres) GetPlaylist(ctx context.Context, id int) (models.Playlist, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return models.Playlist{}, queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	// Итоги и записи читаются из одного снимка, чтобы длительность совпадала со списком песен
+	var playlist models.Playlist
+	if err := scanPlaylist(tx.QueryRowContext(ctx, playlistSelect+` WHERE p.id = $1`, id), &playlist); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logrus.WithFields(logrus.Fields{
+				"playlist_id": id,
+			}).Warn("Playlist does not exist")
+			return models.Playlist{}, fmt.Errorf("playlist with id %d: %w", id, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Errorf("Failed to get playlist: %v", err)
+		return models.Playlist{}, queryError(ctx, err)
+	}
+
+	// Колонки песни берутся из подзапроса: у playlist_entries тоже есть id
+	entriesQuery := fmt.Sprintf(`
+        SELECT s.*, e.id, e.position, e.added_at
+        FROM playlist_entries e
+        CROSS JOIN LATERAL (SELECT %s FROM songs WHERE songs.id = e.song_id) s
+        WHERE e.playlist_id = $1
+        ORDER BY e.position
+    `, songColumns)
+
+	rows, err := tx.QueryContext(ctx, entriesQuery, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Errorf("Failed to get playlist entries: %v", err)
+		return models.Playlist{}, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	playlist.Entries = make([]models.PlaylistEntry, 0, playlist.EntryCount)
+	for rows.Next() {
+		var entry models.PlaylistEntry
+		if err := scanSong(rows, &entry.Song, &entry.ID, &entry.Position, &entry.AddedAt); err != nil {
+			logrus.Errorf("Failed to scan playlist entry: %v", err)
+			return models.Playlist{}, queryError(ctx, err)
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating playlist entries: %v", err)
+		return models.Playlist{}, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"entries":     len(playlist.Entries),
+	}).Debug("Successfully retrieved playlist")
+	return playlist, nil
+}
+
+func (r *PlaylistPostgres) RenamePlaylist(ctx context.Context, id int, name string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `UPDATE playlists SET name = $1, updated_at = now() WHERE id = $2`, name, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Errorf("Failed to rename playlist: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Warn("No playlist found with the given ID")
+		return fmt.Errorf("playlist with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"name":        name,
+	}).Info("Playlist renamed successfully")
+	return nil
+}
+
+func (r *PlaylistPostgres) DeletePlaylist(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM playlists WHERE id = $1`, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Errorf("Failed to delete playlist: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Warn("No playlist found with the given ID")
+		return fmt.Errorf("playlist with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+	}).Info("Playlist deleted successfully")
+	return nil
+}
+
+// lockPlaylist блокирует плейлист до конца транзакции и возвращает число его записей.
+// Все изменения записей сначала берут эту блокировку, поэтому параллельные вставки и перемещения
+// выполняются по очереди и позиции остаются непрерывными.
+func lockPlaylist(ctx context.Context, tx *sqlx.Tx, id int) (int, error) {
+	var locked int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM playlists WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("playlist with id %d: %w", id, apperror.ErrNotFound)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM playlist_entries WHERE playlist_id = $1`, id).Scan(&count)
+	return count, err
+}
+
+// touchPlaylist отмечает время последнего изменения плейлиста
+func touchPlaylist(ctx context.Context, tx *sqlx.Tx, id int) error {
+	_, err := tx.ExecContext(ctx, `UPDATE playlists SET updated_at = now() WHERE id = $1`, id)
+	return err
+}
+
+// AddPlaylistEntry вставляет песню на позицию position (0 — в конец), сдвигая следующие записи,
+// и возвращает id и позицию новой записи
+func (r *PlaylistPostgres) AddPlaylistEntry(ctx context.Context, playlistID int, entry models.NewPlaylistEntry) (int, int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return 0, 0, queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	count, err := lockPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+		}).Errorf("Failed to lock playlist: %v", err)
+		return 0, 0, queryError(ctx, err)
+	}
+
+	position := entry.Position
+	if position == 0 {
+		position = count + 1
+	}
+	if position > count+1 {
+		return 0, 0, apperror.NewValidationError("position", fmt.Sprintf("must be between 1 and %d", count+1))
+	}
+
+	// Песни из корзины в плейлист не добавляются
+	var available bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`, entry.SongID).Scan(&available)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": entry.SongID,
+		}).Errorf("Failed to check song: %v", err)
+		return 0, 0, queryError(ctx, err)
+	}
+	if !available {
+		return 0, 0, apperror.NewValidationError("songId", fmt.Sprintf("song with id %d does not exist", entry.SongID))
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE playlist_entries SET position = position + 1
+        WHERE playlist_id = $1 AND position >= $2
+    `, playlistID, position)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+		}).Errorf("Failed to shift playlist entries: %v", err)
+		return 0, 0, queryError(ctx, err)
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO playlist_entries (playlist_id, song_id, position) VALUES ($1, $2, $3) RETURNING id
+    `, playlistID, entry.SongID, position).Scan(&id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+			"song_id":     entry.SongID,
+		}).Errorf("Failed to add playlist entry: %v", err)
+		return 0, 0, queryError(ctx, err)
+	}
+
+	if err := touchPlaylist(ctx, tx, playlistID); err != nil {
+		return 0, 0, queryError(ctx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+		}).Errorf("Failed to commit playlist entry: %v", err)
+		return 0, 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": playlistID,
+		"entry_id":    id,
+		"song_id":     entry.SongID,
+		"position":    position,
+	}).Info("Song added to playlist")
+	return id, position, nil
+}
+
+// MovePlaylistEntry переносит запись на позицию position; записи между старой и новой позицией
+// сдвигаются на одну в сторону освободившегося места
+func (r *PlaylistPostgres) MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	count, err := lockPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+		}).Errorf("Failed to lock playlist: %v", err)
+		return queryError(ctx, err)
+	}
+
+	var from int
+	err = tx.QueryRowContext(ctx, `SELECT position FROM playlist_entries WHERE id = $1 AND playlist_id = $2`, entryID, playlistID).Scan(&from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("entry %d in playlist %d: %w", entryID, playlistID, apperror.ErrNotFound)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+			"entry_id":    entryID,
+		}).Errorf("Failed to get playlist entry: %v", err)
+		return queryError(ctx, err)
+	}
+	if position > count {
+		return apperror.NewValidationError("position", fmt.Sprintf("must be between 1 and %d", count))
+	}
+	if position == from {
+		return nil
+	}
+
+	// Сдвигаем соседей и ставим запись на место одним запросом; совпадения позиций внутри
+	// транзакции допустимы, уникальность проверяется при фиксации
+	_, err = tx.ExecContext(ctx, `
+        UPDATE playlist_entries SET position = CASE
+            WHEN id = $2 THEN $4::int
+            WHEN $3::int < $4::int THEN position - 1
+            ELSE position + 1
+        END
+        WHERE playlist_id = $1 AND position BETWEEN LEAST($3::int, $4::int) AND GREATEST($3::int, $4::int)
+    `, playlistID, entryID, from, position)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+			"entry_id":    entryID,
+		}).Errorf("Failed to move playlist entry: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := touchPlaylist(ctx, tx, playlistID); err != nil {
+		return queryError(ctx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+		}).Errorf("Failed to commit playlist entry move: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": playlistID,
+		"entry_id":    entryID,
+		"from":        from,
+		"to":          position,
+	}).Info("Playlist entry moved")
+	return nil
+}
+
+// RemovePlaylistEntry удаляет запись; следующие записи сдвигает триггер playlist_entries_compact
+func (r *PlaylistPostgres) RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockPlaylist(ctx, tx, playlistID); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+		}).Errorf("Failed to lock playlist: %v", err)
+		return queryError(ctx, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM playlist_entries WHERE id = $1 AND playlist_id = $2`, entryID, playlistID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+			"entry_id":    entryID,
+		}).Errorf("Failed to remove playlist entry: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("entry %d in playlist %d: %w", entryID, playlistID, apperror.ErrNotFound)
+	}
+
+	if err := touchPlaylist(ctx, tx, playlistID); err != nil {
+		return queryError(ctx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": playlistID,
+		}).Errorf("Failed to commit playlist entry removal: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": playlistID,
+		"entry_id":    entryID,
+	}).Info("Playlist entry removed")
+	return nil
+}
+
+// playlistMatchQuery находит для каждой записи файла песню не из корзины: сначала по исполнителю
+// и названию без учёта регистра и лишних пробелов, затем по ссылке. Из нескольких совпадений берётся
+// песня с меньшим id. Возвращает id песни или 0 в порядке записей.
+const playlistMatchQuery = `
+    SELECT COALESCE(by_name.id, by_link.id, 0)
+    FROM unnest($1::text[], $2::text[], $3::text[]) WITH ORDINALITY AS k(grp, song, link, ord)
+    LEFT JOIN LATERAL (
+        SELECT s.id
+        FROM artists a
+        JOIN songs s ON s.artist_id = a.id
+        WHERE k.grp <> '' AND k.song <> ''
+            AND a.normalized_name = lower(btrim(regexp_replace(k.grp, '\s+', ' ', 'g')))
+            AND lower(btrim(s.song)) = lower(btrim(k.song))
+            AND s.deleted_at IS NULL
+        ORDER BY s.id
+        LIMIT 1
+    ) by_name ON true
+    LEFT JOIN LATERAL (
+        SELECT s.id
+        FROM songs s
+        WHERE by_name.id IS NULL AND k.link <> '' AND s.link = k.link AND s.deleted_at IS NULL
+        ORDER BY s.id
+        LIMIT 1
+    ) by_link ON true
+    ORDER BY k.ord`
+
+// ImportPlaylist создаёт плейлист name из записей файла, сопоставленных с песнями каталога, и возвращает
+// его id и id найденной песни для каждой записи (0 — не найдена). Несопоставленные записи пропускаются,
+// позиции идут подряд. Сопоставление и вставка выполняются в одной транзакции.
+func (r *PlaylistPostgres) ImportPlaylist(ctx context.Context, name string, items []models.PlaylistItem) (int, []int, error) {
+	groups := make([]string, len(items))
+	songs := make([]string, len(items))
+	links := make([]string, len(items))
+	for i, item := range items {
+		groups[i], songs[i], links[i] = item.Group, item.Song, item.Location
+	}
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return 0, nil, queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, playlistMatchQuery, pq.Array(groups), pq.Array(songs), pq.Array(links))
+	if err != nil {
+		logrus.Errorf("Failed to match playlist songs: %v", err)
+		return 0, nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	songIDs := make([]int, 0, len(items))
+	matched := make([]int, 0, len(items))
+	for rows.Next() {
+		var songID int
+		if err := rows.Scan(&songID); err != nil {
+			logrus.Errorf("Failed to scan matched song: %v", err)
+			return 0, nil, queryError(ctx, err)
+		}
+		songIDs = append(songIDs, songID)
+		if songID != 0 {
+			matched = append(matched, songID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating matched songs: %v", err)
+		return 0, nil, queryError(ctx, err)
+	}
+
+	var id int
+	if err := tx.QueryRowContext(ctx, `INSERT INTO playlists (name) VALUES ($1) RETURNING id`, name).Scan(&id); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name": name,
+		}).Errorf("Failed to add playlist: %v", err)
+		return 0, nil, queryError(ctx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO playlist_entries (playlist_id, song_id, position)
+        SELECT $1, t.song_id, t.ord
+        FROM unnest($2::int[]) WITH ORDINALITY AS t(song_id, ord)
+    `, id, pq.Array(matched))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Errorf("Failed to add imported playlist entries: %v", err)
+		return 0, nil, queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"playlist_id": id,
+		}).Errorf("Failed to commit playlist import: %v", err)
+		return 0, nil, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"playlist_id": id,
+		"entries":     len(items),
+		"matched":     len(matched),
+	}).Info("Playlist imported")
+	return id, songIDs, nil
+}
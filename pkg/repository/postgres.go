@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
 )
 
 type Config struct {
@@ -60,7 +64,8 @@ func runMigrations(db *sql.DB) error {
 
 	goose.SetLogger(log.New(log.Writer(), "", log.LstdFlags))
 
-	if err := goose.Up(db, migrationsPath); err != nil {
+	// Миграция 20250205120000 добавлена позже следующих за ней: в базах, где они уже применены, она выполнится вне очереди
+	if err := goose.Up(db, migrationsPath, goose.WithAllowMissing()); err != nil {
 		logrus.Errorf("Failed to run migrations: %v", err)
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
@@ -68,3 +73,31 @@ func runMigrations(db *sql.DB) error {
 	logrus.Info("Database migrations applied successfully")
 	return nil
 }
+
+// withTimeout ограничивает время выполнения запроса к БД
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// queryError приводит ошибку драйвера к доменной: нарушение уникальности — ErrConflict,
+// некорректные данные — ErrValidation, а истёкший или отменённый контекст добавляется
+// как причина, чтобы вызывающий код мог проверить её через errors.Is
+func queryError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %v", ctxErr, err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505":
+			return fmt.Errorf("%s: %w", pqErr.Message, apperror.ErrConflict)
+		case pqErr.Code == "23503", pqErr.Code == "23502", pqErr.Code == "23514", pqErr.Code.Class() == "22":
+			return fmt.Errorf("%s: %w", pqErr.Message, apperror.ErrValidation)
+		}
+	}
+	return err
+}
@@ -1,24 +1,126 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
 )
 
 type Song interface {
-	AddSong(list models.Song) error
-	GetSongs(filter string, page int, limit int) ([]models.Song, error)
-	GetSongText(id int) (string, error)
-	UpdateSong(id int, song models.Song) error
-	DeleteSong(id int) error
+	AddSong(ctx context.Context, list models.Song) (int, error)
+	ImportSongs(ctx context.Context, songs []models.Song) ([]int, error)
+	GetSong(ctx context.Context, id int) (models.Song, error)
+	GetSongRelations(ctx context.Context, songID int, include []string) (models.SongRelations, error)
+	GetSongs(ctx context.Context, filter models.SongFilter) ([]models.Song, int, error)
+	GetSongsByCursor(ctx context.Context, filter models.SongFilter, cursor string) ([]models.Song, string, error)
+	ExportSongs(ctx context.Context, filter models.SongFilter, fn func(models.Song) error) (int, error)
+	GetSongText(ctx context.Context, id int) (string, error)
+	GetLyricSections(ctx context.Context, id int) ([]lyrics.Section, error)
+	SetSyncedLyrics(ctx context.Context, songID int, synced lyrics.SyncedLyrics) error
+	GetSyncedLyrics(ctx context.Context, songID int) (lyrics.SyncedLyrics, error)
+	DeleteSyncedLyrics(ctx context.Context, songID int) error
+	SetTranslation(ctx context.Context, songID int, translation models.Translation) error
+	GetTranslations(ctx context.Context, songID int) ([]models.Translation, error)
+	GetTranslation(ctx context.Context, songID int, language string) (models.Translation, error)
+	DeleteTranslation(ctx context.Context, songID int, language string) error
+	SearchSongs(ctx context.Context, search models.SongSearch) ([]models.SongSearchResult, error)
+	GetSongFacets(ctx context.Context, filter models.SongFilter) (models.SongFacets, error)
+	UpdateSong(ctx context.Context, id int, changes models.SongChanges, precondition models.Precondition) (int, error)
+	DeleteSong(ctx context.Context, id int, precondition models.Precondition) error
+	RestoreSong(ctx context.Context, id int) error
+	PurgeSong(ctx context.Context, id int, precondition models.Precondition) error
+	PurgeDeletedSongs(ctx context.Context, before time.Time, limit int) (int, error)
+	GetRevisions(ctx context.Context, filter models.RevisionFilter) ([]models.SongRevision, int, error)
+	GetRevision(ctx context.Context, songID, revision int) (models.SongRevision, error)
+	RestoreRevision(ctx context.Context, songID, revision int) error
+	SetSongDetails(ctx context.Context, id int, details models.Song) error
+	SetEnrichmentStatus(ctx context.Context, id int, status string, attempts int, lastErr string) error
+	GetPendingEnrichments(ctx context.Context, limit int) ([]models.EnrichmentJob, error)
+}
+
+type Artist interface {
+	AddArtist(ctx context.Context, name string) (int, error)
+	GetArtists(ctx context.Context, filter models.ArtistFilter) ([]models.Artist, int, error)
+	GetArtist(ctx context.Context, id int) (models.Artist, error)
+	UpdateArtist(ctx context.Context, id int, name string) error
+	DeleteArtist(ctx context.Context, id int) error
+}
+
+type Album interface {
+	AddAlbum(ctx context.Context, album models.Album) (int, error)
+	GetAlbums(ctx context.Context, filter models.AlbumFilter) ([]models.Album, int, error)
+	GetAlbum(ctx context.Context, id int) (models.Album, error)
+	UpdateAlbum(ctx context.Context, id int, album models.Album) error
+	SetAlbumTracks(ctx context.Context, id int, tracks []models.TrackPosition) error
+	DeleteAlbum(ctx context.Context, id int) error
+}
+
+type Genre interface {
+	AddGenre(ctx context.Context, genre models.Genre) (int, error)
+	GetGenres(ctx context.Context) ([]models.Genre, error)
+	DeleteGenre(ctx context.Context, id int) error
+	AttachGenre(ctx context.Context, songID, genreID int) error
+	DetachGenre(ctx context.Context, songID, genreID int) error
+}
+
+type Tag interface {
+	GetTags(ctx context.Context) ([]models.Tag, error)
+	AttachTag(ctx context.Context, songID int, name string) error
+	DetachTag(ctx context.Context, songID int, name string) error
+}
+
+type Playlist interface {
+	AddPlaylist(ctx context.Context, name string) (int, error)
+	GetPlaylists(ctx context.Context, filter models.PlaylistFilter) ([]models.Playlist, int, error)
+	GetPlaylist(ctx context.Context, id int) (models.Playlist, error)
+	RenamePlaylist(ctx context.Context, id int, name string) error
+	DeletePlaylist(ctx context.Context, id int) error
+	AddPlaylistEntry(ctx context.Context, playlistID int, entry models.NewPlaylistEntry) (int, int, error)
+	MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) error
+	RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error
+	ImportPlaylist(ctx context.Context, name string, items []models.PlaylistItem) (int, []int, error)
+}
+
+type User interface {
+	AddUser(ctx context.Context, user models.User) (int, error)
+	GetUsers(ctx context.Context) ([]models.User, error)
+	GetUser(ctx context.Context, id int) (models.User, error)
+	GetUserByName(ctx context.Context, username string) (models.User, error)
+	SetUserRole(ctx context.Context, id int, role string) error
+}
+
+type APIKey interface {
+	AddAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	GetActiveAPIKey(ctx context.Context, keyHash string) (models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	SetAPIKeyLimits(ctx context.Context, id, rateLimit, burst int) error
 }
 
 type Repository struct {
 	Song
+	Artist
+	Album
+	Genre
+	Tag
+	Playlist
+	User
+	APIKey
 }
 
-func NewRepository(db *sqlx.DB) *Repository {
+// NewRepository создаёт репозитории; queryTimeout ограничивает время каждого запроса к БД
+func NewRepository(db *sqlx.DB, queryTimeout time.Duration) *Repository {
 	return &Repository{
-		Song: NewSongPostgres(db),
+		Song:     NewSongPostgres(db, queryTimeout),
+		Artist:   NewArtistPostgres(db, queryTimeout),
+		Album:    NewAlbumPostgres(db, queryTimeout),
+		Genre:    NewGenrePostgres(db, queryTimeout),
+		Tag:      NewTagPostgres(db, queryTimeout),
+		Playlist: NewPlaylistPostgres(db, queryTimeout),
+		User:     NewUserPostgres(db, queryTimeout),
+		APIKey:   NewAPIKeyPostgres(db, queryTimeout),
 	}
 }
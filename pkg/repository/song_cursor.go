@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// songCursor — позиция в выборке: значения ключей сортировки последней отданной песни.
+// Sort хранит порядок, для которого курсор был выдан, чтобы его нельзя было применить к другому.
+type songCursor struct {
+	Sort   string    `json:"s"`
+	Values []*string `json:"v"`
+}
+
+// songSortValues достают из песни значения ключей сортировки в виде, пригодном для сравнения в SQL
+var songSortValues = map[string]func(song models.Song) *string{
+	"id":    func(song models.Song) *string { return stringPtr(strconv.Itoa(song.ID)) },
+	"group": func(song models.Song) *string { return stringPtr(song.GroupName) },
+	"song":  func(song models.Song) *string { return stringPtr(song.SongName) },
+	"releaseDate": func(song models.Song) *string {
+		if song.ReleaseDate.IsZero() {
+			return nil
+		}
+		return stringPtr(song.ReleaseDate.Time.Format(time.DateOnly))
+	},
+	"deletedAt": func(song models.Song) *string {
+		if song.DeletedAt == nil {
+			return nil
+		}
+		return stringPtr(song.DeletedAt.Format(time.RFC3339Nano))
+	},
+}
+
+// GetSongsByCursor — keyset-пагинация: вместо OFFSET выбираются строки, идущие после курсора
+// в порядке сортировки. Порядок не «съезжает» при вставках, а глубокие страницы не замедляются.
+func (s *SongPostgres) GetSongsByCursor(ctx context.Context, filter models.SongFilter, cursor string) ([]models.Song, string, error) {
+	ordering, err := songOrdering(filter.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	signature := orderingSignature(ordering)
+
+	conditions, values := buildSongFilter(filter)
+	if cursor != "" {
+		position, err := decodeSongCursor(cursor, signature, len(ordering))
+		if err != nil {
+			return nil, "", err
+		}
+		condition, keysetValues := buildKeyset(ordering, position.Values, len(values)+1)
+		conditions = append(conditions, condition)
+		values = append(values, keysetValues...)
+	}
+
+	orderBy, err := buildSongOrder(filter.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	// Берём на одну строку больше, чтобы понять, есть ли следующая страница
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM songs
+        %s
+        ORDER BY %s
+        LIMIT $%d
+    `, songColumnsFor(filter.Fields), whereClause(conditions), orderBy, len(values)+1)
+
+	logrus.WithFields(logrus.Fields{
+		"order":  orderBy,
+		"cursor": cursor != "",
+		"limit":  filter.Limit,
+	}).Debug("Executing keyset query to fetch songs")
+
+	rows, err := s.db.QueryContext(ctx, query, append(values, filter.Limit+1)...)
+	if err != nil {
+		logrus.Errorf("Failed to execute keyset query: %v", err)
+		return nil, "", queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	songs := make([]models.Song, 0, filter.Limit+1)
+	for rows.Next() {
+		var song models.Song
+		if err := scanSong(rows, &song); err != nil {
+			logrus.Errorf("Failed to scan song: %v", err)
+			return nil, "", queryError(ctx, err)
+		}
+		songs = append(songs, song)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating rows: %v", err)
+		return nil, "", queryError(ctx, err)
+	}
+
+	nextCursor := ""
+	if len(songs) > filter.Limit {
+		songs = songs[:filter.Limit]
+		nextCursor = encodeSongCursor(ordering, signature, songs[len(songs)-1])
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"retrieved_songs": len(songs),
+		"has_next":        nextCursor != "",
+	}).Debug("Successfully retrieved songs by cursor")
+
+	return songs, nextCursor, nil
+}
+
+// buildKeyset строит условие «строка идёт после курсора» для составного ключа сортировки:
+// (a > $1) OR (a = $1 AND b > $2) OR ... с учётом направления и NULLS LAST
+func buildKeyset(ordering []songOrderColumn, position []*string, valueIndex int) (string, []interface{}) {
+	alternatives := make([]string, 0, len(ordering))
+	values := make([]interface{}, 0, len(ordering))
+	equals := make([]string, 0, len(ordering))
+
+	for i, col := range ordering {
+		value := position[i]
+
+		var after, equal string
+		if value == nil {
+			// NULL всегда в конце: после NULL идут только такие же NULL
+			after = ""
+			equal = fmt.Sprintf("%s IS NULL", col.column)
+		} else {
+			op := ">"
+			if col.desc {
+				op = "<"
+			}
+			after = fmt.Sprintf("(%s %s $%d OR %s IS NULL)", col.column, op, valueIndex, col.column)
+			equal = fmt.Sprintf("%s = $%d", col.column, valueIndex)
+			values = append(values, *value)
+			valueIndex++
+		}
+
+		if after != "" {
+			alternatives = append(alternatives, "("+strings.Join(append(append([]string{}, equals...), after), " AND ")+")")
+		}
+		equals = append(equals, equal)
+	}
+
+	if len(alternatives) == 0 {
+		return "FALSE", values
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", values
+}
+
+func orderingSignature(ordering []songOrderColumn) string {
+	fields := make([]string, 0, len(ordering))
+	for _, col := range ordering {
+		if col.desc {
+			fields = append(fields, "-"+col.field)
+		} else {
+			fields = append(fields, col.field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+func encodeSongCursor(ordering []songOrderColumn, signature string, last models.Song) string {
+	cursor := songCursor{Sort: signature, Values: make([]*string, 0, len(ordering))}
+	for _, col := range ordering {
+		cursor.Values = append(cursor.Values, songSortValues[col.field](last))
+	}
+
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSongCursor(encoded, signature string, keys int) (songCursor, error) {
+	var cursor songCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(raw, &cursor) != nil || len(cursor.Values) != keys {
+		return songCursor{}, apperror.NewValidationError("cursor", "malformed cursor")
+	}
+	if cursor.Sort != signature {
+		return songCursor{}, apperror.NewValidationError("cursor", "cursor was issued for a different sort order")
+	}
+	return cursor, nil
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// exportFetchSize — сколько песен читается из курсора выгрузки за один FETCH
+const exportFetchSize = 500
+
+// ExportSongs передаёт в fn все песни выборки filter (без пагинации) в порядке filter.Sort и возвращает их количество.
+// Строки читаются из серверного курсора порциями по exportFetchSize, поэтому память не зависит от размера
+// каталога. Транзакция REPEATABLE READ даёт согласованный снимок на всё время выгрузки, а ограничение
+// времени действует на каждый FETCH отдельно: медленный клиент не прерывает выгрузку по таймауту.
+func (s *SongPostgres) ExportSongs(ctx context.Context, filter models.SongFilter, fn func(models.Song) error) (int, error) {
+	conditions, values := buildSongFilter(filter)
+	orderBy, err := buildSongOrder(filter.Sort)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return 0, queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+        DECLARE song_export NO SCROLL CURSOR FOR
+        SELECT %s
+        FROM songs
+        %s
+        ORDER BY %s
+    `, songColumnsFor(filter.Fields), whereClause(conditions), orderBy)
+	declareCtx, cancel := withTimeout(ctx, s.queryTimeout)
+	_, err = tx.ExecContext(declareCtx, query, values...)
+	if err != nil {
+		err = queryError(declareCtx, err)
+	}
+	cancel()
+	if err != nil {
+		logrus.Errorf("Failed to declare export cursor: %v", err)
+		return 0, err
+	}
+
+	count := 0
+	for {
+		songs, err := s.fetchExport(ctx, tx)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"exported": count,
+			}).Errorf("Failed to fetch songs for export: %v", err)
+			return count, err
+		}
+
+		// Строки уже прочитаны: запись клиенту не держит открытый запрос и не входит в его таймаут
+		for _, song := range songs {
+			if err := fn(song); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(songs) < exportFetchSize {
+			break
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.Errorf("Failed to finish export: %v", err)
+		return count, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"songs": count,
+	}).Info("Songs exported")
+	return count, nil
+}
+
+// fetchExport читает из курсора выгрузки следующую порцию песен
+func (s *SongPostgres) fetchExport(ctx context.Context, tx *sqlx.Tx) ([]models.Song, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("FETCH %d FROM song_export", exportFetchSize))
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	songs := make([]models.Song, 0, exportFetchSize)
+	for rows.Next() {
+		var song models.Song
+		if err := scanSong(rows, &song); err != nil {
+			return nil, queryError(ctx, err)
+		}
+		songs = append(songs, song)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return songs, nil
+}
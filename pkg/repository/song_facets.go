@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// facetTagsLimit — сколько самых популярных тегов возвращать в фасете
+const facetTagsLimit = 50
+
+// GetSongFacets считает песни, подходящие под фильтр, в разрезе жанров, тегов и десятилетий выпуска.
+// Количество по жанру включает песни всех его поджанров.
+func (s *SongPostgres) GetSongFacets(ctx context.Context, filter models.SongFilter) (models.SongFacets, error) {
+	conditions, values := buildSongFilter(filter)
+	where := whereClause(conditions)
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	facets := models.SongFacets{
+		Genres:  make([]models.GenreFacet, 0),
+		Tags:    make([]models.FacetCount, 0),
+		Decades: make([]models.FacetCount, 0),
+	}
+
+	genresQuery := fmt.Sprintf(`
+        WITH RECURSIVE filtered AS (
+            SELECT id FROM songs %s
+        ), tree AS (
+            SELECT id AS ancestor_id, id AS genre_id FROM genres
+            UNION ALL
+            SELECT tree.ancestor_id, g.id FROM tree JOIN genres g ON g.parent_id = tree.genre_id
+        )
+        SELECT g.id, g.name, g.parent_id, COUNT(DISTINCT sg.song_id)
+        FROM tree
+        JOIN song_genres sg ON sg.genre_id = tree.genre_id
+        JOIN filtered f ON f.id = sg.song_id
+        JOIN genres g ON g.id = tree.ancestor_id
+        GROUP BY g.id, g.name, g.parent_id
+        ORDER BY 4 DESC, g.name
+    `, where)
+
+	rows, err := s.db.QueryContext(ctx, genresQuery, values...)
+	if err != nil {
+		logrus.Errorf("Failed to count genre facets: %v", err)
+		return models.SongFacets{}, queryError(ctx, err)
+	}
+	for rows.Next() {
+		var facet models.GenreFacet
+		if err := rows.Scan(&facet.ID, &facet.Name, &facet.ParentID, &facet.Count); err != nil {
+			rows.Close()
+			return models.SongFacets{}, queryError(ctx, err)
+		}
+		facets.Genres = append(facets.Genres, facet)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.SongFacets{}, queryError(ctx, err)
+	}
+
+	tagsQuery := fmt.Sprintf(`
+        SELECT t.name, COUNT(*)
+        FROM song_tags st
+        JOIN tags t ON t.id = st.tag_id
+        WHERE st.song_id IN (SELECT id FROM songs %s)
+        GROUP BY t.name
+        ORDER BY 2 DESC, t.name
+        LIMIT %d
+    `, where, facetTagsLimit)
+
+	if facets.Tags, err = s.facetCounts(ctx, tagsQuery, values); err != nil {
+		logrus.Errorf("Failed to count tag facets: %v", err)
+		return models.SongFacets{}, err
+	}
+
+	decadeConditions := append(append([]string{}, conditions...), "release_date IS NOT NULL")
+	decadesQuery := fmt.Sprintf(`
+        SELECT (EXTRACT(YEAR FROM release_date)::int / 10 * 10) || 's', COUNT(*)
+        FROM songs
+        %s
+        GROUP BY 1
+        ORDER BY 1
+    `, whereClause(decadeConditions))
+
+	if facets.Decades, err = s.facetCounts(ctx, decadesQuery, values); err != nil {
+		logrus.Errorf("Failed to count decade facets: %v", err)
+		return models.SongFacets{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"genres":  len(facets.Genres),
+		"tags":    len(facets.Tags),
+		"decades": len(facets.Decades),
+	}).Debug("Song facets counted")
+
+	return facets, nil
+}
+
+func (s *SongPostgres) facetCounts(ctx context.Context, query string, values []interface{}) ([]models.FacetCount, error) {
+	rows, err := s.db.QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	counts := make([]models.FacetCount, 0)
+	for rows.Next() {
+		var facet models.FacetCount
+		if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+			return nil, queryError(ctx, err)
+		}
+		counts = append(counts, facet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/audit"
+)
+
+// songImportColumns — колонки многострочного INSERT при импорте
+const songImportColumns = 11
+
+// ImportSongs сохраняет пачку песен в одной транзакции и возвращает их ID в том же порядке.
+// ID выделяются из последовательности заранее, чтобы сопоставить строки с песнями без отдельных запросов,
+// затем песни и ревизии create вставляются многострочными INSERT.
+func (s *SongPostgres) ImportSongs(ctx context.Context, songs []models.Song) ([]int, error) {
+	if len(songs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int, 0, len(songs))
+	err = tx.SelectContext(ctx, &ids, `SELECT nextval(pg_get_serial_sequence('songs', 'id')) FROM generate_series(1, $1)`, len(songs))
+	if err != nil {
+		logrus.Errorf("Failed to allocate song IDs: %v", err)
+		return nil, queryError(ctx, err)
+	}
+
+	// Исполнители одной пачки обычно повторяются, поэтому каждый находится или создаётся один раз
+	type artist struct {
+		id   int
+		name string
+	}
+	artists := make(map[string]artist)
+
+	rows := make([]string, 0, len(songs))
+	values := make([]interface{}, 0, len(songs)*songImportColumns)
+	for i := range songs {
+		song := &songs[i]
+		resolved, ok := artists[song.GroupName]
+		if !ok {
+			resolved.id, resolved.name, err = upsertArtist(ctx, tx, song.GroupName)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"group_name": song.GroupName,
+				}).Errorf("Failed to resolve artist: %v", err)
+				return nil, queryError(ctx, err)
+			}
+			artists[song.GroupName] = resolved
+		}
+		song.ID = ids[i]
+		song.ArtistID = resolved.id
+		song.GroupName = resolved.name
+		if song.EnrichmentStatus == "" {
+			song.EnrichmentStatus = models.EnrichmentDone
+		}
+
+		releaseDate, precision := releaseDateArgs(song.ReleaseDate)
+		rows = append(rows, valuesPlaceholders(len(values), songImportColumns))
+		values = append(values, song.ID, song.ArtistID, song.GroupName, song.SongName, releaseDate, precision,
+			song.Text, song.Lyrics, song.Link, durationArg(song.Duration), song.EnrichmentStatus)
+	}
+
+	query := fmt.Sprintf(`
+        INSERT INTO songs (id, artist_id, group_name, song, release_date, release_date_precision, text, lyrics, link, duration, enrichment_status)
+        VALUES %s
+    `, strings.Join(rows, ", "))
+	if _, err := tx.ExecContext(ctx, query, values...); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"songs": len(songs),
+		}).Errorf("Failed to import songs: %v", err)
+		return nil, queryError(ctx, err)
+	}
+
+	for _, song := range songs {
+		if song.Text == "" {
+			continue
+		}
+		if err := saveLyricSections(ctx, tx, song.ID, song.Text); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"song_id": song.ID,
+			}).Errorf("Failed to save lyric sections: %v", err)
+			return nil, queryError(ctx, err)
+		}
+	}
+
+	if err := recordImportRevisions(ctx, tx, songs); err != nil {
+		logrus.Errorf("Failed to record revisions: %v", err)
+		return nil, queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.Errorf("Failed to commit imported songs: %v", err)
+		return nil, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"songs":   len(songs),
+		"artists": len(artists),
+		"first":   ids[0],
+	}).Info("Songs imported")
+	return ids, nil
+}
+
+// recordImportRevisions записывает ревизии create для новых песен одним запросом:
+// у только что созданных ID ещё нет истории, поэтому номер ревизии всегда 1
+func recordImportRevisions(ctx context.Context, tx *sqlx.Tx, songs []models.Song) error {
+	rows := make([]string, 0, len(songs))
+	values := []interface{}{audit.Actor(ctx), models.RevisionCreate}
+	for _, song := range songs {
+		snapshot := models.NewSongSnapshot(song)
+		changesJSON, err := json.Marshal(models.DiffSnapshots(nil, &snapshot))
+		if err != nil {
+			return err
+		}
+		snapshotJSON, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+
+		n := len(values)
+		rows = append(rows, fmt.Sprintf("($%d, 1, $2, $1, $%d, $%d)", n+1, n+2, n+3))
+		values = append(values, song.ID, changesJSON, snapshotJSON)
+	}
+
+	query := fmt.Sprintf(`
+        INSERT INTO song_revisions (song_id, revision, action, actor, changes, snapshot)
+        VALUES %s
+    `, strings.Join(rows, ", "))
+	_, err := tx.ExecContext(ctx, query, values...)
+	return err
+}
+
+// valuesPlaceholders — «($n+1, …, $n+count)» для строки многострочного INSERT
+func valuesPlaceholders(offset, count int) string {
+	placeholders := make([]string, count)
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", offset+i+1)
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")"
+}
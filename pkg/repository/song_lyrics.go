@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+)
+
+// saveLyricSections пересобирает секции текста песни из text; вызывается в той же транзакции,
+// что и изменение songs.text, чтобы секции не расходились с текстом
+func saveLyricSections(ctx context.Context, tx *sqlx.Tx, songID int, text string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM lyric_sections WHERE song_id = $1`, songID); err != nil {
+		return err
+	}
+
+	sections := lyrics.Parse(text)
+	for i, section := range sections {
+		var sectionID int
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO lyric_sections (song_id, position, type, label) VALUES ($1, $2, $3, $4) RETURNING id`,
+			songID, i+1, section.Type, section.Label).Scan(&sectionID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO lyric_lines (section_id, position, text)
+            SELECT $1, l.n, l.line FROM unnest($2::text[]) WITH ORDINALITY AS l(line, n)
+        `, sectionID, pq.Array(section.Lines))
+		if err != nil {
+			return err
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  songID,
+		"sections": len(sections),
+	}).Debug("Lyric sections saved")
+	return nil
+}
+
+// GetLyricSections возвращает секции текста песни по порядку. Пустой результат означает,
+// что секции ещё не построены (запись создана до их появления) или текста нет.
+func (s *SongPostgres) GetLyricSections(ctx context.Context, id int) ([]lyrics.Section, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	exists, err := songExists(ctx, s.db, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to check if song exists: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	query := `
+        SELECT ls.type, ls.label, ARRAY(
+            SELECT ll.text FROM lyric_lines ll WHERE ll.section_id = ls.id ORDER BY ll.position
+        )
+        FROM lyric_sections ls
+        WHERE ls.song_id = $1
+        ORDER BY ls.position
+    `
+
+	rows, err := s.db.QueryContext(ctx, query, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to get lyric sections: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	sections := make([]lyrics.Section, 0)
+	for rows.Next() {
+		var section lyrics.Section
+		if err := rows.Scan(&section.Type, &section.Label, pq.Array(&section.Lines)); err != nil {
+			logrus.Errorf("Failed to scan lyric section: %v", err)
+			return nil, queryError(ctx, err)
+		}
+		sections = append(sections, section)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return sections, nil
+}
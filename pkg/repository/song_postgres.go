@@ -1,126 +1,461 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
 )
 
 type SongPostgres struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	queryTimeout time.Duration
 }
 
-func NewSongPostgres(db *sqlx.DB) *SongPostgres {
-	return &SongPostgres{db: db}
+func NewSongPostgres(db *sqlx.DB, queryTimeout time.Duration) *SongPostgres {
+	return &SongPostgres{db: db, queryTimeout: queryTimeout}
 }
 
-func (r *SongPostgres) AddSong(song models.Song) error {
-	query := `INSERT INTO songs (group_name, song, release_date, text, lyrics, link) VALUES ($1, $2, $3, $4, $5, $6)`
+// songColumns — колонки песни в порядке, который ожидает scanSong
+const songColumns = `id, artist_id, group_name, song, release_date, release_date_precision,
+    COALESCE(text, ''), COALESCE(lyrics, ''), COALESCE(link, ''), COALESCE(duration, 0), enrichment_status, deleted_at, version`
 
-	_, err := r.db.Exec(query, song.GroupName, song.SongName, song.ReleaseDate, song.Text, song.Lyrics, song.Link)
+// songHeavyColumns — тяжёлые колонки, которые не читаются, если поле не выбрано параметром fields
+var songHeavyColumns = map[string]string{
+	"text":   "COALESCE(text, '')",
+	"lyrics": "COALESCE(lyrics, '')",
+}
+
+// songColumnsFor — songColumns для выбранных полей: не выбранные тяжёлые колонки заменяются
+// пустой строкой, чтобы не читать их из БД и сохранить порядок колонок для scanSong
+func songColumnsFor(fields []string) string {
+	if len(fields) == 0 {
+		return songColumns
+	}
+	columns := songColumns
+	for field, column := range songHeavyColumns {
+		if !slices.Contains(fields, field) {
+			columns = strings.Replace(columns, column, "''", 1)
+		}
+	}
+	return columns
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSong читает строку, выбранную по songColumns; extra — дополнительные колонки после них
+func scanSong(row rowScanner, song *models.Song, extra ...interface{}) error {
+	var releaseDate sql.NullTime
+	var precision sql.NullString
+
+	dest := []interface{}{&song.ID, &song.ArtistID, &song.GroupName, &song.SongName, &releaseDate, &precision,
+		&song.Text, &song.Lyrics, &song.Link, &song.Duration, &song.EnrichmentStatus, &song.DeletedAt, &song.Version}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	song.ReleaseDate = models.ReleaseDate{}
+	if releaseDate.Valid {
+		song.ReleaseDate = models.NewReleaseDate(releaseDate.Time, models.DatePrecision(precision.String))
+	}
+	return nil
+}
+
+// releaseDateArgs превращает дату релиза в параметры для колонок release_date и release_date_precision
+func releaseDateArgs(date models.ReleaseDate) (interface{}, interface{}) {
+	if date.IsZero() {
+		return nil, nil
+	}
+	return date.Time, string(date.Precision)
+}
+
+// durationArg — значение колонки duration: неизвестная длительность хранится как NULL
+func durationArg(seconds int) interface{} {
+	if seconds == 0 {
+		return nil
+	}
+	return seconds
+}
+
+// AddSong сохраняет песню, привязывая её к исполнителю: если исполнитель с таким же
+// нормализованным названием уже есть, используется он и его каноническое название
+func (r *SongPostgres) AddSong(ctx context.Context, song models.Song) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	status := song.EnrichmentStatus
+	if status == "" {
+		status = models.EnrichmentDone
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return 0, queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	artistID, groupName, err := upsertArtist(ctx, tx, song.GroupName)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"group_name": song.GroupName,
+		}).Errorf("Failed to resolve artist: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	query := `INSERT INTO songs (artist_id, group_name, song, release_date, release_date_precision, text, lyrics, link, duration, enrichment_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	releaseDate, precision := releaseDateArgs(song.ReleaseDate)
+
+	var id int
+	err = tx.QueryRowContext(ctx, query, artistID, groupName, song.SongName, releaseDate, precision, song.Text, song.Lyrics, song.Link, durationArg(song.Duration), status).Scan(&id)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"group_name":   song.GroupName,
 			"song":         song.SongName,
-			"release_date": song.ReleaseDate,
+			"release_date": song.ReleaseDate.String(),
 		}).Errorf("Failed to add song: %v", err)
-		return err
+		return 0, queryError(ctx, err)
+	}
+
+	if err := saveLyricSections(ctx, tx, id, song.Text); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to save lyric sections: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	song.GroupName = groupName
+	snapshot := models.NewSongSnapshot(song)
+	if err := recordRevision(ctx, tx, id, models.RevisionCreate, nil, &snapshot, nil); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to record revision: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.Errorf("Failed to commit song: %v", err)
+		return 0, queryError(ctx, err)
 	}
 
 	logrus.WithFields(logrus.Fields{
-		"group_name":   song.GroupName,
-		"song":         song.SongName,
-		"release_date": song.ReleaseDate,
+		"song_id":           id,
+		"artist_id":         artistID,
+		"group_name":        groupName,
+		"song":              song.SongName,
+		"release_date":      song.ReleaseDate.String(),
+		"enrichment_status": status,
 	}).Debug("Song added successfully")
-	return nil
+	return id, nil
 }
 
-func (s *SongPostgres) GetSongs(filter string, page int, limit int) ([]models.Song, error) {
-	offset := (page - 1) * limit
+// songSortColumns — белый список полей сортировки и соответствующих им колонок
+var songSortColumns = map[string]string{
+	"id":          "id",
+	"group":       "group_name",
+	"song":        "song",
+	"releaseDate": "release_date",
+	"deletedAt":   "deleted_at",
+}
 
-	query := `
-        SELECT id, group_name, song, release_date, text, lyrics, link 
-        FROM songs 
-        WHERE ($1 = '' OR group_name ILIKE $1 OR song ILIKE $1 OR lyrics ILIKE $1) 
-        LIMIT $2 OFFSET $3
-    `
+func (s *SongPostgres) GetSongs(ctx context.Context, filter models.SongFilter) ([]models.Song, int, error) {
+	conditions, values := buildSongFilter(filter)
+	where := whereClause(conditions)
+	orderBy, err := buildSongOrder(filter.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	offset := (filter.Page - 1) * filter.Limit
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM songs %s", where)
+	if err := s.db.QueryRowContext(ctx, countQuery, values...).Scan(&total); err != nil {
+		logrus.Errorf("Failed to count songs: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM songs
+        %s
+        ORDER BY %s
+        LIMIT $%d OFFSET $%d
+    `, songColumnsFor(filter.Fields), where, orderBy, len(values)+1, len(values)+2)
 
 	logrus.WithFields(logrus.Fields{
-		"filter": filter,
-		"page":   page,
-		"limit":  limit,
+		"where":  where,
+		"order":  orderBy,
+		"page":   filter.Page,
+		"limit":  filter.Limit,
 		"offset": offset,
 	}).Debug("Executing query to fetch songs")
 
-	rows, err := s.db.Query(query, "%"+filter+"%", limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, append(values, filter.Limit, offset)...)
 	if err != nil {
 		logrus.Errorf("Failed to execute query: %v", err)
-		return nil, err
+		return nil, 0, queryError(ctx, err)
 	}
 	defer rows.Close()
 
-	songs := make([]models.Song, 0, limit)
+	songs := make([]models.Song, 0, filter.Limit)
 	for rows.Next() {
 		var song models.Song
-		if err := rows.Scan(&song.ID, &song.GroupName, &song.SongName, &song.ReleaseDate, &song.Text, &song.Lyrics, &song.Link); err != nil {
+		if err := scanSong(rows, &song); err != nil {
 			logrus.Errorf("Failed to scan song: %v", err)
-			return nil, err
+			return nil, 0, queryError(ctx, err)
 		}
 		songs = append(songs, song)
 	}
 
 	if err := rows.Err(); err != nil {
 		logrus.Errorf("Error after iterating rows: %v", err)
-		return nil, err
+		return nil, 0, queryError(ctx, err)
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"retrieved_songs": len(songs),
-		"filter":          filter,
-		"page":            page,
-		"limit":           limit,
+		"total":           total,
+		"page":            filter.Page,
+		"limit":           filter.Limit,
 	}).Debug("Successfully retrieved songs")
 
-	return songs, nil
+	return songs, total, nil
 }
 
-func (s *SongPostgres) GetSongText(id int) (string, error) {
-	// Проверка наличия записи с указанным id
+// buildSongFilter собирает условия WHERE по заданным полям фильтра, аналогично SET в UpdateSong
+func buildSongFilter(filter models.SongFilter) ([]string, []interface{}) {
+	conditions := make([]string, 0)
+	values := make([]interface{}, 0)
+	valueIndex := 1
+
+	// Песни из корзины видны только в её собственном списке
+	if filter.Trashed {
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if filter.Query != "" {
+		conditions = append(conditions, fmt.Sprintf("(group_name ILIKE $%d OR song ILIKE $%d OR lyrics ILIKE $%d)", valueIndex, valueIndex, valueIndex))
+		values = append(values, containsPattern(filter.Query))
+		valueIndex++
+	}
+	if filter.ArtistID != 0 {
+		conditions = append(conditions, fmt.Sprintf("artist_id = $%d", valueIndex))
+		values = append(values, filter.ArtistID)
+		valueIndex++
+	}
+	if filter.Group != "" {
+		conditions = append(conditions, fmt.Sprintf("group_name ILIKE $%d", valueIndex))
+		values = append(values, containsPattern(filter.Group))
+		valueIndex++
+	}
+	if filter.Song != "" {
+		conditions = append(conditions, fmt.Sprintf("song ILIKE $%d", valueIndex))
+		values = append(values, containsPattern(filter.Song))
+		valueIndex++
+	}
+	if filter.ReleaseDateFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("release_date >= $%d::date", valueIndex))
+		values = append(values, filter.ReleaseDateFrom)
+		valueIndex++
+	}
+	if filter.ReleaseDateTo != "" {
+		conditions = append(conditions, fmt.Sprintf("release_date <= $%d::date", valueIndex))
+		values = append(values, filter.ReleaseDateTo)
+		valueIndex++
+	}
+	if filter.HasText != nil {
+		conditions = append(conditions, fmt.Sprintf("(COALESCE(text, '') <> '') = $%d", valueIndex))
+		values = append(values, *filter.HasText)
+		valueIndex++
+	}
+	if filter.HasLink != nil {
+		conditions = append(conditions, fmt.Sprintf("(COALESCE(link, '') <> '') = $%d", valueIndex))
+		values = append(values, *filter.HasLink)
+		valueIndex++
+	}
+	// Жанр подходит вместе со всеми поджанрами: genre=rock находит и alt-rock
+	for _, names := range matchGroups(filter.Genres, filter.GenreMode) {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM song_genres sg
+            WHERE sg.song_id = songs.id AND sg.genre_id IN (%s)
+        )`, fmt.Sprintf(genreDescendants, valueIndex)))
+		values = append(values, pq.Array(names))
+		valueIndex++
+	}
+	for _, names := range matchGroups(filter.Tags, filter.TagMode) {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM song_tags st JOIN tags t ON t.id = st.tag_id
+            WHERE st.song_id = songs.id AND t.name = ANY($%d)
+        )`, valueIndex))
+		values = append(values, pq.Array(names))
+		valueIndex++
+	}
+
+	return conditions, values
+}
+
+// genreDescendants выбирает id жанров с названиями из массива-параметра и всех их поджанров
+const genreDescendants = `WITH RECURSIVE d AS (
+                SELECT id FROM genres WHERE lower(name) = ANY($%d)
+                UNION
+                SELECT g.id FROM genres g JOIN d ON g.parent_id = d.id
+            ) SELECT id FROM d`
+
+// matchGroups раскладывает значения фильтра на группы условий: в режиме «или» — одна группа
+// со всеми значениями, в режиме «и» — отдельная группа на каждое значение
+func matchGroups(names []string, mode string) [][]string {
+	if len(names) == 0 {
+		return nil
+	}
+	if mode != models.MatchAll {
+		return [][]string{names}
+	}
+	groups := make([][]string, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, []string{name})
+	}
+	return groups
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// songOrderColumn — колонка, участвующая в сортировке выборки
+type songOrderColumn struct {
+	field  string
+	column string
+	desc   bool
+}
+
+// songOrdering проверяет поля сортировки по белому списку и добавляет id в конец,
+// чтобы порядок был детерминированным (после id остальные поля уже ничего не меняют)
+func songOrdering(sort []models.SortField) ([]songOrderColumn, error) {
+	ordering := make([]songOrderColumn, 0, len(sort)+1)
+
+	for _, field := range sort {
+		column, ok := songSortColumns[field.Field]
+		if !ok {
+			return nil, apperror.NewValidationError("sort", fmt.Sprintf("unsupported sort field %q", field.Field))
+		}
+		ordering = append(ordering, songOrderColumn{field: field.Field, column: column, desc: field.Desc})
+		if column == "id" {
+			return ordering, nil
+		}
+	}
+
+	return append(ordering, songOrderColumn{field: "id", column: "id"}), nil
+}
+
+// buildSongOrder собирает ORDER BY по белому списку полей
+func buildSongOrder(sort []models.SortField) (string, error) {
+	ordering, err := songOrdering(sort)
+	if err != nil {
+		return "", err
+	}
+
+	clauses := make([]string, 0, len(ordering))
+	for _, col := range ordering {
+		direction := "ASC"
+		if col.desc {
+			direction = "DESC"
+		}
+		clauses = append(clauses, fmt.Sprintf("%s %s NULLS LAST", col.column, direction))
+	}
+	return strings.Join(clauses, ", "), nil
+}
+
+// containsPattern экранирует спецсимволы LIKE и оборачивает значение в %...%
+func containsPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
+
+// GetSong возвращает песню, если она не в корзине
+func (s *SongPostgres) GetSong(ctx context.Context, id int) (models.Song, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT %s FROM songs WHERE id = $1 AND deleted_at IS NULL`, songColumns)
+
+	var song models.Song
+	if err := scanSong(s.db.QueryRowContext(ctx, query, id), &song); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logrus.WithFields(logrus.Fields{
+				"song_id": id,
+			}).Warn("Song does not exist")
+			return models.Song{}, fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to get song: %v", err)
+		return models.Song{}, queryError(ctx, err)
+	}
+	return song, nil
+}
+
+// songExists проверяет, что песня есть и не находится в корзине
+func songExists(ctx context.Context, q sqlx.QueryerContext, id int) (bool, error) {
 	var exists bool
-	checkQuery := `SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1)`
-	err := s.db.QueryRow(checkQuery, id).Scan(&exists)
+	err := q.QueryRowxContext(ctx, `SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+	return exists, err
+}
+
+func (s *SongPostgres) GetSongText(ctx context.Context, id int) (string, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	// Проверка наличия записи с указанным id
+	exists, err := songExists(ctx, s.db, id)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
 		}).Errorf("Failed to check if song exists: %v", err)
-		return "", err
+		return "", queryError(ctx, err)
 	}
 	if !exists {
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
 		}).Warn("Song does not exist")
-		return "", fmt.Errorf("song with id %d does not exist", id)
+		return "", fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
 	}
 
 	// Запрос текста песни
-	query := `SELECT text FROM songs WHERE id = $1`
+	query := `SELECT COALESCE(text, '') FROM songs WHERE id = $1`
 	var text string
-	err = s.db.QueryRow(query, id).Scan(&text)
+	err = s.db.QueryRowContext(ctx, query, id).Scan(&text)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			logrus.WithFields(logrus.Fields{
 				"song_id": id,
 			}).Warn("No text found for song")
-			return "", fmt.Errorf("no text found for song id %d", id)
+			return "", fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
 		}
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
 		}).Errorf("Failed to get text: %v", err)
-		return "", err
+		return "", queryError(ctx, err)
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -130,52 +465,122 @@ func (s *SongPostgres) GetSongText(id int) (string, error) {
 	return text, nil
 }
 
-func (s *SongPostgres) UpdateSong(id int, song models.Song) error {
-	setClauses := make([]string, 0)
-	values := make([]interface{}, 0)
-	valueIndex := 1
+// songChangeFields — изменяемые поля песни в порядке, в котором из них собирается SET
+var songChangeFields = []string{"group", "song", "releaseDate", "text", "lyrics", "link", "duration"}
 
-	// Проверяем каждое поле song на наличие значения
-	if song.GroupName != "" {
-		setClauses = append(setClauses, fmt.Sprintf("group_name = $%d", valueIndex))
-		values = append(values, song.GroupName)
-		valueIndex++
+// songChangeColumns — колонки строковых полей; group и releaseDate занимают по две колонки
+var songChangeColumns = map[string]string{
+	"song":   "song",
+	"text":   "text",
+	"lyrics": "lyrics",
+	"link":   "link",
+}
+
+// songSetClauses собирает SET для UPDATE из изменений песни; nil очищает колонку.
+// Исполнитель находится или создаётся, поэтому нужна транзакция.
+func songSetClauses(ctx context.Context, tx *sqlx.Tx, changes models.SongChanges) ([]string, []interface{}, error) {
+	for field := range changes {
+		if !slices.Contains(songChangeFields, field) {
+			return nil, nil, apperror.NewValidationError(field, "unknown or read-only field")
+		}
 	}
-	if song.SongName != "" {
-		setClauses = append(setClauses, fmt.Sprintf("song = $%d", valueIndex))
-		values = append(values, song.SongName)
-		valueIndex++
+
+	setClauses := make([]string, 0, len(changes)+1)
+	values := make([]interface{}, 0, len(changes)+2)
+	for _, field := range songChangeFields {
+		value, ok := changes[field]
+		if !ok {
+			continue
+		}
+
+		switch field {
+		case "group":
+			// Смена исполнителя: находим или создаём его и берём каноническое название
+			name, _ := value.(string)
+			if name == "" {
+				return nil, nil, apperror.NewValidationError("group", "must not be blank")
+			}
+			artistID, groupName, err := upsertArtist(ctx, tx, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			setClauses = append(setClauses, fmt.Sprintf("artist_id = $%d, group_name = $%d", len(values)+1, len(values)+2))
+			values = append(values, artistID, groupName)
+		case "releaseDate":
+			var date models.ReleaseDate
+			if value != nil {
+				if date, ok = value.(models.ReleaseDate); !ok {
+					return nil, nil, apperror.NewValidationError(field, "must be a date")
+				}
+			}
+			releaseDate, precision := releaseDateArgs(date)
+			setClauses = append(setClauses, fmt.Sprintf("release_date = $%d, release_date_precision = $%d", len(values)+1, len(values)+2))
+			values = append(values, releaseDate, precision)
+		case "duration":
+			if _, ok := value.(int); value != nil && !ok {
+				return nil, nil, apperror.NewValidationError(field, "must be an integer")
+			}
+			setClauses = append(setClauses, fmt.Sprintf("duration = $%d", len(values)+1))
+			values = append(values, value)
+		default:
+			if _, ok := value.(string); value != nil && !ok {
+				return nil, nil, apperror.NewValidationError(field, "must be a string")
+			}
+			setClauses = append(setClauses, fmt.Sprintf("%s = $%d", songChangeColumns[field], len(values)+1))
+			values = append(values, value)
+		}
 	}
-	if song.ReleaseDate != "" {
-		setClauses = append(setClauses, fmt.Sprintf("release_date = $%d", valueIndex))
-		values = append(values, song.ReleaseDate)
-		valueIndex++
+	return setClauses, values, nil
+}
+
+// UpdateSong применяет изменения полей песни, если её версия удовлетворяет precondition,
+// и возвращает новую версию. SET собирается только из переданных полей.
+func (s *SongPostgres) UpdateSong(ctx context.Context, id int, changes models.SongChanges, precondition models.Precondition) (int, error) {
+	if len(changes) == 0 {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Warn("No fields provided for update")
+		return 0, apperror.NewValidationError("body", "no fields provided for update")
 	}
-	if song.Text != "" {
-		setClauses = append(setClauses, fmt.Sprintf("text = $%d", valueIndex))
-		values = append(values, song.Text)
-		valueIndex++
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return 0, queryError(ctx, err)
 	}
-	if song.Lyrics != "" {
-		setClauses = append(setClauses, fmt.Sprintf("lyrics = $%d", valueIndex))
-		values = append(values, song.Lyrics)
-		valueIndex++
+	defer tx.Rollback()
+
+	before, err := lockSongSnapshot(ctx, tx, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to load song: %v", err)
+		return 0, queryError(ctx, err)
 	}
-	if song.Link != "" {
-		setClauses = append(setClauses, fmt.Sprintf("link = $%d", valueIndex))
-		values = append(values, song.Link)
-		valueIndex++
+	if before == nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Warn("No song found with the given ID")
+		return 0, fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
+	}
+	if err := checkPrecondition(id, before.Version, precondition); err != nil {
+		return 0, err
 	}
 
-	if len(setClauses) == 0 {
+	setClauses, values, err := songSetClauses(ctx, tx, changes)
+	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
-		}).Warn("No fields provided for update")
-		return nil
+		}).Errorf("Failed to build update: %v", err)
+		return 0, queryError(ctx, err)
 	}
 
 	// Собираем SQL-запрос динамически
-	query := fmt.Sprintf("UPDATE songs SET %s WHERE id = $%d", strings.Join(setClauses, ", "), valueIndex)
+	setClauses = append(setClauses, "version = version + 1")
+	query := fmt.Sprintf("UPDATE songs SET %s WHERE id = $%d", strings.Join(setClauses, ", "), len(values)+1)
 	values = append(values, id)
 
 	logrus.WithFields(logrus.Fields{
@@ -183,54 +588,229 @@ func (s *SongPostgres) UpdateSong(id int, song models.Song) error {
 		"fields":  setClauses,
 	}).Debug("Executing update query")
 
-	_, err := s.db.Exec(query, values...)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, query, values...); err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
 		}).Errorf("Failed to update song: %v", err)
-		return err
+		return 0, queryError(ctx, err)
+	}
+
+	// Очищенный текст удаляет и разбивку на секции
+	if value, ok := changes["text"]; ok {
+		text, _ := value.(string)
+		if err := saveLyricSections(ctx, tx, id, text); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"song_id": id,
+			}).Errorf("Failed to save lyric sections: %v", err)
+			return 0, queryError(ctx, err)
+		}
+	}
+
+	after, err := recordSongChange(ctx, tx, id, before)
+	if err != nil {
+		return 0, queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to commit song update: %v", err)
+		return 0, queryError(ctx, err)
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"song_id":        id,
 		"updated_fields": setClauses,
+		"version":        after.Version,
 	}).Info("Song updated successfully")
-	return nil
+	return after.Version, nil
 }
 
-func (s *SongPostgres) DeleteSong(id int) error {
-	query := `DELETE FROM songs WHERE id = $1`
+// DeleteSong переносит песню в корзину: она скрывается из выборок, но остаётся в БД
+// до восстановления или окончательной очистки
+func (s *SongPostgres) DeleteSong(ctx context.Context, id int, precondition models.Precondition) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
 
 	logrus.WithFields(logrus.Fields{
 		"song_id": id,
 	}).Debug("Attempting to delete song")
 
-	res, err := s.db.Exec(query, id)
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	// Последнее состояние песни сохраняется в ревизии удаления, чтобы её можно было восстановить
+	before, err := lockSongSnapshot(ctx, tx, id)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
-		}).Errorf("Failed to delete song: %v", err)
+		}).Errorf("Failed to load song: %v", err)
+		return queryError(ctx, err)
+	}
+	if before == nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Warn("No song found with the given ID")
+		return fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
+	}
+	if err := checkPrecondition(id, before.Version, precondition); err != nil {
 		return err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	if _, err := tx.ExecContext(ctx, `UPDATE songs SET deleted_at = now(), version = version + 1 WHERE id = $1`, id); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to move song to trash: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := recordRevision(ctx, tx, id, models.RevisionDelete, before, nil, nil); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to record revision: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to commit song deletion: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": id,
+	}).Info("Song moved to trash")
+
+	return nil
+}
+
+// SetSongDetails заполняет пустые поля песни данными из внешнего API и завершает дополнение
+func (s *SongPostgres) SetSongDetails(ctx context.Context, id int, details models.Song) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockSongSnapshot(ctx, tx, id)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
-		}).Errorf("Failed to retrieve affected rows: %v", err)
-		return err
+		}).Errorf("Failed to load song: %v", err)
+		return queryError(ctx, err)
+	}
+	if before == nil {
+		// Песню удалили, пока шло дополнение
+		return fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
 	}
 
-	if rowsAffected == 0 {
+	query := `
+        UPDATE songs SET
+            release_date = COALESCE(release_date, $1),
+            release_date_precision = CASE WHEN release_date IS NULL THEN $2 ELSE release_date_precision END,
+            text = COALESCE(NULLIF(text, ''), $3),
+            link = COALESCE(NULLIF(link, ''), $4),
+            enrichment_status = $5,
+            enrichment_error = NULL,
+            version = version + 1
+        WHERE id = $6
+        RETURNING COALESCE(text, '')
+    `
+
+	releaseDate, precision := releaseDateArgs(details.ReleaseDate)
+
+	var text string
+	err = tx.QueryRowContext(ctx, query, releaseDate, precision, details.Text, details.Link, models.EnrichmentDone, id).Scan(&text)
+	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id": id,
-		}).Warn("No song found with the given ID")
-		return fmt.Errorf("no song found with id %d", id)
+		}).Errorf("Failed to set song details: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := saveLyricSections(ctx, tx, id, text); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to save lyric sections: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if _, err := recordSongChange(ctx, tx, id, before); err != nil {
+		return queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to commit song details: %v", err)
+		return queryError(ctx, err)
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"song_id": id,
-	}).Info("Song deleted successfully")
+	}).Debug("Song details saved")
+	return nil
+}
+
+// SetEnrichmentStatus сохраняет состояние дополнения песни (число попыток и последнюю ошибку)
+func (s *SongPostgres) SetEnrichmentStatus(ctx context.Context, id int, status string, attempts int, lastErr string) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
 
+	query := `UPDATE songs SET enrichment_status = $1, enrichment_attempts = $2, enrichment_error = NULLIF($3, '') WHERE id = $4`
+
+	_, err := s.db.ExecContext(ctx, query, status, attempts, lastErr, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+			"status":  status,
+		}).Errorf("Failed to set enrichment status: %v", err)
+		return queryError(ctx, err)
+	}
 	return nil
 }
+
+// GetPendingEnrichments возвращает песни, которые ещё ожидают дополнения
+func (s *SongPostgres) GetPendingEnrichments(ctx context.Context, limit int) ([]models.EnrichmentJob, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	query := `
+        SELECT id, group_name, song, enrichment_attempts
+        FROM songs
+        WHERE enrichment_status = $1 AND deleted_at IS NULL
+        ORDER BY id
+        LIMIT $2
+    `
+
+	rows, err := s.db.QueryContext(ctx, query, models.EnrichmentPending, limit)
+	if err != nil {
+		logrus.Errorf("Failed to query pending enrichments: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	jobs := make([]models.EnrichmentJob, 0)
+	for rows.Next() {
+		var job models.EnrichmentJob
+		if err := rows.Scan(&job.SongID, &job.GroupName, &job.SongName, &job.Attempts); err != nil {
+			logrus.Errorf("Failed to scan pending enrichment: %v", err)
+			return nil, queryError(ctx, err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return jobs, nil
+}
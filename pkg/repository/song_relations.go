@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+// GetSongRelations загружает связанные данные песни, перечисленные в include (artist, albums, tags, genres)
+func (s *SongPostgres) GetSongRelations(ctx context.Context, songID int, include []string) (models.SongRelations, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var relations models.SongRelations
+	for _, name := range include {
+		var err error
+		switch name {
+		case models.IncludeArtist:
+			relations.Artist, err = s.songArtist(ctx, songID)
+		case models.IncludeAlbums:
+			relations.Albums, err = s.songAlbums(ctx, songID)
+		case models.IncludeTags:
+			relations.Tags, err = s.songTags(ctx, songID)
+		case models.IncludeGenres:
+			relations.Genres, err = s.songGenres(ctx, songID)
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"song_id": songID,
+				"include": name,
+			}).Errorf("Failed to get song relations: %v", err)
+			return models.SongRelations{}, queryError(ctx, err)
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": songID,
+		"include": include,
+	}).Debug("Successfully retrieved song relations")
+	return relations, nil
+}
+
+func (s *SongPostgres) songArtist(ctx context.Context, songID int) (*models.Artist, error) {
+	query := fmt.Sprintf(`SELECT %s FROM artists a JOIN songs ON songs.artist_id = a.id WHERE songs.id = $1`, artistColumns)
+
+	var artist models.Artist
+	err := s.db.QueryRowContext(ctx, query, songID).Scan(&artist.ID, &artist.Name, &artist.SongCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// songAlbums — альбомы песни: сначала ранние релизы, альбомы без даты в конце
+func (s *SongPostgres) songAlbums(ctx context.Context, songID int) ([]models.SongAlbum, error) {
+	query := fmt.Sprintf(`
+        SELECT %s, tr.disc_number, tr.track_number
+        FROM album_tracks tr
+        JOIN albums al ON al.id = tr.album_id
+        JOIN artists ar ON ar.id = al.artist_id
+        WHERE tr.song_id = $1
+        ORDER BY al.release_date NULLS LAST, al.id, tr.disc_number, tr.track_number
+    `, albumColumns)
+
+	rows, err := s.db.QueryContext(ctx, query, songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	albums := make([]models.SongAlbum, 0)
+	for rows.Next() {
+		var album models.SongAlbum
+		if err := scanAlbum(rows, &album.Album, &album.Disc, &album.Track); err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
+
+func (s *SongPostgres) songTags(ctx context.Context, songID int) ([]models.Tag, error) {
+	query := `
+        SELECT t.id, t.name, (
+            SELECT COUNT(*) FROM song_tags x JOIN songs ON songs.id = x.song_id AND songs.deleted_at IS NULL
+            WHERE x.tag_id = t.id
+        )
+        FROM song_tags st
+        JOIN tags t ON t.id = st.tag_id
+        WHERE st.song_id = $1
+        ORDER BY t.name
+    `
+
+	rows, err := s.db.QueryContext(ctx, query, songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]models.Tag, 0)
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.SongCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func (s *SongPostgres) songGenres(ctx context.Context, songID int) ([]models.Genre, error) {
+	query := `
+        SELECT g.id, g.name, g.parent_id
+        FROM song_genres sg
+        JOIN genres g ON g.id = sg.genre_id
+        WHERE sg.song_id = $1
+        ORDER BY lower(g.name), g.id
+    `
+
+	rows, err := s.db.QueryContext(ctx, query, songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := make([]models.Genre, 0)
+	for rows.Next() {
+		var genre models.Genre
+		if err := rows.Scan(&genre.ID, &genre.Name, &genre.ParentID); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, rows.Err()
+}
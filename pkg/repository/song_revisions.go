@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/audit"
+)
+
+// lockSongSnapshot читает текущее состояние песни и блокирует строку до конца транзакции,
+// чтобы ревизии одной песни записывались по очереди. nil — песни нет или она в корзине.
+func lockSongSnapshot(ctx context.Context, tx *sqlx.Tx, id int) (*models.SongSnapshot, error) {
+	var song models.Song
+	err := scanSong(tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, songColumns), id), &song)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	snapshot := models.NewSongSnapshot(song)
+	return &snapshot, nil
+}
+
+// recordRevision записывает ревизию песни в той же транзакции, что и само изменение.
+// old и new — состояние до и после изменения; обновление без реальных изменений не записывается.
+func recordRevision(ctx context.Context, tx *sqlx.Tx, songID int, action string, old, new *models.SongSnapshot, restoredFrom *int) error {
+	changes := models.DiffSnapshots(old, new)
+	if action == models.RevisionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	snapshot := new
+	if snapshot == nil {
+		snapshot = old
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO song_revisions (song_id, revision, action, actor, changes, snapshot, restored_from)
+        SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6
+        FROM song_revisions WHERE song_id = $1
+        RETURNING revision
+    `
+
+	actor := audit.Actor(ctx)
+
+	var revision int
+	if err := tx.QueryRowContext(ctx, query, songID, action, actor, changesJSON, snapshotJSON, restoredFrom).Scan(&revision); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  songID,
+		"revision": revision,
+		"action":   action,
+		"actor":    actor,
+	}).Debug("Song revision recorded")
+	return nil
+}
+
+// recordSongChange записывает ревизию обновления: сравнивает состояние песни до изменения
+// с тем, что получилось после UPDATE в этой же транзакции, и возвращает новое состояние
+func recordSongChange(ctx context.Context, tx *sqlx.Tx, id int, before *models.SongSnapshot) (*models.SongSnapshot, error) {
+	after, err := lockSongSnapshot(ctx, tx, id)
+	if err == nil {
+		err = recordRevision(ctx, tx, id, models.RevisionUpdate, before, after, nil)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to record revision: %v", err)
+		return nil, err
+	}
+	return after, nil
+}
+
+// lockArtistSongs читает состояние всех песен исполнителя, включая песни в корзине, и блокирует их строки
+// до конца транзакции. Ключ — id песни.
+func lockArtistSongs(ctx context.Context, tx *sqlx.Tx, artistID int) (map[int]*models.SongSnapshot, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM songs WHERE artist_id = $1 ORDER BY id FOR UPDATE`, songColumns), artistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make(map[int]*models.SongSnapshot)
+	for rows.Next() {
+		var song models.Song
+		if err := scanSong(rows, &song); err != nil {
+			return nil, err
+		}
+		snapshot := models.NewSongSnapshot(song)
+		snapshots[song.ID] = &snapshot
+	}
+	return snapshots, rows.Err()
+}
+
+// checkPrecondition сверяет версию песни с условием If-Match
+func checkPrecondition(id, version int, precondition models.Precondition) error {
+	if !precondition.Matches(version) {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+			"version": version,
+		}).Warn("Song version does not match If-Match")
+		return fmt.Errorf("song with id %d was modified (current version %d): %w", id, version, apperror.ErrPreconditionFailed)
+	}
+	return nil
+}
+
+// scanRevision читает ревизию; snapshot может быть nil, если колонка не выбиралась
+func scanRevision(row rowScanner, revision *models.SongRevision, snapshot *[]byte) error {
+	var changes []byte
+	dest := []interface{}{&revision.SongID, &revision.Revision, &revision.Action, &revision.Actor,
+		&changes, &revision.RestoredFrom, &revision.CreatedAt}
+	if snapshot != nil {
+		dest = append(dest, snapshot)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	return json.Unmarshal(changes, &revision.Changes)
+}
+
+const revisionColumns = `song_id, revision, action, actor, changes, restored_from, created_at`
+
+// GetRevisions возвращает историю песни от новых ревизий к старым. История удалённой песни
+// тоже доступна; ErrNotFound — только если у песни нет ни одной ревизии.
+func (s *SongPostgres) GetRevisions(ctx context.Context, filter models.RevisionFilter) ([]models.SongRevision, int, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM song_revisions WHERE song_id = $1`, filter.SongID).Scan(&total); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": filter.SongID,
+		}).Errorf("Failed to count revisions: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+	if total == 0 {
+		return nil, 0, fmt.Errorf("revisions of song with id %d: %w", filter.SongID, apperror.ErrNotFound)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM song_revisions
+        WHERE song_id = $1
+        ORDER BY revision DESC
+        LIMIT $2 OFFSET $3
+    `, revisionColumns)
+
+	rows, err := s.db.QueryContext(ctx, query, filter.SongID, filter.Limit, (filter.Page-1)*filter.Limit)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": filter.SongID,
+		}).Errorf("Failed to get revisions: %v", err)
+		return nil, 0, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	revisions := make([]models.SongRevision, 0, filter.Limit)
+	for rows.Next() {
+		var revision models.SongRevision
+		if err := scanRevision(rows, &revision, nil); err != nil {
+			logrus.Errorf("Failed to scan revision: %v", err)
+			return nil, 0, queryError(ctx, err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, queryError(ctx, err)
+	}
+	return revisions, total, nil
+}
+
+// GetRevision возвращает ревизию вместе со снимком состояния песни
+func (s *SongPostgres) GetRevision(ctx context.Context, songID, revision int) (models.SongRevision, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	return getRevision(ctx, s.db, songID, revision)
+}
+
+func getRevision(ctx context.Context, q sqlx.QueryerContext, songID, revision int) (models.SongRevision, error) {
+	query := fmt.Sprintf(`SELECT %s, snapshot FROM song_revisions WHERE song_id = $1 AND revision = $2`, revisionColumns)
+
+	var result models.SongRevision
+	var snapshot []byte
+	if err := scanRevision(q.QueryRowxContext(ctx, query, songID, revision), &result, &snapshot); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.SongRevision{}, fmt.Errorf("revision %d of song with id %d: %w", revision, songID, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"song_id":  songID,
+			"revision": revision,
+		}).Errorf("Failed to get revision: %v", err)
+		return models.SongRevision{}, queryError(ctx, err)
+	}
+
+	result.Snapshot = &models.SongSnapshot{}
+	if err := json.Unmarshal(snapshot, result.Snapshot); err != nil {
+		return models.SongRevision{}, err
+	}
+	return result, nil
+}
+
+// RestoreRevision возвращает песне состояние из ревизии и записывает это как новую ревизию.
+// Песня из корзины восстанавливается, окончательно удалённая — создаётся заново с прежним id.
+func (s *SongPostgres) RestoreRevision(ctx context.Context, songID, revision int) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	source, err := getRevision(ctx, tx, songID, revision)
+	if err != nil {
+		return err
+	}
+	target := source.Snapshot
+
+	current, err := lockSongSnapshot(ctx, tx, songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to load song: %v", err)
+		return queryError(ctx, err)
+	}
+
+	artistID, groupName, err := upsertArtist(ctx, tx, target.GroupName)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":    songID,
+			"group_name": target.GroupName,
+		}).Errorf("Failed to resolve artist: %v", err)
+		return queryError(ctx, err)
+	}
+	target.GroupName = groupName
+
+	releaseDate, precision := releaseDateArgs(target.ReleaseDate)
+
+	query := `
+        UPDATE songs SET artist_id = $1, group_name = $2, song = $3, release_date = $4,
+            release_date_precision = $5, text = $6, lyrics = $7, link = $8, duration = $9, version = version + 1
+        WHERE id = $10
+    `
+	if current == nil {
+		// Песни нет или она в корзине: создаём заново либо достаём из корзины
+		query = `
+            INSERT INTO songs (artist_id, group_name, song, release_date, release_date_precision, text, lyrics, link, duration, id, enrichment_status)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'done')
+            ON CONFLICT (id) DO UPDATE SET artist_id = EXCLUDED.artist_id, group_name = EXCLUDED.group_name,
+                song = EXCLUDED.song, release_date = EXCLUDED.release_date,
+                release_date_precision = EXCLUDED.release_date_precision, text = EXCLUDED.text,
+                lyrics = EXCLUDED.lyrics, link = EXCLUDED.link, duration = EXCLUDED.duration, deleted_at = NULL, version = songs.version + 1
+        `
+	}
+
+	_, err = tx.ExecContext(ctx, query, artistID, groupName, target.SongName, releaseDate, precision,
+		target.Text, target.Lyrics, target.Link, durationArg(target.Duration), songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":  songID,
+			"revision": revision,
+		}).Errorf("Failed to restore song: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := saveLyricSections(ctx, tx, songID, target.Text); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to save lyric sections: %v", err)
+		return queryError(ctx, err)
+	}
+
+	// Снимок ревизии хранит версию, с которой песня вышла из отката
+	restored, err := lockSongSnapshot(ctx, tx, songID)
+	if err == nil {
+		err = recordRevision(ctx, tx, songID, models.RevisionRestore, current, restored, &revision)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to record revision: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to commit song restore: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":   songID,
+		"revision":  revision,
+		"recreated": current == nil,
+	}).Info("Song restored from revision")
+	return nil
+}
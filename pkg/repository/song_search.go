@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"unicode"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+const (
+	searchHeadlineOptions  = `StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=20, MinWords=5, FragmentDelimiter=" … "`
+	searchHighlightOptions = `StartSel=<mark>, StopSel=</mark>, HighlightAll=true`
+	searchMaxLines         = 5
+)
+
+// SearchSongs ищет песни по tsvector-колонке search_vector, сортируя по ts_rank.
+// Для текста возвращаются фрагменты (headline) и строки, в которых есть совпадения.
+func (s *SongPostgres) SearchSongs(ctx context.Context, search models.SongSearch) ([]models.SongSearchResult, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tsQuery, headlineConfig := searchConfig(search.Language, search.Query)
+	offset := (search.Page - 1) * search.Limit
+
+	query := fmt.Sprintf(`
+        WITH q AS (SELECT %s AS query)
+        SELECT %s,
+            ts_rank(s.search_vector, q.query) AS rank,
+            ts_headline($2::regconfig, replace(coalesce(s.text, ''), '\n', E'\n'), q.query, $3) AS headline,
+            ARRAY(
+                SELECT ts_headline($2::regconfig, l.line, q.query, $4)
+                FROM regexp_split_to_table(replace(coalesce(s.text, ''), '\n', E'\n'), E'\n') WITH ORDINALITY AS l(line, n)
+                WHERE to_tsvector($2::regconfig, l.line) @@ q.query
+                ORDER BY l.n
+                LIMIT %d
+            ) AS lines
+        FROM songs s, q
+        WHERE s.search_vector @@ q.query AND s.deleted_at IS NULL
+        ORDER BY rank DESC, s.id
+        LIMIT $5 OFFSET $6
+    `, tsQuery, songColumns, searchMaxLines)
+
+	logrus.WithFields(logrus.Fields{
+		"query":    search.Query,
+		"language": search.Language,
+		"page":     search.Page,
+		"limit":    search.Limit,
+	}).Debug("Executing full-text search")
+
+	rows, err := s.db.QueryContext(ctx, query, search.Query, headlineConfig,
+		searchHeadlineOptions, searchHighlightOptions, search.Limit, offset)
+	if err != nil {
+		logrus.Errorf("Failed to execute search query: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	results := make([]models.SongSearchResult, 0, search.Limit)
+	for rows.Next() {
+		var result models.SongSearchResult
+		if err := scanSong(rows, &result.Song, &result.Rank, &result.Headline, pq.Array(&result.Lines)); err != nil {
+			logrus.Errorf("Failed to scan search result: %v", err)
+			return nil, queryError(ctx, err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating search results: %v", err)
+		return nil, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"query":   search.Query,
+		"results": len(results),
+	}).Debug("Full-text search completed")
+
+	return results, nil
+}
+
+// searchConfig выбирает tsquery и конфигурацию для ts_headline по языку поиска.
+// Без явного языка ищем одновременно с русской и английской морфологией,
+// а подсветку строим по алфавиту запроса.
+func searchConfig(language, query string) (tsQuery string, headlineConfig string) {
+	switch language {
+	case models.SearchLanguageRussian:
+		return "websearch_to_tsquery('russian', $1)", "russian"
+	case models.SearchLanguageEnglish:
+		return "websearch_to_tsquery('english', $1)", "english"
+	}
+
+	headlineConfig = "english"
+	for _, r := range query {
+		if unicode.Is(unicode.Cyrillic, r) {
+			headlineConfig = "russian"
+			break
+		}
+	}
+	return "websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1)", headlineConfig
+}
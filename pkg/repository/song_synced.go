@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+)
+
+// SetSyncedLyrics сохраняет синхронизированный текст песни, заменяя загруженный ранее
+func (s *SongPostgres) SetSyncedLyrics(ctx context.Context, songID int, synced lyrics.SyncedLyrics) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tags, err := json.Marshal(synced.Tags)
+	if err != nil {
+		return err
+	}
+	if synced.Tags == nil {
+		tags = []byte("{}")
+	}
+
+	starts := make([]int64, 0, len(synced.Lines))
+	texts := make([]string, 0, len(synced.Lines))
+	words := make([]sql.NullString, 0, len(synced.Lines))
+	for _, line := range synced.Lines {
+		starts = append(starts, line.StartMs)
+		texts = append(texts, line.Text)
+		if len(line.Words) == 0 {
+			words = append(words, sql.NullString{})
+			continue
+		}
+		raw, err := json.Marshal(line.Words)
+		if err != nil {
+			return err
+		}
+		words = append(words, sql.NullString{String: string(raw), Valid: true})
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO synced_lyrics (song_id, tags) VALUES ($1, $2)
+        ON CONFLICT (song_id) DO UPDATE SET tags = EXCLUDED.tags, updated_at = now()
+    `, songID, string(tags))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to save synced lyrics: %v", err)
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("song with id %d: %w", songID, apperror.ErrNotFound)
+		}
+		return queryError(ctx, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM synced_lines WHERE song_id = $1`, songID); err != nil {
+		return queryError(ctx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO synced_lines (song_id, position, start_ms, text, words)
+        SELECT $1, l.n, l.start_ms, l.text, l.words::jsonb
+        FROM unnest($2::bigint[], $3::text[], $4::text[]) WITH ORDINALITY AS l(start_ms, text, words, n)
+    `, songID, pq.Array(starts), pq.Array(texts), pq.Array(words))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to save synced lines: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to commit synced lyrics: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": songID,
+		"lines":   len(synced.Lines),
+	}).Info("Synced lyrics saved")
+	return nil
+}
+
+func (s *SongPostgres) GetSyncedLyrics(ctx context.Context, songID int) (lyrics.SyncedLyrics, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var tags []byte
+	err := s.db.QueryRowContext(ctx, `SELECT sl.tags FROM synced_lyrics sl JOIN songs s ON s.id = sl.song_id
+         WHERE sl.song_id = $1 AND s.deleted_at IS NULL`, songID).Scan(&tags)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return lyrics.SyncedLyrics{}, fmt.Errorf("synced lyrics for song %d: %w", songID, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to get synced lyrics: %v", err)
+		return lyrics.SyncedLyrics{}, queryError(ctx, err)
+	}
+
+	var synced lyrics.SyncedLyrics
+	if err := json.Unmarshal(tags, &synced.Tags); err != nil {
+		return lyrics.SyncedLyrics{}, err
+	}
+	if len(synced.Tags) == 0 {
+		synced.Tags = nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT start_ms, text, words FROM synced_lines WHERE song_id = $1 ORDER BY position`, songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to get synced lines: %v", err)
+		return lyrics.SyncedLyrics{}, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	synced.Lines = make([]lyrics.SyncedLine, 0)
+	for rows.Next() {
+		var line lyrics.SyncedLine
+		var words []byte
+		if err := rows.Scan(&line.StartMs, &line.Text, &words); err != nil {
+			logrus.Errorf("Failed to scan synced line: %v", err)
+			return lyrics.SyncedLyrics{}, queryError(ctx, err)
+		}
+		if words != nil {
+			if err := json.Unmarshal(words, &line.Words); err != nil {
+				return lyrics.SyncedLyrics{}, err
+			}
+		}
+		synced.Lines = append(synced.Lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return lyrics.SyncedLyrics{}, queryError(ctx, err)
+	}
+	return synced, nil
+}
+
+func (s *SongPostgres) DeleteSyncedLyrics(ctx context.Context, songID int) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM synced_lyrics WHERE song_id = $1`, songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to delete synced lyrics: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("synced lyrics for song %d: %w", songID, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": songID,
+	}).Info("Synced lyrics deleted")
+	return nil
+}
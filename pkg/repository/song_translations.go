@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// SetTranslation добавляет перевод или заменяет существующий перевод на тот же язык
+func (s *SongPostgres) SetTranslation(ctx context.Context, songID int, translation models.Translation) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	query := `
+        INSERT INTO song_translations (song_id, language, text) VALUES ($1, $2, $3)
+        ON CONFLICT (song_id, language) DO UPDATE SET text = EXCLUDED.text, updated_at = now()
+    `
+
+	if _, err := s.db.ExecContext(ctx, query, songID, translation.Language, translation.Text); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":  songID,
+			"language": translation.Language,
+		}).Errorf("Failed to save translation: %v", err)
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("song with id %d: %w", songID, apperror.ErrNotFound)
+		}
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  songID,
+		"language": translation.Language,
+	}).Info("Translation saved")
+	return nil
+}
+
+// GetTranslations возвращает языки, на которые переведена песня (без текстов)
+func (s *SongPostgres) GetTranslations(ctx context.Context, songID int) ([]models.Translation, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	exists, err := songExists(ctx, s.db, songID)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("song with id %d: %w", songID, apperror.ErrNotFound)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT language, updated_at FROM song_translations WHERE song_id = $1 ORDER BY language`, songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+		}).Errorf("Failed to get translations: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	translations := make([]models.Translation, 0)
+	for rows.Next() {
+		var translation models.Translation
+		if err := rows.Scan(&translation.Language, &translation.UpdatedAt); err != nil {
+			return nil, queryError(ctx, err)
+		}
+		translations = append(translations, translation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return translations, nil
+}
+
+func (s *SongPostgres) GetTranslation(ctx context.Context, songID int, language string) (models.Translation, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	translation := models.Translation{Language: language}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT t.text, t.updated_at FROM song_translations t JOIN songs s ON s.id = t.song_id
+         WHERE t.song_id = $1 AND t.language = $2 AND s.deleted_at IS NULL`, songID, language).
+		Scan(&translation.Text, &translation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Translation{}, fmt.Errorf("translation %q of song %d: %w", language, songID, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"song_id":  songID,
+			"language": language,
+		}).Errorf("Failed to get translation: %v", err)
+		return models.Translation{}, queryError(ctx, err)
+	}
+	return translation, nil
+}
+
+func (s *SongPostgres) DeleteTranslation(ctx context.Context, songID int, language string) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM song_translations WHERE song_id = $1 AND language = $2`, songID, language)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":  songID,
+			"language": language,
+		}).Errorf("Failed to delete translation: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("translation %q of song %d: %w", language, songID, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  songID,
+		"language": language,
+	}).Info("Translation deleted")
+	return nil
+}
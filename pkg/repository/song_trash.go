@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// RestoreSong достаёт песню из корзины
+func (s *SongPostgres) RestoreSong(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	var deletedAt *time.Time
+	err = tx.QueryRowContext(ctx, `SELECT deleted_at FROM songs WHERE id = $1 FOR UPDATE`, id).Scan(&deletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to load song: %v", err)
+		return queryError(ctx, err)
+	}
+	if deletedAt == nil {
+		return fmt.Errorf("song with id %d is not in trash: %w", id, apperror.ErrConflict)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE songs SET deleted_at = NULL, version = version + 1 WHERE id = $1`, id); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to restore song from trash: %v", err)
+		return queryError(ctx, err)
+	}
+
+	after, err := lockSongSnapshot(ctx, tx, id)
+	if err == nil {
+		err = recordRevision(ctx, tx, id, models.RevisionRestore, nil, after, nil)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to record revision: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to commit song restore: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":    id,
+		"deleted_at": deletedAt,
+	}).Info("Song restored from trash")
+	return nil
+}
+
+// PurgeSong удаляет песню окончательно, в том числе из корзины. История ревизий сохраняется;
+// если песня не была в корзине, удаление записывается в неё.
+func (s *SongPostgres) PurgeSong(ctx context.Context, id int, precondition models.Precondition) error {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.Errorf("Failed to begin transaction: %v", err)
+		return queryError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	// Версия проверяется и у песни в корзине, поэтому блокируем строку независимо от deleted_at
+	var version int
+	err = tx.QueryRowContext(ctx, `SELECT version FROM songs WHERE id = $1 FOR UPDATE`, id).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logrus.WithFields(logrus.Fields{
+				"song_id": id,
+			}).Warn("No song found with the given ID")
+			return fmt.Errorf("song with id %d: %w", id, apperror.ErrNotFound)
+		}
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to load song: %v", err)
+		return queryError(ctx, err)
+	}
+	if err := checkPrecondition(id, version, precondition); err != nil {
+		return err
+	}
+
+	before, err := lockSongSnapshot(ctx, tx, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to load song: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM songs WHERE id = $1`, id); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to delete song: %v", err)
+		return queryError(ctx, err)
+	}
+
+	if before != nil {
+		if err := recordRevision(ctx, tx, id, models.RevisionDelete, before, nil, nil); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"song_id": id,
+			}).Errorf("Failed to record revision: %v", err)
+			return queryError(ctx, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": id,
+		}).Errorf("Failed to commit song deletion: %v", err)
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": id,
+	}).Info("Song deleted permanently")
+	return nil
+}
+
+// PurgeDeletedSongs окончательно удаляет до limit песен, попавших в корзину раньше before
+func (s *SongPostgres) PurgeDeletedSongs(ctx context.Context, before time.Time, limit int) (int, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	query := `
+        DELETE FROM songs
+        WHERE id IN (
+            SELECT id FROM songs
+            WHERE deleted_at < $1
+            ORDER BY deleted_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+    `
+
+	res, err := s.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		logrus.Errorf("Failed to purge trashed songs: %v", err)
+		return 0, queryError(ctx, err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(purged), nil
+}
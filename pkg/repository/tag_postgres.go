@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+type TagPostgres struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewTagPostgres(db *sqlx.DB, queryTimeout time.Duration) *TagPostgres {
+	return &TagPostgres{db: db, queryTimeout: queryTimeout}
+}
+
+// GetTags возвращает теги, которые стоят хотя бы на одной песне, начиная с самых популярных
+func (r *TagPostgres) GetTags(ctx context.Context) ([]models.Tag, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+        SELECT t.id, t.name, COUNT(*)
+        FROM tags t
+        JOIN song_tags st ON st.tag_id = t.id
+        JOIN songs s ON s.id = st.song_id AND s.deleted_at IS NULL
+        GROUP BY t.id, t.name
+        ORDER BY 3 DESC, t.name
+    `
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		logrus.Errorf("Failed to get tags: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	tags := make([]models.Tag, 0)
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.SongCount); err != nil {
+			logrus.Errorf("Failed to scan tag: %v", err)
+			return nil, queryError(ctx, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return tags, nil
+}
+
+// AttachTag ставит тег на песню, создавая тег при первом использовании
+func (r *TagPostgres) AttachTag(ctx context.Context, songID int, name string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+        WITH tag AS (
+            INSERT INTO tags (name) VALUES ($2)
+            ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+            RETURNING id
+        )
+        INSERT INTO song_tags (song_id, tag_id)
+        SELECT $1, tag.id FROM tag
+        ON CONFLICT DO NOTHING
+    `
+
+	_, err := r.db.ExecContext(ctx, query, songID, name)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+			"tag":     name,
+		}).Errorf("Failed to attach tag: %v", err)
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("song with id %d: %w", songID, apperror.ErrNotFound)
+		}
+		return queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": songID,
+		"tag":     name,
+	}).Debug("Tag attached to song")
+	return nil
+}
+
+func (r *TagPostgres) DetachTag(ctx context.Context, songID int, name string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+        DELETE FROM song_tags st
+        USING tags t
+        WHERE t.id = st.tag_id AND st.song_id = $1 AND t.name = $2
+    `
+
+	res, err := r.db.ExecContext(ctx, query, songID, name)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id": songID,
+			"tag":     name,
+		}).Errorf("Failed to detach tag: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag %q on song %d: %w", name, songID, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": songID,
+		"tag":     name,
+	}).Debug("Tag detached from song")
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+type UserPostgres struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+}
+
+func NewUserPostgres(db *sqlx.DB, queryTimeout time.Duration) *UserPostgres {
+	return &UserPostgres{db: db, queryTimeout: queryTimeout}
+}
+
+// userColumns — колонки пользователя в порядке, который ожидает scanUser
+const userColumns = `id, username, role, created_at, updated_at, password_hash, token_version`
+
+func scanUser(row rowScanner, user *models.User) error {
+	return row.Scan(&user.ID, &user.Username, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.PasswordHash, &user.TokenVersion)
+}
+
+func (r *UserPostgres) AddUser(ctx context.Context, user models.User) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `
+        INSERT INTO users (username, password_hash, role) VALUES ($1, $2, $3) RETURNING id
+    `, user.Username, user.PasswordHash, user.Role).Scan(&id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username": user.Username,
+		}).Errorf("Failed to add user: %v", err)
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("user %q already exists: %w", user.Username, apperror.ErrConflict)
+		}
+		return 0, queryError(ctx, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  id,
+		"username": user.Username,
+		"role":     user.Role,
+	}).Info("User added successfully")
+	return id, nil
+}
+
+func (r *UserPostgres) GetUsers(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM users ORDER BY username`, userColumns))
+	if err != nil {
+		logrus.Errorf("Failed to execute users query: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		if err := scanUser(rows, &user); err != nil {
+			logrus.Errorf("Failed to scan user: %v", err)
+			return nil, queryError(ctx, err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		logrus.Errorf("Error after iterating users: %v", err)
+		return nil, queryError(ctx, err)
+	}
+	return users, nil
+}
+
+func (r *UserPostgres) GetUser(ctx context.Context, id int) (models.User, error) {
+	return r.getUser(ctx, "id = $1", id)
+}
+
+func (r *UserPostgres) GetUserByName(ctx context.Context, username string) (models.User, error) {
+	return r.getUser(ctx, "username = $1", username)
+}
+
+func (r *UserPostgres) getUser(ctx context.Context, where string, arg interface{}) (models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var user models.User
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE %s`, userColumns, where)
+	if err := scanUser(r.db.QueryRowContext(ctx, query, arg), &user); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, fmt.Errorf("user %v: %w", arg, apperror.ErrNotFound)
+		}
+		logrus.Errorf("Failed to get user: %v", err)
+		return models.User{}, queryError(ctx, err)
+	}
+	return user, nil
+}
+
+// SetUserRole меняет роль пользователя и отзывает его refresh-токены
+func (r *UserPostgres) SetUserRole(ctx context.Context, id int, role string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE users SET role = $1, token_version = token_version + 1, updated_at = now() WHERE id = $2
+    `, role, id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"user_id": id,
+		}).Errorf("Failed to set user role: %v", err)
+		return queryError(ctx, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		logrus.WithFields(logrus.Fields{
+			"user_id": id,
+		}).Warn("No user found with the given ID")
+		return fmt.Errorf("user with id %d: %w", id, apperror.ErrNotFound)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": id,
+		"role":    role,
+	}).Info("User role changed")
+	return nil
+}
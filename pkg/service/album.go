@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+type AlbumService struct {
+	repo repository.Album
+}
+
+func NewAlbumService(repo repository.Album) *AlbumService {
+	return &AlbumService{repo: repo}
+}
+
+func validAlbumType(albumType string) bool {
+	switch albumType {
+	case models.AlbumTypeLP, models.AlbumTypeEP, models.AlbumTypeSingle, models.AlbumTypeCompilation:
+		return true
+	}
+	return false
+}
+
+func (s *AlbumService) AddAlbum(ctx context.Context, album models.Album) (int, error) {
+	album.Title = strings.TrimSpace(album.Title)
+	album.Artist = artistName(album.Artist)
+	if album.Title == "" {
+		return 0, apperror.NewValidationError("title", "must not be blank")
+	}
+	if album.Artist == "" {
+		return 0, apperror.NewValidationError("artist", "must not be blank")
+	}
+	if album.Type == "" {
+		album.Type = models.AlbumTypeLP
+	}
+	if !validAlbumType(album.Type) {
+		return 0, apperror.NewValidationError("type", "must be one of: LP, EP, single, compilation")
+	}
+	return s.repo.AddAlbum(ctx, album)
+}
+
+func (s *AlbumService) GetAlbums(ctx context.Context, filter models.AlbumFilter) (models.AlbumPage, error) {
+	if filter.Page < 1 {
+		return models.AlbumPage{}, apperror.NewValidationError("page", "must be a positive integer")
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize {
+		return models.AlbumPage{}, apperror.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxPageSize))
+	}
+	if filter.Type != "" && !validAlbumType(filter.Type) {
+		return models.AlbumPage{}, apperror.NewValidationError("type", "must be one of: LP, EP, single, compilation")
+	}
+
+	albums, total, err := s.repo.GetAlbums(ctx, filter)
+	if err != nil {
+		return models.AlbumPage{}, err
+	}
+
+	return models.AlbumPage{
+		Items:      albums,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+func (s *AlbumService) GetAlbum(ctx context.Context, id int) (models.Album, error) {
+	return s.repo.GetAlbum(ctx, id)
+}
+
+func (s *AlbumService) UpdateAlbum(ctx context.Context, id int, album models.Album) error {
+	album.Title = strings.TrimSpace(album.Title)
+	album.Artist = artistName(album.Artist)
+	if album.Type != "" && !validAlbumType(album.Type) {
+		return apperror.NewValidationError("type", "must be one of: LP, EP, single, compilation")
+	}
+	return s.repo.UpdateAlbum(ctx, id, album)
+}
+
+// SetAlbumTracks проверяет трек-лист: номера положительные, позиции (диск, трек) не повторяются.
+// Диск по умолчанию — первый.
+func (s *AlbumService) SetAlbumTracks(ctx context.Context, id int, tracks []models.TrackPosition) error {
+	verr := &apperror.ValidationError{}
+	seen := make(map[[2]int]bool, len(tracks))
+
+	for i := range tracks {
+		field := fmt.Sprintf("tracks[%d]", i)
+		if tracks[i].Disc == 0 {
+			tracks[i].Disc = 1
+		}
+		switch {
+		case tracks[i].SongID < 1:
+			verr.Add(field+".songId", "must be a positive integer")
+		case tracks[i].Disc < 1:
+			verr.Add(field+".disc", "must be a positive integer")
+		case tracks[i].Track < 1:
+			verr.Add(field+".track", "must be a positive integer")
+		}
+
+		position := [2]int{tracks[i].Disc, tracks[i].Track}
+		if seen[position] {
+			verr.Add(field, fmt.Sprintf("duplicate position: disc %d, track %d", position[0], position[1]))
+		}
+		seen[position] = true
+	}
+
+	if len(verr.Fields) > 0 {
+		return verr
+	}
+	return s.repo.SetAlbumTracks(ctx, id, tracks)
+}
+
+func (s *AlbumService) DeleteAlbum(ctx context.Context, id int) error {
+	return s.repo.DeleteAlbum(ctx, id)
+}
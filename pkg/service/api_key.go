@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/ratelimit"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+const (
+	// apiKeyPrefix отличает ключи сервиса от других секретов (например, при поиске утечек в репозиториях)
+	apiKeyPrefix = "mlk_"
+	// apiKeyShownPrefix — сколько первых символов ключа хранится открыто, чтобы ключ можно было узнать в списке
+	apiKeyShownPrefix = 12
+	apiKeyBytes       = 32
+	// maxAPIKeyRateLimit — верхняя граница лимита ключа, запросов в минуту
+	maxAPIKeyRateLimit = 100000
+)
+
+// errInvalidAPIKey — общий ответ на неизвестный и отозванный ключ
+var errInvalidAPIKey = fmt.Errorf("invalid or revoked API key: %w", apperror.ErrUnauthorized)
+
+// APIKeyConfig — лимит ключей по умолчанию и хранилище ведёр ограничителя частоты запросов
+type APIKeyConfig struct {
+	DefaultRateLimit int
+	DefaultBurst     int
+	Limiter          ratelimit.Store
+}
+
+type APIKeyService struct {
+	repo   repository.APIKey
+	config APIKeyConfig
+}
+
+func NewAPIKeyService(repo repository.APIKey, config APIKeyConfig) *APIKeyService {
+	return &APIKeyService{repo: repo, config: config}
+}
+
+// hashAPIKey — SHA-256 ключа. Ключ случайный и длинный, поэтому медленный хеш вроде bcrypt не нужен,
+// а быстрый позволяет искать ключ по хешу через индекс.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateLimits проверяет лимит ключа; без burst он равен rateLimit
+func validateLimits(rateLimit, burst int) (int, int, error) {
+	if burst == 0 {
+		burst = rateLimit
+	}
+	validationErr := &apperror.ValidationError{}
+	if rateLimit < 1 || rateLimit > maxAPIKeyRateLimit {
+		validationErr.Add("rateLimit", fmt.Sprintf("must be between 1 and %d requests per minute", maxAPIKeyRateLimit))
+	}
+	if burst < 1 || burst > maxAPIKeyRateLimit {
+		validationErr.Add("burst", fmt.Sprintf("must be between 1 and %d", maxAPIKeyRateLimit))
+	}
+	if len(validationErr.Fields) > 0 {
+		return 0, 0, validationErr
+	}
+	return rateLimit, burst, nil
+}
+
+// CreateAPIKey создаёт ключ и возвращает его вместе с самим ключом; в базе остаётся только хеш
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, key models.NewAPIKey, createdBy int) (models.IssuedAPIKey, error) {
+	name := strings.TrimSpace(key.Name)
+	if name == "" || utf8.RuneCountInString(name) > songColumnLimit {
+		return models.IssuedAPIKey{}, apperror.NewValidationError("name", fmt.Sprintf("must be 1 to %d characters", songColumnLimit))
+	}
+
+	if len(key.Scopes) == 0 {
+		return models.IssuedAPIKey{}, apperror.NewValidationError("scopes", "must not be empty")
+	}
+	scopes := slices.Clone(key.Scopes)
+	for _, scope := range scopes {
+		if !slices.Contains(models.Scopes, scope) {
+			return models.IssuedAPIKey{}, apperror.NewValidationError("scopes", fmt.Sprintf("unknown scope %q, expected %s", scope, strings.Join(models.Scopes, ", ")))
+		}
+	}
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	rateLimit, burst := key.RateLimit, key.Burst
+	if rateLimit == 0 && burst == 0 {
+		rateLimit, burst = s.config.DefaultRateLimit, s.config.DefaultBurst
+	}
+	rateLimit, burst, err := validateLimits(rateLimit, burst)
+	if err != nil {
+		return models.IssuedAPIKey{}, err
+	}
+
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return models.IssuedAPIKey{}, err
+	}
+	raw := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	stored := models.APIKey{
+		Name:      name,
+		Prefix:    raw[:apiKeyShownPrefix],
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		Burst:     burst,
+		KeyHash:   hashAPIKey(raw),
+	}
+	if createdBy != 0 {
+		stored.CreatedBy = &createdBy
+	}
+
+	created, err := s.repo.AddAPIKey(ctx, stored)
+	if err != nil {
+		return models.IssuedAPIKey{}, err
+	}
+	return models.IssuedAPIKey{APIKey: created, Key: raw}, nil
+}
+
+func (s *APIKeyService) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.repo.GetAPIKeys(ctx)
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	return s.repo.RevokeAPIKey(ctx, id)
+}
+
+// SetAPIKeyLimits меняет лимит ключа; ограничитель применяет его со следующего запроса
+func (s *APIKeyService) SetAPIKeyLimits(ctx context.Context, id int, limits models.APIKeyLimits) error {
+	rateLimit, burst, err := validateLimits(limits.RateLimit, limits.Burst)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetAPIKeyLimits(ctx, id, rateLimit, burst)
+}
+
+// AuthenticateAPIKey находит действующий ключ и берёт токен из его ведра. Возвращает владельца запроса
+// и состояние лимита; превышен ли лимит (Result.Allowed == false), решает вызывающий.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, raw string) (models.Principal, ratelimit.Result, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return models.Principal{}, ratelimit.Result{}, errInvalidAPIKey
+	}
+
+	key, err := s.repo.GetActiveAPIKey(ctx, hashAPIKey(raw))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return models.Principal{}, ratelimit.Result{}, errInvalidAPIKey
+	}
+	if err != nil {
+		return models.Principal{}, ratelimit.Result{}, err
+	}
+
+	result, err := s.config.Limiter.Take(ctx, "api-key:"+strconv.Itoa(key.ID), ratelimit.Limit{PerMinute: key.RateLimit, Burst: key.Burst})
+	if err != nil {
+		return models.Principal{}, ratelimit.Result{}, err
+	}
+
+	principal := models.Principal{
+		Username: "key:" + key.Name,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}
+	return principal, result, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+type ArtistService struct {
+	repo repository.Artist
+}
+
+func NewArtistService(repo repository.Artist) *ArtistService {
+	return &ArtistService{repo: repo}
+}
+
+// artistName убирает пробелы по краям и схлопывает повторяющиеся пробелы внутри названия
+func artistName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func (s *ArtistService) AddArtist(ctx context.Context, name string) (int, error) {
+	name = artistName(name)
+	if name == "" {
+		return 0, apperror.NewValidationError("name", "must not be blank")
+	}
+	return s.repo.AddArtist(ctx, name)
+}
+
+func (s *ArtistService) GetArtists(ctx context.Context, filter models.ArtistFilter) (models.ArtistPage, error) {
+	if filter.Page < 1 {
+		return models.ArtistPage{}, apperror.NewValidationError("page", "must be a positive integer")
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize {
+		return models.ArtistPage{}, apperror.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxPageSize))
+	}
+
+	artists, total, err := s.repo.GetArtists(ctx, filter)
+	if err != nil {
+		return models.ArtistPage{}, err
+	}
+
+	return models.ArtistPage{
+		Items:      artists,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+func (s *ArtistService) GetArtist(ctx context.Context, id int) (models.Artist, error) {
+	return s.repo.GetArtist(ctx, id)
+}
+
+func (s *ArtistService) UpdateArtist(ctx context.Context, id int, name string) error {
+	name = artistName(name)
+	if name == "" {
+		return apperror.NewValidationError("name", "must not be blank")
+	}
+	return s.repo.UpdateArtist(ctx, id, name)
+}
+
+func (s *ArtistService) DeleteArtist(ctx context.Context, id int) error {
+	return s.repo.DeleteArtist(ctx, id)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// tokenIssuer — издатель токенов (claim iss)
+	tokenIssuer = "music-lib"
+	// Тип токена (claim typ): access-токен нельзя использовать для обновления и наоборот
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+
+	minPasswordLength = 8
+	// maxPasswordBytes — bcrypt учитывает только первые 72 байта пароля
+	maxPasswordBytes  = 72
+	maxUsernameLength = 64
+)
+
+// errInvalidCredentials — общий ответ на неверное имя или пароль, чтобы не раскрывать, какие имена заняты
+var errInvalidCredentials = fmt.Errorf("invalid username or password: %w", apperror.ErrUnauthorized)
+
+// dummyPasswordHash сравнивается с паролем, когда пользователя нет, чтобы время ответа
+// не выдавало существующие имена
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("music-lib"), bcrypt.DefaultCost)
+
+// AuthConfig — ключи подписи и время жизни токенов. Ключи access- и refresh-токенов разные,
+// поэтому один тип токена нельзя подделать другим.
+type AuthConfig struct {
+	AccessSecret  []byte
+	RefreshSecret []byte
+	AccessTTL     time.Duration
+	RefreshTTL    time.Duration
+}
+
+type AuthService struct {
+	repo   repository.User
+	config AuthConfig
+}
+
+func NewAuthService(repo repository.User, config AuthConfig) *AuthService {
+	return &AuthService{repo: repo, config: config}
+}
+
+// tokenClaims — содержимое токенов: sub — id пользователя, ver — версия refresh-токенов пользователя
+type tokenClaims struct {
+	Type     string `json:"typ"`
+	Username string `json:"name"`
+	Role     string `json:"role,omitempty"`
+	Version  int    `json:"ver,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// normalizeUsername убирает пробелы по краям и приводит имя к нижнему регистру
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func validateRole(role string) error {
+	if !slices.Contains(models.Roles, role) {
+		return apperror.NewValidationError("role", "must be one of viewer, editor, admin")
+	}
+	return nil
+}
+
+// Login проверяет имя и пароль и выдаёт пару токенов
+func (s *AuthService) Login(ctx context.Context, credentials models.Credentials) (models.TokenPair, error) {
+	user, err := s.repo.GetUserByName(ctx, normalizeUsername(credentials.Username))
+	if errors.Is(err, apperror.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(credentials.Password))
+		return models.TokenPair{}, errInvalidCredentials
+	}
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password)); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username": user.Username,
+		}).Warn("Failed login attempt")
+		return models.TokenPair{}, errInvalidCredentials
+	}
+	return s.issueTokens(user)
+}
+
+// Refresh выдаёт новую пару токенов по refresh-токену. Роль берётся из базы, поэтому новый
+// access-токен учитывает её изменения; после смены роли старые refresh-токены отклоняются.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	claims, err := s.parseToken(refreshToken, refreshTokenType, s.config.RefreshSecret)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf("invalid token subject: %w", apperror.ErrUnauthorized)
+	}
+
+	user, err := s.repo.GetUser(ctx, id)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return models.TokenPair{}, fmt.Errorf("user of the token no longer exists: %w", apperror.ErrUnauthorized)
+	}
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	if claims.Version != user.TokenVersion {
+		return models.TokenPair{}, fmt.Errorf("refresh token has been revoked: %w", apperror.ErrUnauthorized)
+	}
+	return s.issueTokens(user)
+}
+
+// ParseAccessToken проверяет подпись и срок действия access-токена и возвращает его владельца
+func (s *AuthService) ParseAccessToken(token string) (models.Principal, error) {
+	claims, err := s.parseToken(token, accessTokenType, s.config.AccessSecret)
+	if err != nil {
+		return models.Principal{}, err
+	}
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil || validateRole(claims.Role) != nil {
+		return models.Principal{}, fmt.Errorf("invalid access token: %w", apperror.ErrUnauthorized)
+	}
+	return models.Principal{UserID: id, Username: claims.Username, Role: claims.Role}, nil
+}
+
+func (s *AuthService) parseToken(token, tokenType string, secret []byte) (*tokenClaims, error) {
+	claims := &tokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tokenIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s token: %v: %w", tokenType, err, apperror.ErrUnauthorized)
+	}
+	if claims.Type != tokenType {
+		return nil, fmt.Errorf("expected %s token: %w", tokenType, apperror.ErrUnauthorized)
+	}
+	return claims, nil
+}
+
+func (s *AuthService) issueTokens(user models.User) (models.TokenPair, error) {
+	now := time.Now()
+	registered := func(ttl time.Duration) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.Itoa(user.ID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		}
+	}
+
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
+		Type:             accessTokenType,
+		Username:         user.Username,
+		Role:             user.Role,
+		RegisteredClaims: registered(s.config.AccessTTL),
+	}).SignedString(s.config.AccessSecret)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	refresh, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
+		Type:             refreshTokenType,
+		Username:         user.Username,
+		Version:          user.TokenVersion,
+		RegisteredClaims: registered(s.config.RefreshTTL),
+	}).SignedString(s.config.RefreshSecret)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.config.AccessTTL.Seconds()),
+	}, nil
+}
+
+// CreateUser проверяет имя, пароль и роль и сохраняет пользователя с bcrypt-хешем пароля
+func (s *AuthService) CreateUser(ctx context.Context, user models.NewUser) (int, error) {
+	username := normalizeUsername(user.Username)
+	role := user.Role
+	if role == "" {
+		role = models.RoleViewer
+	}
+
+	validationErr := &apperror.ValidationError{}
+	if username == "" || utf8.RuneCountInString(username) > maxUsernameLength || strings.ContainsFunc(username, isSpaceOrControl) {
+		validationErr.Add("username", fmt.Sprintf("must be 1 to %d characters without spaces", maxUsernameLength))
+	}
+	if utf8.RuneCountInString(user.Password) < minPasswordLength || len(user.Password) > maxPasswordBytes {
+		validationErr.Add("password", fmt.Sprintf("must be at least %d characters and at most %d bytes", minPasswordLength, maxPasswordBytes))
+	}
+	if err := validateRole(role); err != nil {
+		validationErr.Add("role", "must be one of viewer, editor, admin")
+	}
+	if len(validationErr.Fields) > 0 {
+		return 0, validationErr
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.AddUser(ctx, models.User{Username: username, PasswordHash: string(hash), Role: role})
+}
+
+func isSpaceOrControl(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsControl(r)
+}
+
+func (s *AuthService) GetUsers(ctx context.Context) ([]models.User, error) {
+	return s.repo.GetUsers(ctx)
+}
+
+func (s *AuthService) SetUserRole(ctx context.Context, id int, role string) error {
+	if err := validateRole(role); err != nil {
+		return err
+	}
+	return s.repo.SetUserRole(ctx, id, role)
+}
+
+// EnsureAdmin создаёт администратора при запуске, если пользователя с таким именем ещё нет.
+// Существующий пользователь не меняется.
+func (s *AuthService) EnsureAdmin(ctx context.Context, username, password string) error {
+	_, err := s.CreateUser(ctx, models.NewUser{Username: username, Password: password, Role: models.RoleAdmin})
+	if errors.Is(err, apperror.ErrConflict) {
+		return nil
+	}
+	return err
+}
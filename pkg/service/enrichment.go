@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/audit"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+type EnrichmentConfig struct {
+	Workers         int
+	QueueSize       int
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	PollInterval    time.Duration
+}
+
+// Enricher — пул фоновых воркеров, дополняющих новые песни данными из SongDetailProvider.
+// Песни, которые не удалось обработать за MaxAttempts попыток, помечаются как failed.
+type Enricher struct {
+	repo    repository.Song
+	details SongDetailProvider
+	cfg     EnrichmentConfig
+
+	jobs   chan models.EnrichmentJob
+	stop   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	inflight map[int]struct{}
+	closed   bool
+}
+
+func NewEnricher(repo repository.Song, details SongDetailProvider, cfg EnrichmentConfig) *Enricher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Second
+	}
+	if cfg.MaxRetryBackoff < cfg.RetryBackoff {
+		cfg.MaxRetryBackoff = cfg.RetryBackoff
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+
+	// Изменения, внесённые воркерами, попадают в историю ревизий от имени enrichment
+	ctx, cancel := context.WithCancel(audit.WithActor(context.Background(), "enrichment"))
+	return &Enricher{
+		repo:     repo,
+		details:  details,
+		cfg:      cfg,
+		jobs:     make(chan models.EnrichmentJob, cfg.QueueSize),
+		stop:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+		inflight: make(map[int]struct{}),
+	}
+}
+
+// Start запускает воркеров и периодически подбирает песни, оставшиеся в статусе pending
+// (например, после перезапуска или переполнения очереди)
+func (e *Enricher) Start() {
+	logrus.WithFields(logrus.Fields{
+		"workers":      e.cfg.Workers,
+		"queue_size":   e.cfg.QueueSize,
+		"max_attempts": e.cfg.MaxAttempts,
+	}).Info("Starting enrichment workers...")
+
+	for i := 0; i < e.cfg.Workers; i++ {
+		e.wg.Add(1)
+		go e.worker(i)
+	}
+
+	e.wg.Add(1)
+	go e.poll()
+}
+
+// Enqueue ставит песню в очередь. Возвращает false, если очередь заполнена или закрыта —
+// в этом случае песня останется в статусе pending и будет подобрана позже.
+func (e *Enricher) Enqueue(job models.EnrichmentJob) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return false
+	}
+	if _, ok := e.inflight[job.SongID]; ok {
+		return true
+	}
+
+	select {
+	case e.jobs <- job:
+		e.inflight[job.SongID] = struct{}{}
+		return true
+	default:
+		logrus.WithField("song_id", job.SongID).Warn("Enrichment queue is full, song stays pending")
+		return false
+	}
+}
+
+// Shutdown перестаёт принимать новые задачи и ждёт, пока воркеры разберут очередь.
+// Если ctx истекает раньше, незавершённые задачи прерываются и остаются в статусе pending.
+func (e *Enricher) Shutdown(ctx context.Context) error {
+	logrus.Info("Draining enrichment queue...")
+
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.stop)
+		close(e.jobs)
+	}
+	e.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		e.cancel()
+		logrus.Info("Enrichment workers stopped")
+		return nil
+	case <-ctx.Done():
+		e.cancel()
+		<-done
+		logrus.Warn("Enrichment queue was not drained before shutdown deadline")
+		return ctx.Err()
+	}
+}
+
+func (e *Enricher) worker(n int) {
+	defer e.wg.Done()
+
+	for job := range e.jobs {
+		e.process(job)
+
+		e.mu.Lock()
+		delete(e.inflight, job.SongID)
+		e.mu.Unlock()
+	}
+
+	logrus.Debugf("Enrichment worker %d stopped", n)
+}
+
+func (e *Enricher) process(job models.EnrichmentJob) {
+	log := logrus.WithFields(logrus.Fields{
+		"song_id": job.SongID,
+		"group":   job.GroupName,
+		"song":    job.SongName,
+	})
+
+	for job.Attempts < e.cfg.MaxAttempts {
+		if job.Attempts > 0 {
+			if err := e.wait(job.Attempts); err != nil {
+				log.Debug("Enrichment interrupted by shutdown, song stays pending")
+				return
+			}
+		}
+
+		detail, err := e.details.GetSongDetail(e.ctx, job.GroupName, job.SongName)
+		if err == nil {
+			releaseDate, parseErr := models.ParseReleaseDate(detail.ReleaseDate)
+			if parseErr != nil {
+				log.Warnf("Ignoring release date from song details: %v", parseErr)
+			}
+			err = e.repo.SetSongDetails(e.ctx, job.SongID, models.Song{
+				ReleaseDate: releaseDate,
+				Text:        detail.Text,
+				Link:        detail.Link,
+			})
+			if err == nil {
+				log.Info("Song enriched successfully")
+				return
+			}
+			if errors.Is(err, apperror.ErrNotFound) {
+				log.Info("Song was deleted before enrichment completed")
+				return
+			}
+		}
+
+		if e.ctx.Err() != nil {
+			log.Debug("Enrichment interrupted by shutdown, song stays pending")
+			return
+		}
+
+		job.Attempts++
+		if errors.Is(err, ErrSongDetailNotFound) || job.Attempts >= e.cfg.MaxAttempts {
+			log.WithField("attempt", job.Attempts).Errorf("Song moved to dead-letter state: %v", err)
+			_ = e.repo.SetEnrichmentStatus(e.ctx, job.SongID, models.EnrichmentFailed, job.Attempts, err.Error())
+			return
+		}
+
+		log.WithField("attempt", job.Attempts).Warnf("Song enrichment failed, will retry: %v", err)
+		if err := e.repo.SetEnrichmentStatus(e.ctx, job.SongID, models.EnrichmentPending, job.Attempts, err.Error()); err != nil {
+			return
+		}
+	}
+
+	log.Error("Song moved to dead-letter state after exhausting enrichment attempts")
+	_ = e.repo.SetEnrichmentStatus(e.ctx, job.SongID, models.EnrichmentFailed, job.Attempts, "")
+}
+
+func (e *Enricher) poll() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		e.requeuePending()
+
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Enricher) requeuePending() {
+	jobs, err := e.repo.GetPendingEnrichments(e.ctx, e.cfg.QueueSize)
+	if err != nil {
+		logrus.Errorf("Failed to load pending enrichments: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if !e.Enqueue(job) {
+			return
+		}
+	}
+
+	if len(jobs) > 0 {
+		logrus.Debugf("Requeued %d pending songs for enrichment", len(jobs))
+	}
+}
+
+// wait — экспоненциальная задержка между попытками, прерывается при остановке
+func (e *Enricher) wait(attempt int) error {
+	delay := e.cfg.RetryBackoff << (attempt - 1)
+	if delay <= 0 || delay > e.cfg.MaxRetryBackoff {
+		delay = e.cfg.MaxRetryBackoff
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-e.ctx.Done():
+		return e.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+type GenreService struct {
+	repo repository.Genre
+}
+
+func NewGenreService(repo repository.Genre) *GenreService {
+	return &GenreService{repo: repo}
+}
+
+func (s *GenreService) AddGenre(ctx context.Context, genre models.Genre) (int, error) {
+	genre.Name = strings.TrimSpace(genre.Name)
+	if genre.Name == "" {
+		return 0, apperror.NewValidationError("name", "must not be blank")
+	}
+	if genre.ParentID != nil && *genre.ParentID < 1 {
+		return 0, apperror.NewValidationError("parentId", "must be a positive integer")
+	}
+	return s.repo.AddGenre(ctx, genre)
+}
+
+func (s *GenreService) GetGenres(ctx context.Context) ([]models.Genre, error) {
+	return s.repo.GetGenres(ctx)
+}
+
+func (s *GenreService) DeleteGenre(ctx context.Context, id int) error {
+	return s.repo.DeleteGenre(ctx, id)
+}
+
+func (s *GenreService) AttachGenre(ctx context.Context, songID, genreID int) error {
+	return s.repo.AttachGenre(ctx, songID, genreID)
+}
+
+func (s *GenreService) DetachGenre(ctx context.Context, songID, genreID int) error {
+	return s.repo.DetachGenre(ctx, songID, genreID)
+}
@@ -1,31 +1,337 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
 	"github.com/skorpsrgvch/music-lib/pkg/repository"
 )
 
 type SongService struct {
-	repo repository.Song
+	repo     repository.Song
+	enricher *Enricher
+}
+
+func NewSongService(repo repository.Song, enricher *Enricher) *SongService {
+	return &SongService{repo: repo, enricher: enricher}
+}
+
+const maxPageSize = 100
+
+func (s *SongService) AddSong(ctx context.Context, list models.Song) (int, error) {
+	list.GroupName = artistName(list.GroupName)
+	list.SongName = strings.TrimSpace(list.SongName)
+	if list.GroupName == "" {
+		return 0, apperror.NewValidationError("group", "must not be blank")
+	}
+	if list.SongName == "" {
+		return 0, apperror.NewValidationError("song", "must not be blank")
+	}
+	if list.Duration < 0 {
+		return 0, apperror.NewValidationError("duration", "must not be negative")
+	}
+
+	needsDetails := s.needsEnrichment(list)
+	list.EnrichmentStatus = models.EnrichmentDone
+	if needsDetails {
+		list.EnrichmentStatus = models.EnrichmentPending
+	}
+
+	id, err := s.repo.AddSong(ctx, list)
+	if err != nil {
+		return 0, err
+	}
+
+	if needsDetails {
+		s.enricher.Enqueue(models.EnrichmentJob{
+			SongID:    id,
+			GroupName: list.GroupName,
+			SongName:  list.SongName,
+		})
+	}
+	return id, nil
+}
+
+// needsEnrichment — передали только группу и название: дату релиза, текст и ссылку дозаполнят фоновые воркеры
+func (s *SongService) needsEnrichment(song models.Song) bool {
+	return s.enricher != nil && song.ReleaseDate.IsZero() && song.Text == "" && song.Link == ""
+}
+
+// GetSong возвращает песню вместе со связанными данными, перечисленными в query.Include
+func (s *SongService) GetSong(ctx context.Context, id int, query models.SongQuery) (models.SongDetails, error) {
+	if err := validateSongFields(query.Fields); err != nil {
+		return models.SongDetails{}, err
+	}
+	include, err := songIncludes(query.Include)
+	if err != nil {
+		return models.SongDetails{}, err
+	}
+
+	song, err := s.repo.GetSong(ctx, id)
+	if err != nil {
+		return models.SongDetails{}, err
+	}
+	details := models.SongDetails{Song: song}
+	if len(include) > 0 {
+		if details.SongRelations, err = s.repo.GetSongRelations(ctx, id, include); err != nil {
+			return models.SongDetails{}, err
+		}
+	}
+	return details, nil
+}
+
+// validateSongFields проверяет, что параметр fields перечисляет только поля песни
+func validateSongFields(fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(models.SongFields, field) {
+			return apperror.NewValidationError("fields", fmt.Sprintf("unknown field %q, expected one of: %s", field, strings.Join(models.SongFields, ", ")))
+		}
+	}
+	return nil
+}
+
+// songIncludes проверяет параметр include и убирает повторы
+func songIncludes(include []string) ([]string, error) {
+	known := []string{models.IncludeArtist, models.IncludeAlbums, models.IncludeTags, models.IncludeGenres}
+	result := make([]string, 0, len(include))
+	for _, name := range include {
+		if !slices.Contains(known, name) {
+			return nil, apperror.NewValidationError("include", fmt.Sprintf("unknown relation %q, expected one of: %s", name, strings.Join(known, ", ")))
+		}
+		if !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+func (s *SongService) GetSongs(ctx context.Context, filter models.SongFilter) (models.SongPage, error) {
+	if filter.Page < 1 {
+		return models.SongPage{}, apperror.NewValidationError("page", "must be a positive integer")
+	}
+	if err := validateSongFilter(&filter); err != nil {
+		return models.SongPage{}, err
+	}
+
+	songs, total, err := s.repo.GetSongs(ctx, filter)
+	if err != nil {
+		return models.SongPage{}, err
+	}
+
+	return models.SongPage{
+		Items:      songs,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
 }
 
-func NewSongService(repo repository.Song) *SongService {
-	return &SongService{repo: repo}
+func (s *SongService) GetSongsByCursor(ctx context.Context, filter models.SongFilter, cursor string) (models.SongCursorPage, error) {
+	if err := validateSongFilter(&filter); err != nil {
+		return models.SongCursorPage{}, err
+	}
+
+	songs, nextCursor, err := s.repo.GetSongsByCursor(ctx, filter, cursor)
+	if err != nil {
+		return models.SongCursorPage{}, err
+	}
+
+	return models.SongCursorPage{
+		Items:      songs,
+		Limit:      filter.Limit,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func validateSongFilter(filter *models.SongFilter) error {
+	if filter.Limit < 1 || filter.Limit > maxPageSize {
+		return apperror.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxPageSize))
+	}
+	return normalizeSongFilter(filter)
+}
+
+// normalizeSongFilter проверяет и приводит к каноническому виду условия выборки, кроме пагинации
+func normalizeSongFilter(filter *models.SongFilter) error {
+	if err := validateSongFields(filter.Fields); err != nil {
+		return err
+	}
+
+	// Жанры и теги сравниваются без учёта регистра; по умолчанию достаточно совпадения с любым из них
+	var err error
+	if filter.GenreMode, err = matchMode("genreMode", filter.GenreMode); err != nil {
+		return err
+	}
+	if filter.TagMode, err = matchMode("tagMode", filter.TagMode); err != nil {
+		return err
+	}
+	filter.Genres = normalizeNames(filter.Genres)
+	filter.Tags = normalizeNames(filter.Tags)
+
+	// Границы диапазона принимаются в любом поддерживаемом формате и с любой точностью:
+	// releaseDateFrom=2006 означает «с 1 января 2006», releaseDateTo=2006 — «по 31 декабря 2006»
+	if filter.ReleaseDateFrom != "" {
+		from, err := models.ParseReleaseDate(filter.ReleaseDateFrom)
+		if err != nil {
+			return apperror.NewValidationError("releaseDateFrom", err.Error())
+		}
+		filter.ReleaseDateFrom = from.Time.Format(time.DateOnly)
+	}
+	if filter.ReleaseDateTo != "" {
+		to, err := models.ParseReleaseDate(filter.ReleaseDateTo)
+		if err != nil {
+			return apperror.NewValidationError("releaseDateTo", err.Error())
+		}
+		filter.ReleaseDateTo = to.End().Format(time.DateOnly)
+	}
+	return nil
+}
+
+func matchMode(field, mode string) (string, error) {
+	switch strings.ToLower(mode) {
+	case "", models.MatchAny:
+		return models.MatchAny, nil
+	case models.MatchAll:
+		return models.MatchAll, nil
+	}
+	return "", apperror.NewValidationError(field, "must be one of: and, or")
+}
+
+// normalizeNames приводит названия к нижнему регистру, схлопывает пробелы и убирает пустые и повторяющиеся
+func normalizeNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
 }
 
-func (s *SongService) AddSong(list models.Song) error {
-	return s.repo.AddSong(list)
+// GetSongFacets считает песни выборки по жанрам, тегам и десятилетиям
+func (s *SongService) GetSongFacets(ctx context.Context, filter models.SongFilter) (models.SongFacets, error) {
+	if err := validateSongFilter(&filter); err != nil {
+		return models.SongFacets{}, err
+	}
+	return s.repo.GetSongFacets(ctx, filter)
 }
 
-func (s *SongService) GetSongs(filter string, page int, limit int) ([]models.Song, error) {
-	return s.repo.GetSongs(filter, page, limit)
+func (s *SongService) SearchSongs(ctx context.Context, search models.SongSearch) ([]models.SongSearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return nil, apperror.NewValidationError("q", "must not be blank")
+	}
+	switch search.Language {
+	case models.SearchLanguageAll, models.SearchLanguageRussian, models.SearchLanguageEnglish:
+	default:
+		return nil, apperror.NewValidationError("lang", "must be one of: ru, en")
+	}
+	if search.Page < 1 {
+		return nil, apperror.NewValidationError("page", "must be a positive integer")
+	}
+	if search.Limit < 1 || search.Limit > maxPageSize {
+		return nil, apperror.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxPageSize))
+	}
+	return s.repo.SearchSongs(ctx, search)
 }
-func (s *SongService) GetSongText(id int) (string, error) {
-	return s.repo.GetSongText(id)
+
+// GetSongLyrics возвращает страницу секций текста песни на выбранном языке, при необходимости
+// в транслитерации. Для записей, сохранённых до появления секций, текст разбирается на лету.
+func (s *SongService) GetSongLyrics(ctx context.Context, id int, query models.LyricsQuery) (models.SongLyrics, error) {
+	if query.Page < 1 {
+		return models.SongLyrics{}, apperror.NewValidationError("page", "must be a positive integer")
+	}
+	if query.Limit < 1 || query.Limit > maxPageSize {
+		return models.SongLyrics{}, apperror.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxPageSize))
+	}
+	if query.Translit != "" && !lyrics.ValidTranslit(query.Translit) {
+		return models.SongLyrics{}, apperror.NewValidationError("translit", "must be one of: iso9, gost")
+	}
+
+	sections, err := s.repo.GetLyricSections(ctx, id)
+	if err != nil {
+		return models.SongLyrics{}, err
+	}
+	if len(sections) == 0 {
+		text, err := s.repo.GetSongText(ctx, id)
+		if err != nil {
+			return models.SongLyrics{}, err
+		}
+		sections = lyrics.Parse(text)
+	}
+
+	sections, lang, err := s.translatedSections(ctx, id, sections, query)
+	if err != nil {
+		return models.SongLyrics{}, err
+	}
+
+	start := min((query.Page-1)*query.Limit, len(sections))
+	end := min(start+query.Limit, len(sections))
+	page := sections[start:end]
+	if query.Translit != "" {
+		page = transliterateSections(page, query.Translit)
+	}
+
+	return models.SongLyrics{
+		SongID:     id,
+		Language:   lang,
+		Translit:   query.Translit,
+		Sections:   page,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      len(sections),
+		TotalPages: (len(sections) + query.Limit - 1) / query.Limit,
+	}, nil
 }
-func (s *SongService) UpdateSong(id int, song models.Song) error {
-	return s.repo.UpdateSong(id, song)
+
+// UpdateSong заменяет песню целиком, если её версия удовлетворяет precondition (If-Match),
+// и возвращает новую версию. Не переданные необязательные поля очищаются.
+func (s *SongService) UpdateSong(ctx context.Context, id int, song models.Song, precondition models.Precondition) (int, error) {
+	song.GroupName = artistName(song.GroupName)
+	song.SongName = strings.TrimSpace(song.SongName)
+	if song.GroupName == "" {
+		return 0, apperror.NewValidationError("group", "must not be blank")
+	}
+	if song.SongName == "" {
+		return 0, apperror.NewValidationError("song", "must not be blank")
+	}
+	if song.Duration < 0 {
+		return 0, apperror.NewValidationError("duration", "must not be negative")
+	}
+
+	changes := models.SongChanges{
+		"group":       song.GroupName,
+		"song":        song.SongName,
+		"releaseDate": nil,
+		"text":        optionalString(song.Text),
+		"lyrics":      optionalString(song.Lyrics),
+		"link":        optionalString(song.Link),
+		"duration":    optionalDuration(song.Duration),
+	}
+	if !song.ReleaseDate.IsZero() {
+		changes["releaseDate"] = song.ReleaseDate
+	}
+	return s.repo.UpdateSong(ctx, id, changes, precondition)
 }
-func (s *SongService) DeleteSong(id int) error {
-	return s.repo.DeleteSong(id)
+
+// DeleteSong переносит песню в корзину; с hard песня удаляется окончательно
+func (s *SongService) DeleteSong(ctx context.Context, id int, hard bool, precondition models.Precondition) error {
+	if hard {
+		return s.repo.PurgeSong(ctx, id, precondition)
+	}
+	return s.repo.DeleteSong(ctx, id, precondition)
+}
+
+func (s *SongService) RestoreSong(ctx context.Context, id int) error {
+	return s.repo.RestoreSong(ctx, id)
 }
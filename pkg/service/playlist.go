@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/playlistfile"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+// maxPlaylistImportEntries — максимальное число записей в импортируемом файле плейлиста
+const maxPlaylistImportEntries = 10000
+
+type PlaylistService struct {
+	repo repository.Playlist
+}
+
+func NewPlaylistService(repo repository.Playlist) *PlaylistService {
+	return &PlaylistService{repo: repo}
+}
+
+// playlistName проверяет название плейлиста: не пустое и помещается в колонку
+func playlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", apperror.NewValidationError("name", "must not be blank")
+	}
+	if utf8.RuneCountInString(name) > songColumnLimit {
+		return "", apperror.NewValidationError("name", fmt.Sprintf("must not exceed %d characters", songColumnLimit))
+	}
+	return name, nil
+}
+
+func (s *PlaylistService) AddPlaylist(ctx context.Context, name string) (int, error) {
+	name, err := playlistName(name)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.AddPlaylist(ctx, name)
+}
+
+func (s *PlaylistService) GetPlaylists(ctx context.Context, filter models.PlaylistFilter) (models.PlaylistPage, error) {
+	if filter.Page < 1 {
+		return models.PlaylistPage{}, apperror.NewValidationError("page", "must be a positive integer")
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize {
+		return models.PlaylistPage{}, apperror.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxPageSize))
+	}
+
+	playlists, total, err := s.repo.GetPlaylists(ctx, filter)
+	if err != nil {
+		return models.PlaylistPage{}, err
+	}
+
+	return models.PlaylistPage{
+		Items:      playlists,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+func (s *PlaylistService) GetPlaylist(ctx context.Context, id int) (models.Playlist, error) {
+	return s.repo.GetPlaylist(ctx, id)
+}
+
+func (s *PlaylistService) RenamePlaylist(ctx context.Context, id int, name string) error {
+	name, err := playlistName(name)
+	if err != nil {
+		return err
+	}
+	return s.repo.RenamePlaylist(ctx, id, name)
+}
+
+func (s *PlaylistService) DeletePlaylist(ctx context.Context, id int) error {
+	return s.repo.DeletePlaylist(ctx, id)
+}
+
+// AddPlaylistEntry добавляет песню в плейлист и возвращает id и позицию новой записи.
+// Позиция 0 — в конец; верхняя граница проверяется в репозитории под блокировкой плейлиста.
+func (s *PlaylistService) AddPlaylistEntry(ctx context.Context, playlistID int, entry models.NewPlaylistEntry) (int, int, error) {
+	if entry.SongID < 1 {
+		return 0, 0, apperror.NewValidationError("songId", "must be a positive integer")
+	}
+	if entry.Position < 0 {
+		return 0, 0, apperror.NewValidationError("position", "must not be negative")
+	}
+	return s.repo.AddPlaylistEntry(ctx, playlistID, entry)
+}
+
+func (s *PlaylistService) MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) error {
+	if position < 1 {
+		return apperror.NewValidationError("position", "must be a positive integer")
+	}
+	return s.repo.MovePlaylistEntry(ctx, playlistID, entryID, position)
+}
+
+func (s *PlaylistService) RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error {
+	return s.repo.RemovePlaylistEntry(ctx, playlistID, entryID)
+}
+
+// ExportPlaylist записывает плейлист в w в формате m3u8, xspf или pls и возвращает число записанных песен.
+// Песни из корзины в файл не попадают; в M3U8 и PLS пропускаются и песни без ссылки.
+func (s *PlaylistService) ExportPlaylist(ctx context.Context, id int, format string, w io.Writer) (int, error) {
+	playlist, err := s.repo.GetPlaylist(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	writer, err := playlistfile.NewWriter(format, w, playlist.Name)
+	if err != nil {
+		return 0, err
+	}
+	written := 0
+	for _, entry := range playlist.Entries {
+		if entry.Song.DeletedAt != nil {
+			continue
+		}
+		if err := writer.Write(entry.Song); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, writer.Close()
+}
+
+// ImportPlaylist создаёт плейлист из файла M3U8, XSPF или PLS. Записи сопоставляются с песнями каталога
+// по исполнителю и названию, а если их нет в файле или песня не нашлась — по ссылке.
+// Несопоставленные записи не добавляются и перечисляются в отчёте с номерами строк файла.
+func (s *PlaylistService) ImportPlaylist(ctx context.Context, r io.Reader, options models.PlaylistImportOptions) (models.PlaylistImportReport, error) {
+	title, items, err := playlistfile.Read(options.Format, r)
+	if err != nil {
+		return models.PlaylistImportReport{}, err
+	}
+	if len(items) > maxPlaylistImportEntries {
+		return models.PlaylistImportReport{}, apperror.NewValidationError("file", fmt.Sprintf("playlist must not exceed %d entries", maxPlaylistImportEntries))
+	}
+
+	name := options.Name
+	if strings.TrimSpace(name) == "" {
+		name = title
+	}
+	if strings.TrimSpace(name) == "" && options.Filename != "" {
+		name = strings.TrimSuffix(filepath.Base(options.Filename), filepath.Ext(options.Filename))
+	}
+	if name, err = playlistName(name); err != nil {
+		return models.PlaylistImportReport{}, err
+	}
+
+	id, songIDs, err := s.repo.ImportPlaylist(ctx, name, items)
+	if err != nil {
+		return models.PlaylistImportReport{}, err
+	}
+
+	report := models.PlaylistImportReport{
+		PlaylistID: id,
+		Name:       name,
+		Total:      len(items),
+		Unmatched:  make([]models.PlaylistImportMiss, 0),
+	}
+	for i, item := range items {
+		if songIDs[i] != 0 {
+			report.Matched++
+			continue
+		}
+		report.Unmatched = append(report.Unmatched, models.PlaylistImportMiss{Line: item.Line, Text: playlistItemText(item)})
+	}
+	return report, nil
+}
+
+// playlistItemText — запись файла для отчёта: «Исполнитель - Название», название или ссылка
+func playlistItemText(item models.PlaylistItem) string {
+	switch {
+	case item.Group != "" && item.Song != "":
+		return item.Group + " - " + item.Song
+	case item.Song != "":
+		return item.Song
+	}
+	return item.Location
+}
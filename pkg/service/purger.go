@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+type PurgeConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+// Purger — фоновая очистка корзины: песни, пролежавшие в ней дольше Retention,
+// удаляются окончательно пачками по BatchSize раз в Interval.
+type Purger struct {
+	repo repository.Song
+	cfg  PurgeConfig
+
+	stop   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewPurger(repo repository.Song, cfg PurgeConfig) *Purger {
+	if cfg.Retention <= 0 {
+		cfg.Retention = 30 * 24 * time.Hour
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Purger{
+		repo:   repo,
+		cfg:    cfg,
+		stop:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (p *Purger) Start() {
+	logrus.WithFields(logrus.Fields{
+		"retention": p.cfg.Retention.String(),
+		"interval":  p.cfg.Interval.String(),
+	}).Info("Starting trash purger...")
+
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Shutdown останавливает очистку; начатая пачка прерывается, если ctx истекает раньше
+func (p *Purger) Shutdown(ctx context.Context) error {
+	p.once.Do(func() { close(p.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		logrus.Info("Trash purger stopped")
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (p *Purger) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge()
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge удаляет пачки, пока они заполняются целиком
+func (p *Purger) purge() {
+	before := time.Now().Add(-p.cfg.Retention)
+
+	total := 0
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		purged, err := p.repo.PurgeDeletedSongs(p.ctx, before, p.cfg.BatchSize)
+		if err != nil {
+			logrus.Errorf("Failed to purge trash: %v", err)
+			return
+		}
+		total += purged
+		if purged < p.cfg.BatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		logrus.WithFields(logrus.Fields{
+			"purged": total,
+			"before": before.Format(time.RFC3339),
+		}).Info("Trashed songs purged")
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+)
+
+func (s *SongService) GetRevisions(ctx context.Context, filter models.RevisionFilter) (models.RevisionPage, error) {
+	if filter.Page < 1 {
+		return models.RevisionPage{}, apperror.NewValidationError("page", "must be a positive integer")
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize {
+		return models.RevisionPage{}, apperror.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxPageSize))
+	}
+
+	revisions, total, err := s.repo.GetRevisions(ctx, filter)
+	if err != nil {
+		return models.RevisionPage{}, err
+	}
+
+	return models.RevisionPage{
+		Items:      revisions,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+func (s *SongService) GetRevision(ctx context.Context, id, revision int) (models.SongRevision, error) {
+	return s.repo.GetRevision(ctx, id, revision)
+}
+
+// DiffRevisions сравнивает снимки двух ревизий; текст и lyrics дополнительно сравниваются построчно
+func (s *SongService) DiffRevisions(ctx context.Context, id, from, to int) (models.RevisionDiff, error) {
+	if from < 1 {
+		return models.RevisionDiff{}, apperror.NewValidationError("from", "must be a positive integer")
+	}
+	if to < 1 {
+		return models.RevisionDiff{}, apperror.NewValidationError("to", "must be a positive integer")
+	}
+
+	older, err := s.repo.GetRevision(ctx, id, from)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+	newer, err := s.repo.GetRevision(ctx, id, to)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+
+	diff := models.RevisionDiff{
+		SongID:  id,
+		From:    from,
+		To:      to,
+		Changes: models.DiffSnapshots(older.Snapshot, newer.Snapshot),
+	}
+	if _, ok := diff.Changes["text"]; ok {
+		diff.Text = lyrics.DiffLines(lyrics.Lines(older.Snapshot.Text), lyrics.Lines(newer.Snapshot.Text))
+	}
+	if _, ok := diff.Changes["lyrics"]; ok {
+		diff.Lyrics = lyrics.DiffLines(lyrics.Lines(older.Snapshot.Lyrics), lyrics.Lines(newer.Snapshot.Lyrics))
+	}
+	return diff, nil
+}
+
+func (s *SongService) RestoreRevision(ctx context.Context, id, revision int) error {
+	return s.repo.RestoreRevision(ctx, id, revision)
+}
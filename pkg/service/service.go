@@ -1,30 +1,147 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"io"
+
 	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+	"github.com/skorpsrgvch/music-lib/pkg/ratelimit"
 	"github.com/skorpsrgvch/music-lib/pkg/repository"
 )
 
+var (
+	ErrSongDetailNotFound    = fmt.Errorf("song detail: %w", apperror.ErrNotFound)
+	ErrSongDetailUnavailable = fmt.Errorf("song detail service: %w", apperror.ErrUpstreamUnavailable)
+)
+
 type SongDetail struct {
 	ReleaseDate string `json:"releaseDate" example:"16.07.2006"`
 	Text        string `json:"text" example:"Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\nYou caught me under false pretenses\nHow long before you let me go?\n\nOoh\nYou set my soul alight\nOoh\nYou set my soul alight"`
 	Link        string `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
 }
 
+// SongDetailProvider — источник дополнительной информации о песне (внешний API)
+type SongDetailProvider interface {
+	GetSongDetail(ctx context.Context, group, song string) (SongDetail, error)
+}
+
 type Song interface {
-	AddSong(list models.Song) error
-	GetSongs(filter string, page int, limit int) ([]models.Song, error)
-	GetSongText(id int) (string, error)
-	UpdateSong(id int, song models.Song) error
-	DeleteSong(id int) error
+	AddSong(ctx context.Context, list models.Song) (int, error)
+	ImportSongs(ctx context.Context, body io.Reader, options models.ImportOptions) (models.ImportReport, error)
+	GetSong(ctx context.Context, id int, query models.SongQuery) (models.SongDetails, error)
+	GetSongs(ctx context.Context, filter models.SongFilter) (models.SongPage, error)
+	GetSongsByCursor(ctx context.Context, filter models.SongFilter, cursor string) (models.SongCursorPage, error)
+	ExportSongs(ctx context.Context, filter models.SongFilter, format string, w io.Writer) (int, error)
+	GetSongLyrics(ctx context.Context, id int, query models.LyricsQuery) (models.SongLyrics, error)
+	SetSyncedLyrics(ctx context.Context, id int, lrc string) (lyrics.SyncedLyrics, error)
+	GetSyncedLyrics(ctx context.Context, id int) (lyrics.SyncedLyrics, error)
+	DeleteSyncedLyrics(ctx context.Context, id int) error
+	SetTranslation(ctx context.Context, id int, translation models.Translation) error
+	GetTranslations(ctx context.Context, id int) ([]models.Translation, error)
+	DeleteTranslation(ctx context.Context, id int, lang string) error
+	SearchSongs(ctx context.Context, search models.SongSearch) ([]models.SongSearchResult, error)
+	GetSongFacets(ctx context.Context, filter models.SongFilter) (models.SongFacets, error)
+	UpdateSong(ctx context.Context, id int, song models.Song, precondition models.Precondition) (int, error)
+	PatchSong(ctx context.Context, id int, patch models.SongPatch, precondition models.Precondition) (int, error)
+	DeleteSong(ctx context.Context, id int, hard bool, precondition models.Precondition) error
+	RestoreSong(ctx context.Context, id int) error
+	GetRevisions(ctx context.Context, filter models.RevisionFilter) (models.RevisionPage, error)
+	GetRevision(ctx context.Context, id, revision int) (models.SongRevision, error)
+	DiffRevisions(ctx context.Context, id, from, to int) (models.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, id, revision int) error
+}
+
+type Artist interface {
+	AddArtist(ctx context.Context, name string) (int, error)
+	GetArtists(ctx context.Context, filter models.ArtistFilter) (models.ArtistPage, error)
+	GetArtist(ctx context.Context, id int) (models.Artist, error)
+	UpdateArtist(ctx context.Context, id int, name string) error
+	DeleteArtist(ctx context.Context, id int) error
+}
+
+type Album interface {
+	AddAlbum(ctx context.Context, album models.Album) (int, error)
+	GetAlbums(ctx context.Context, filter models.AlbumFilter) (models.AlbumPage, error)
+	GetAlbum(ctx context.Context, id int) (models.Album, error)
+	UpdateAlbum(ctx context.Context, id int, album models.Album) error
+	SetAlbumTracks(ctx context.Context, id int, tracks []models.TrackPosition) error
+	DeleteAlbum(ctx context.Context, id int) error
+}
+
+type Genre interface {
+	AddGenre(ctx context.Context, genre models.Genre) (int, error)
+	GetGenres(ctx context.Context) ([]models.Genre, error)
+	DeleteGenre(ctx context.Context, id int) error
+	AttachGenre(ctx context.Context, songID, genreID int) error
+	DetachGenre(ctx context.Context, songID, genreID int) error
+}
+
+type Tag interface {
+	GetTags(ctx context.Context) ([]models.Tag, error)
+	AttachTag(ctx context.Context, songID int, name string) error
+	DetachTag(ctx context.Context, songID int, name string) error
+}
+
+type Playlist interface {
+	AddPlaylist(ctx context.Context, name string) (int, error)
+	GetPlaylists(ctx context.Context, filter models.PlaylistFilter) (models.PlaylistPage, error)
+	GetPlaylist(ctx context.Context, id int) (models.Playlist, error)
+	RenamePlaylist(ctx context.Context, id int, name string) error
+	DeletePlaylist(ctx context.Context, id int) error
+	AddPlaylistEntry(ctx context.Context, playlistID int, entry models.NewPlaylistEntry) (int, int, error)
+	MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) error
+	RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error
+	ExportPlaylist(ctx context.Context, id int, format string, w io.Writer) (int, error)
+	ImportPlaylist(ctx context.Context, r io.Reader, options models.PlaylistImportOptions) (models.PlaylistImportReport, error)
+}
+
+type Authorization interface {
+	Login(ctx context.Context, credentials models.Credentials) (models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error)
+	ParseAccessToken(token string) (models.Principal, error)
+	CreateUser(ctx context.Context, user models.NewUser) (int, error)
+	GetUsers(ctx context.Context) ([]models.User, error)
+	SetUserRole(ctx context.Context, id int, role string) error
+	EnsureAdmin(ctx context.Context, username, password string) error
+}
+
+type APIKey interface {
+	CreateAPIKey(ctx context.Context, key models.NewAPIKey, createdBy int) (models.IssuedAPIKey, error)
+	GetAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	SetAPIKeyLimits(ctx context.Context, id int, limits models.APIKeyLimits) error
+	AuthenticateAPIKey(ctx context.Context, raw string) (models.Principal, ratelimit.Result, error)
+}
+
+type Info interface {
+	GetInfo(ctx context.Context, group, song string) (SongDetail, error)
 }
 
 type Service struct {
 	Song
+	Artist
+	Album
+	Genre
+	Tag
+	Playlist
+	Authorization
+	APIKey
+	Info
 }
 
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, details SongDetailProvider, enricher *Enricher, auth AuthConfig, apiKeys APIKeyConfig) *Service {
 	return &Service{
-		Song: NewSongService(repos.Song),
+		Song:          NewSongService(repos.Song, enricher),
+		Artist:        NewArtistService(repos.Artist),
+		Album:         NewAlbumService(repos.Album),
+		Genre:         NewGenreService(repos.Genre),
+		Tag:           NewTagService(repos.Tag),
+		Playlist:      NewPlaylistService(repos.Playlist),
+		Authorization: NewAuthService(repos.User, auth),
+		APIKey:        NewAPIKeyService(repos.APIKey, apiKeys),
+		Info:          NewInfoService(details),
 	}
 }
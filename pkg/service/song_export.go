@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"io"
+	"slices"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/songexport"
+)
+
+// exportFlushEvery — через сколько песен выгрузка отправляет клиенту накопленные данные
+const exportFlushEvery = 500
+
+// ExportSongs записывает в w все песни выборки filter в формате format (csv, tsv, ndjson, ods)
+// и возвращает их количество. Пагинация не применяется; fields задаёт колонки, id выгружается всегда.
+// До первой порции песен в w ничего не пишется, поэтому ошибки проверки и запроса можно вернуть обычным ответом.
+func (s *SongService) ExportSongs(ctx context.Context, filter models.SongFilter, format string, w io.Writer) (int, error) {
+	if err := normalizeSongFilter(&filter); err != nil {
+		return 0, err
+	}
+	fields := filter.Fields
+	if len(fields) > 0 && !slices.Contains(fields, "id") {
+		fields = append([]string{"id"}, fields...)
+	}
+
+	writer, err := songexport.NewWriter(format, w, fields)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	count, err := s.repo.ExportSongs(ctx, filter, func(song models.Song) error {
+		if err := writer.Write(song); err != nil {
+			return err
+		}
+		// Сбрасываем буферы порциями, чтобы клиент получал файл по частям, а не в конце
+		written++
+		if written%exportFlushEvery == 0 {
+			return writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
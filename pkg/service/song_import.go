@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/songimport"
+)
+
+const (
+	defaultImportBatchSize = 500
+	maxImportBatchSize     = 1000
+)
+
+// songColumnLimit — длина строковых колонок songs (VARCHAR(255)); text не ограничен
+const songColumnLimit = 255
+
+// ImportSongs читает песни из body и сохраняет их пачками по options.BatchSize, каждую в своей транзакции.
+// Ошибочные записи попадают в отчёт; с OnErrorAbort импорт останавливается на первой из них,
+// и пачка с ошибкой не сохраняется, с OnErrorSkip такие записи пропускаются. Если импорт прервала ошибка
+// чтения или базы, вместе с ней возвращается отчёт с Aborted: по нему видно, что уже сохранено.
+func (s *SongService) ImportSongs(ctx context.Context, body io.Reader, options models.ImportOptions) (models.ImportReport, error) {
+	switch options.OnError {
+	case "":
+		options.OnError = models.OnErrorAbort
+	case models.OnErrorAbort, models.OnErrorSkip:
+	default:
+		return models.ImportReport{}, apperror.NewValidationError("on_error", "must be one of: abort, skip")
+	}
+	if options.BatchSize == 0 {
+		options.BatchSize = defaultImportBatchSize
+	}
+	if options.BatchSize < 1 || options.BatchSize > maxImportBatchSize {
+		return models.ImportReport{}, apperror.NewValidationError("batch_size", fmt.Sprintf("must be between 1 and %d", maxImportBatchSize))
+	}
+
+	reader, err := songimport.NewReader(options.Format, body)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+
+	report := models.ImportReport{DryRun: options.DryRun, Errors: make([]models.ImportRowError, 0)}
+	batch := make([]models.Song, 0, options.BatchSize)
+	lastRow := 0 // последняя строка в пачке
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !options.DryRun {
+			if err := s.importBatch(ctx, batch); err != nil {
+				return fmt.Errorf("import stopped after %d songs: %w", report.Imported, err)
+			}
+			report.SavedThrough = lastRow
+		}
+		report.Imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		song, row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		var validationErr *apperror.ValidationError
+		if err != nil && !errors.As(err, &validationErr) {
+			if !errors.Is(err, songimport.ErrMalformed) {
+				report.Aborted = true
+				return report, err
+			}
+			// Повреждённый файл дальше не читается: это всегда конец импорта
+			report.Errors = append(report.Errors, models.ImportRowError{Row: row, Errors: map[string]string{"file": err.Error()}})
+			report.Aborted = true
+			break
+		}
+
+		report.Total++
+		if err == nil {
+			validationErr = s.prepareImportedSong(&song)
+		}
+		if validationErr != nil {
+			report.Errors = append(report.Errors, models.ImportRowError{Row: row, Errors: validationErr.Fields})
+			if options.OnError == models.OnErrorAbort {
+				report.Aborted = true
+				break
+			}
+			report.Skipped++
+			continue
+		}
+
+		batch = append(batch, song)
+		lastRow = row
+		if len(batch) == options.BatchSize {
+			if err := flush(); err != nil {
+				report.Aborted = true
+				return report, err
+			}
+		}
+	}
+
+	// С abort пачка, в которой нашлась ошибка, не сохраняется
+	if !report.Aborted || options.OnError == models.OnErrorSkip {
+		if err := flush(); err != nil {
+			report.Aborted = true
+			return report, err
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"format":   options.Format,
+		"dry_run":  options.DryRun,
+		"total":    report.Total,
+		"imported": report.Imported,
+		"skipped":  report.Skipped,
+		"aborted":  report.Aborted,
+	}).Info("Song import finished")
+	return report, nil
+}
+
+// prepareImportedSong нормализует и проверяет песню так же, как AddSong, и дополнительно
+// проверяет длину полей, чтобы ошибка в одной записи не срывала вставку всей пачки
+func (s *SongService) prepareImportedSong(song *models.Song) *apperror.ValidationError {
+	song.GroupName = artistName(song.GroupName)
+	song.SongName = strings.TrimSpace(song.SongName)
+
+	validationErr := &apperror.ValidationError{}
+	if song.GroupName == "" {
+		validationErr.Add("group", "must not be blank")
+	}
+	if song.SongName == "" {
+		validationErr.Add("song", "must not be blank")
+	}
+	if song.Duration < 0 {
+		validationErr.Add("duration", "must not be negative")
+	}
+	for field, value := range map[string]string{"group": song.GroupName, "song": song.SongName, "lyrics": song.Lyrics, "link": song.Link} {
+		if utf8.RuneCountInString(value) > songColumnLimit {
+			validationErr.Add(field, fmt.Sprintf("must not exceed %d characters", songColumnLimit))
+		}
+	}
+	if len(validationErr.Fields) > 0 {
+		return validationErr
+	}
+
+	song.EnrichmentStatus = models.EnrichmentDone
+	if s.needsEnrichment(*song) {
+		song.EnrichmentStatus = models.EnrichmentPending
+	}
+	return nil
+}
+
+// importBatch сохраняет пачку и ставит песни без деталей в очередь дополнения. Когда очередь заполнится,
+// остальные песни останутся в статусе pending и будут подобраны воркерами позже.
+func (s *SongService) importBatch(ctx context.Context, batch []models.Song) error {
+	ids, err := s.repo.ImportSongs(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	if s.enricher == nil {
+		return nil
+	}
+	for i, song := range batch {
+		if song.EnrichmentStatus != models.EnrichmentPending {
+			continue
+		}
+		if !s.enricher.Enqueue(models.EnrichmentJob{SongID: ids[i], GroupName: song.GroupName, SongName: song.SongName}) {
+			break
+		}
+	}
+	return nil
+}
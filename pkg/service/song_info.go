@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+type InfoService struct {
+	details SongDetailProvider
+}
+
+func NewInfoService(details SongDetailProvider) *InfoService {
+	return &InfoService{details: details}
+}
+
+func (s *InfoService) GetInfo(ctx context.Context, group, song string) (SongDetail, error) {
+	if s.details == nil {
+		return SongDetail{}, fmt.Errorf("no song detail provider configured: %w", ErrSongDetailUnavailable)
+	}
+	return s.details.GetSongDetail(ctx, group, song)
+}
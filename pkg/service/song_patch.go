@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/patch"
+)
+
+// editableSongFields — поля песни, которые можно менять патчем; остальные поля документа только для чтения
+var editableSongFields = map[string]bool{
+	"group":       true,
+	"song":        true,
+	"releaseDate": true,
+	"text":        true,
+	"lyrics":      true,
+	"link":        true,
+	"duration":    true,
+}
+
+// PatchSong применяет к песне merge patch или JSON Patch и возвращает новую версию.
+// Патч применяется к JSON-представлению песни; null или удалённое поле очищает колонку.
+func (s *SongService) PatchSong(ctx context.Context, id int, p models.SongPatch, precondition models.Precondition) (int, error) {
+	song, err := s.repo.GetSong(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	// If-Match проверяем до применения: test-операции должны видеть ту версию, которую ждёт клиент
+	if !precondition.Matches(song.Version) {
+		return 0, fmt.Errorf("song with id %d was modified (current version %d): %w", id, song.Version, apperror.ErrPreconditionFailed)
+	}
+
+	original, err := songDocument(song)
+	if err != nil {
+		return 0, err
+	}
+	patched, err := applySongPatch(original, p)
+	if err != nil {
+		return 0, err
+	}
+
+	changes, err := songPatchChanges(song, original, patched)
+	if err != nil {
+		return 0, err
+	}
+	if len(changes) == 0 {
+		return song.Version, nil
+	}
+
+	// Версия, к которой применялся патч: если песню успели изменить, получим 412
+	return s.repo.UpdateSong(ctx, id, changes, models.Precondition{Versions: []int{song.Version}})
+}
+
+// songDocument — песня в виде JSON-документа, к которому применяется патч
+func songDocument(song models.Song) (map[string]interface{}, error) {
+	data, err := json.Marshal(song)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+func applySongPatch(document interface{}, p models.SongPatch) (interface{}, error) {
+	switch p.Format {
+	case models.PatchMerge:
+		mergePatch, err := patch.Decode(p.Document)
+		if err != nil {
+			return nil, patchError(err)
+		}
+		return patch.MergePatch(document, mergePatch), nil
+	case models.PatchJSON:
+		ops, err := patch.DecodeJSONPatch(p.Document)
+		if err != nil {
+			return nil, patchError(err)
+		}
+		patched, err := patch.ApplyJSONPatch(document, ops)
+		if err != nil {
+			return nil, patchError(err)
+		}
+		return patched, nil
+	default:
+		return nil, apperror.NewValidationError("body", fmt.Sprintf("unsupported patch format %q", p.Format))
+	}
+}
+
+// patchError переводит ошибки патча в доменные: не прошедшая test-операция — конфликт, остальное — ошибка валидации
+func patchError(err error) error {
+	if errors.Is(err, patch.ErrTestFailed) {
+		return fmt.Errorf("%v: %w", err, apperror.ErrConflict)
+	}
+	return apperror.NewValidationError("body", err.Error())
+}
+
+// songPatchChanges сравнивает документ после патча с исходным и возвращает изменённые поля песни
+func songPatchChanges(song models.Song, original map[string]interface{}, patched interface{}) (models.SongChanges, error) {
+	document, ok := patched.(map[string]interface{})
+	if !ok {
+		return nil, apperror.NewValidationError("body", "patch must produce a JSON object")
+	}
+
+	validationErr := &apperror.ValidationError{}
+	for field, value := range document {
+		if editableSongFields[field] {
+			continue
+		}
+		if originalValue, known := original[field]; !known {
+			validationErr.Add(field, "unknown field")
+		} else if !reflect.DeepEqual(value, originalValue) {
+			validationErr.Add(field, "read-only field")
+		}
+	}
+	for field := range original {
+		if _, kept := document[field]; !kept && !editableSongFields[field] {
+			validationErr.Add(field, "read-only field")
+		}
+	}
+
+	changes := models.SongChanges{}
+
+	// Исполнитель и название обязательны: их нельзя очистить
+	if group, ok := patchedString(document, "group"); !ok {
+		validationErr.Add("group", "must be a string")
+	} else if name := artistName(group); name == "" {
+		validationErr.Add("group", "must not be blank")
+	} else if name != song.GroupName {
+		changes["group"] = name
+	}
+	if title, ok := patchedString(document, "song"); !ok {
+		validationErr.Add("song", "must be a string")
+	} else if title = strings.TrimSpace(title); title == "" {
+		validationErr.Add("song", "must not be blank")
+	} else if title != song.SongName {
+		changes["song"] = title
+	}
+
+	if value, ok := patchedString(document, "releaseDate"); !ok {
+		validationErr.Add("releaseDate", "must be a string")
+	} else if date, err := models.ParseReleaseDate(value); err != nil {
+		validationErr.Add("releaseDate", err.Error())
+	} else if date.String() != song.ReleaseDate.String() {
+		changes["releaseDate"] = nil
+		if !date.IsZero() {
+			changes["releaseDate"] = date
+		}
+	}
+
+	// Пустая строка, null и удалённое поле одинаково очищают колонку
+	for field, current := range map[string]string{"text": song.Text, "lyrics": song.Lyrics, "link": song.Link} {
+		value, ok := patchedString(document, field)
+		if !ok {
+			validationErr.Add(field, "must be a string")
+			continue
+		}
+		if value != current {
+			changes[field] = optionalString(value)
+		}
+	}
+
+	if duration, ok := patchedDuration(document); !ok {
+		validationErr.Add("duration", "must be a non-negative integer")
+	} else if duration != song.Duration {
+		changes["duration"] = optionalDuration(duration)
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return nil, validationErr
+	}
+	return changes, nil
+}
+
+// patchedString читает строковое поле документа; отсутствующее поле и null дают пустую строку
+func patchedString(document map[string]interface{}, field string) (string, bool) {
+	switch value := document[field].(type) {
+	case nil:
+		return "", true
+	case string:
+		return value, true
+	default:
+		return "", false
+	}
+}
+
+// patchedDuration читает длительность в секундах; отсутствующее поле, null и 0 означают «неизвестна»
+func patchedDuration(document map[string]interface{}) (int, bool) {
+	switch value := document["duration"].(type) {
+	case nil:
+		return 0, true
+	case float64:
+		if value < 0 || value != math.Trunc(value) || value > math.MaxInt32 {
+			return 0, false
+		}
+		return int(value), true
+	default:
+		return 0, false
+	}
+}
+
+// optionalDuration — значение длительности для SongChanges: 0 очищает колонку
+func optionalDuration(seconds int) interface{} {
+	if seconds == 0 {
+		return nil
+	}
+	return seconds
+}
+
+// optionalString — значение необязательного поля для SongChanges: пустая строка очищает колонку
+func optionalString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+)
+
+// SetSyncedLyrics разбирает LRC-файл и сохраняет строки с метками времени.
+// Ошибки разбора возвращаются по строкам файла: поле "line N".
+func (s *SongService) SetSyncedLyrics(ctx context.Context, id int, lrc string) (lyrics.SyncedLyrics, error) {
+	synced, err := lyrics.ParseLRC(lrc)
+	if err != nil {
+		var parseErr *lyrics.ParseError
+		if !errors.As(err, &parseErr) {
+			return lyrics.SyncedLyrics{}, err
+		}
+		verr := &apperror.ValidationError{}
+		for _, lineErr := range parseErr.Lines {
+			field := fmt.Sprintf("line %d", lineErr.Line)
+			if previous, ok := verr.Fields[field]; ok {
+				verr.Add(field, previous+"; "+lineErr.Message)
+				continue
+			}
+			verr.Add(field, lineErr.Message)
+		}
+		return lyrics.SyncedLyrics{}, verr
+	}
+
+	if err := s.repo.SetSyncedLyrics(ctx, id, synced); err != nil {
+		return lyrics.SyncedLyrics{}, err
+	}
+	return synced, nil
+}
+
+func (s *SongService) GetSyncedLyrics(ctx context.Context, id int) (lyrics.SyncedLyrics, error) {
+	return s.repo.GetSyncedLyrics(ctx, id)
+}
+
+func (s *SongService) DeleteSyncedLyrics(ctx context.Context, id int) error {
+	return s.repo.DeleteSyncedLyrics(ctx, id)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/repository"
+)
+
+const maxTagLength = 100
+
+type TagService struct {
+	repo repository.Tag
+}
+
+func NewTagService(repo repository.Tag) *TagService {
+	return &TagService{repo: repo}
+}
+
+// tagName приводит тег к каноническому виду: нижний регистр, без лишних пробелов
+func tagName(name string) (string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if name == "" {
+		return "", apperror.NewValidationError("tag", "must not be blank")
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return "", apperror.NewValidationError("tag", "must be at most 100 characters")
+	}
+	return name, nil
+}
+
+func (s *TagService) GetTags(ctx context.Context) ([]models.Tag, error) {
+	return s.repo.GetTags(ctx)
+}
+
+func (s *TagService) AttachTag(ctx context.Context, songID int, name string) error {
+	name, err := tagName(name)
+	if err != nil {
+		return err
+	}
+	return s.repo.AttachTag(ctx, songID, name)
+}
+
+func (s *TagService) DetachTag(ctx context.Context, songID int, name string) error {
+	name, err := tagName(name)
+	if err != nil {
+		return err
+	}
+	return s.repo.DetachTag(ctx, songID, name)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/lyrics"
+	"golang.org/x/text/language"
+)
+
+// languageTag проверяет тег BCP 47 и приводит его к каноническому виду (en-us → en-US)
+func languageTag(field, raw string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(raw))
+	if err != nil || tag == language.Und {
+		return "", apperror.NewValidationError(field, fmt.Sprintf("invalid BCP 47 language tag %q", raw))
+	}
+	return tag.String(), nil
+}
+
+func (s *SongService) SetTranslation(ctx context.Context, id int, translation models.Translation) error {
+	tag, err := languageTag("lang", translation.Language)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(translation.Text) == "" {
+		return apperror.NewValidationError("text", "must not be blank")
+	}
+	translation.Language = tag
+	return s.repo.SetTranslation(ctx, id, translation)
+}
+
+func (s *SongService) GetTranslations(ctx context.Context, id int) ([]models.Translation, error) {
+	return s.repo.GetTranslations(ctx, id)
+}
+
+func (s *SongService) DeleteTranslation(ctx context.Context, id int, lang string) error {
+	tag, err := languageTag("lang", lang)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTranslation(ctx, id, tag)
+}
+
+// translatedSections подбирает язык текста по ?lang= или Accept-Language и возвращает секции
+// на этом языке вместе с его тегом. Оригинал участвует в подборе наравне с переводами:
+// его язык определяется по алфавиту (кириллица — ru), иначе считается неизвестным.
+func (s *SongService) translatedSections(ctx context.Context, id int, original []lyrics.Section, query models.LyricsQuery) ([]lyrics.Section, string, error) {
+	originalTag := language.Und
+	if lyrics.IsCyrillic(lyrics.Text(original)) {
+		originalTag = language.Russian
+	}
+	originalLanguage := ""
+	if originalTag != language.Und {
+		originalLanguage = originalTag.String()
+	}
+
+	if query.Lang == "" && query.AcceptLanguage == "" {
+		return original, originalLanguage, nil
+	}
+
+	translations, err := s.repo.GetTranslations(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	supported := make([]language.Tag, 0, len(translations)+1)
+	supported = append(supported, originalTag)
+	for _, translation := range translations {
+		supported = append(supported, language.Make(translation.Language))
+	}
+	matcher := language.NewMatcher(supported)
+
+	var index int
+	if query.Lang != "" {
+		tag, err := language.Parse(query.Lang)
+		if err != nil {
+			return nil, "", apperror.NewValidationError("lang", fmt.Sprintf("invalid BCP 47 language tag %q", query.Lang))
+		}
+		var confidence language.Confidence
+		_, index, confidence = matcher.Match(tag)
+		if confidence == language.No {
+			return nil, "", fmt.Errorf("translation %q of song %d: %w", tag, id, apperror.ErrNotFound)
+		}
+	} else {
+		// Некорректный Accept-Language не ошибка: просто отдаём оригинал
+		preferred, _, err := language.ParseAcceptLanguage(query.AcceptLanguage)
+		if err != nil {
+			return original, originalLanguage, nil
+		}
+		var confidence language.Confidence
+		_, index, confidence = matcher.Match(preferred...)
+		if confidence == language.No {
+			index = 0
+		}
+	}
+
+	if index == 0 {
+		return original, originalLanguage, nil
+	}
+
+	translation, err := s.repo.GetTranslation(ctx, id, translations[index-1].Language)
+	if err != nil {
+		return nil, "", err
+	}
+	return lyrics.Parse(translation.Text), translation.Language, nil
+}
+
+// transliterateSections переводит строки и подписи секций в латиницу
+func transliterateSections(sections []lyrics.Section, scheme string) []lyrics.Section {
+	result := make([]lyrics.Section, 0, len(sections))
+	for _, section := range sections {
+		lines := make([]string, 0, len(section.Lines))
+		for _, line := range section.Lines {
+			lines = append(lines, lyrics.Transliterate(line, scheme))
+		}
+		result = append(result, lyrics.Section{
+			Type:  section.Type,
+			Label: lyrics.Transliterate(section.Label, scheme),
+			Lines: lines,
+		})
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package songexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/flate"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+)
+
+const odsMimetype = "application/vnd.oasis.opendocument.spreadsheet"
+
+const odsManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">
+ <manifest:file-entry manifest:full-path="/" manifest:media-type="application/vnd.oasis.opendocument.spreadsheet" manifest:version="1.2"/>
+ <manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>
+</manifest:manifest>
+`
+
+const odsContentStart = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"` +
+	` xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"` +
+	` xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" office:version="1.2">` +
+	`<office:body><office:spreadsheet><table:table table:name="Songs">`
+
+const odsContentEnd = `</table:table></office:spreadsheet></office:body></office:document-content>`
+
+// odsWriter пишет таблицу OpenDocument. ODS — это zip-архив, поэтому content.xml сжимается
+// на лету и строки таблицы дописываются по мере поступления песен.
+type odsWriter struct {
+	zip     *zip.Writer
+	deflate *flate.Writer
+	content *bufio.Writer
+	dst     io.Writer
+	fields  []string
+}
+
+func newODSWriter(w io.Writer, fields []string) (*odsWriter, error) {
+	writer := &odsWriter{zip: zip.NewWriter(w), dst: w, fields: fields}
+	// Свой компрессор, чтобы Flush мог досжать и отправить уже записанные строки
+	writer.zip.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		var err error
+		writer.deflate, err = flate.NewWriter(out, flate.DefaultCompression)
+		return writer.deflate, err
+	})
+
+	// mimetype по спецификации идёт первым и не сжимается
+	mimetype, err := writer.zip.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(odsMimetype)),
+		CompressedSize64:   uint64(len(odsMimetype)),
+		UncompressedSize64: uint64(len(odsMimetype)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(mimetype, odsMimetype); err != nil {
+		return nil, err
+	}
+
+	manifest, err := writer.zip.Create("META-INF/manifest.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(manifest, odsManifest); err != nil {
+		return nil, err
+	}
+
+	content, err := writer.zip.Create("content.xml")
+	if err != nil {
+		return nil, err
+	}
+	writer.content = bufio.NewWriter(content)
+	writer.content.WriteString(odsContentStart)
+
+	// Заголовок таблицы — имена полей
+	header := make([]interface{}, len(fields))
+	for i, field := range fields {
+		header[i] = field
+	}
+	if err := writer.writeRow(header); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *odsWriter) Write(song models.Song) error {
+	values := make([]interface{}, len(w.fields))
+	for i, field := range w.fields {
+		values[i] = fieldValue(song, field)
+	}
+	return w.writeRow(values)
+}
+
+func (w *odsWriter) writeRow(values []interface{}) error {
+	w.content.WriteString("<table:table-row>")
+	for _, value := range values {
+		switch value := value.(type) {
+		case int:
+			number := strconv.Itoa(value)
+			w.content.WriteString(`<table:table-cell office:value-type="float" office:value="` + number + `"><text:p>` +
+				number + `</text:p></table:table-cell>`)
+		case string:
+			if value == "" {
+				w.content.WriteString("<table:table-cell/>")
+				continue
+			}
+			w.content.WriteString(`<table:table-cell office:value-type="string">`)
+			// Каждая строка многострочного текста — отдельный абзац ячейки
+			for _, line := range strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n") {
+				w.content.WriteString("<text:p>")
+				if err := xml.EscapeText(w.content, []byte(line)); err != nil {
+					return err
+				}
+				w.content.WriteString("</text:p>")
+			}
+			w.content.WriteString("</table:table-cell>")
+		default:
+			w.content.WriteString("<table:table-cell/>")
+		}
+	}
+	_, err := w.content.WriteString("</table:table-row>")
+	return err
+}
+
+func (w *odsWriter) Flush() error {
+	if err := w.content.Flush(); err != nil {
+		return err
+	}
+	if err := w.deflate.Flush(); err != nil {
+		return err
+	}
+	if err := w.zip.Flush(); err != nil {
+		return err
+	}
+	return flush(w.dst)
+}
+
+func (w *odsWriter) Close() error {
+	w.content.WriteString(odsContentEnd)
+	if err := w.content.Flush(); err != nil {
+		return err
+	}
+	if err := w.zip.Close(); err != nil {
+		return err
+	}
+	return flush(w.dst)
+}
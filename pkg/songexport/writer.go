@@ -0,0 +1,257 @@
+// Package songexport записывает песни для выгрузки каталога в CSV, TSV, NDJSON и ODS,
+// а также в форматы плейлистов M3U8, XSPF и PLS (см. playlistfile).
+// Песни пишутся по одной, поэтому выгрузка не держит каталог в памяти.
+package songexport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+	"github.com/skorpsrgvch/music-lib/pkg/playlistfile"
+)
+
+// Форматы выгрузки
+const (
+	CSV    = "csv"
+	TSV    = "tsv"
+	NDJSON = "ndjson"
+	ODS    = "ods"
+)
+
+// ContentTypes — Content-Type ответа для каждого формата
+var ContentTypes = map[string]string{
+	CSV:    "text/csv; charset=utf-8",
+	TSV:    "text/tab-separated-values; charset=utf-8",
+	NDJSON: "application/x-ndjson",
+	ODS:    "application/vnd.oasis.opendocument.spreadsheet",
+
+	models.PlaylistM3U8: playlistfile.ContentTypes[models.PlaylistM3U8],
+	models.PlaylistXSPF: playlistfile.ContentTypes[models.PlaylistXSPF],
+	models.PlaylistPLS:  playlistfile.ContentTypes[models.PlaylistPLS],
+}
+
+// DefaultFields — колонки выгрузки без параметра fields; имена совпадают с колонками импорта
+var DefaultFields = []string{"id", "artistId", "group", "song", "releaseDate", "text", "lyrics", "link",
+	"duration", "enrichmentStatus", "version"}
+
+// Writer записывает песни по одной
+type Writer interface {
+	Write(song models.Song) error
+	// Flush отправляет накопленные данные в нижележащий поток и, если он это умеет, сбрасывает и его
+	Flush() error
+	// Close дописывает окончание файла; после Close писать нельзя
+	Close() error
+}
+
+// Flusher — поток, который умеет отправлять буферизованные данные клиенту (например, HTTP-ответ)
+type Flusher interface {
+	Flush() error
+}
+
+// NewWriter создаёт Writer для формата CSV, TSV, NDJSON или ODS с колонками fields.
+// Для форматов плейлистов колонки не применяются: в файл попадают исполнитель, название, ссылка и длительность.
+func NewWriter(format string, w io.Writer, fields []string) (Writer, error) {
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+
+	switch format {
+	case CSV:
+		return newCSVWriter(w, fields)
+	case TSV:
+		return newTSVWriter(w, fields)
+	case NDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w), dst: w, fields: fields}, nil
+	case ODS:
+		return newODSWriter(w, fields)
+	case models.PlaylistM3U8, models.PlaylistXSPF, models.PlaylistPLS:
+		return playlistfile.NewWriter(format, w, "")
+	default:
+		return nil, apperror.NewValidationError("format", fmt.Sprintf("unsupported export format %q, expected csv, tsv, ndjson, ods, m3u8, xspf or pls", format))
+	}
+}
+
+// fieldValue — значение поля песни: int, string или nil для пустой даты
+func fieldValue(song models.Song, field string) interface{} {
+	switch field {
+	case "id":
+		return song.ID
+	case "artistId":
+		return song.ArtistID
+	case "group":
+		return song.GroupName
+	case "song":
+		return song.SongName
+	case "releaseDate":
+		if song.ReleaseDate.IsZero() {
+			return nil
+		}
+		return song.ReleaseDate.String()
+	case "text":
+		return song.Text
+	case "lyrics":
+		return song.Lyrics
+	case "link":
+		return song.Link
+	case "duration":
+		return song.Duration
+	case "enrichmentStatus":
+		return song.EnrichmentStatus
+	case "deletedAt":
+		if song.DeletedAt == nil {
+			return nil
+		}
+		return song.DeletedAt.UTC().Format(time.RFC3339)
+	case "version":
+		return song.Version
+	}
+	return nil
+}
+
+// fieldString — значение поля для текстовых форматов; пустая дата становится пустой строкой
+func fieldString(song models.Song, field string) string {
+	switch value := fieldValue(song, field).(type) {
+	case int:
+		return strconv.Itoa(value)
+	case string:
+		return value
+	}
+	return ""
+}
+
+// flush сбрасывает поток, если он это поддерживает
+func flush(w io.Writer) error {
+	if f, ok := w.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	dst    io.Writer
+	fields []string
+	record []string
+}
+
+// newCSVWriter пишет заголовок с именами полей, поэтому выгрузку можно загрузить обратно импортом
+func newCSVWriter(w io.Writer, fields []string) (*csvWriter, error) {
+	writer := &csvWriter{w: csv.NewWriter(w), dst: w, fields: fields, record: make([]string, len(fields))}
+	if err := writer.w.Write(fields); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *csvWriter) Write(song models.Song) error {
+	for i, field := range w.fields {
+		w.record[i] = fieldString(song, field)
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	if err := w.w.Error(); err != nil {
+		return err
+	}
+	return flush(w.dst)
+}
+
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
+
+// tsvEscaper экранирует символы, которые в TSV разделяют колонки и строки
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+type tsvWriter struct {
+	w      *bufio.Writer
+	dst    io.Writer
+	fields []string
+}
+
+func newTSVWriter(w io.Writer, fields []string) (*tsvWriter, error) {
+	writer := &tsvWriter{w: bufio.NewWriter(w), dst: w, fields: fields}
+	if err := writer.writeLine(fields); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *tsvWriter) writeLine(values []string) error {
+	for i, value := range values {
+		if i > 0 {
+			w.w.WriteByte('\t')
+		}
+		tsvEscaper.WriteString(w.w, value)
+	}
+	return w.w.WriteByte('\n')
+}
+
+func (w *tsvWriter) Write(song models.Song) error {
+	for i, field := range w.fields {
+		if i > 0 {
+			w.w.WriteByte('\t')
+		}
+		if _, err := tsvEscaper.WriteString(w.w, fieldString(song, field)); err != nil {
+			return err
+		}
+	}
+	return w.w.WriteByte('\n')
+}
+
+func (w *tsvWriter) Flush() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	return flush(w.dst)
+}
+
+func (w *tsvWriter) Close() error {
+	return w.Flush()
+}
+
+// ndjsonWriter пишет по объекту на строку; ключи идут в порядке fields
+type ndjsonWriter struct {
+	w      *bufio.Writer
+	dst    io.Writer
+	fields []string
+}
+
+func (w *ndjsonWriter) Write(song models.Song) error {
+	w.w.WriteByte('{')
+	for i, field := range w.fields {
+		if i > 0 {
+			w.w.WriteByte(',')
+		}
+		value, err := json.Marshal(fieldValue(song, field))
+		if err != nil {
+			return err
+		}
+		w.w.WriteString(strconv.Quote(field))
+		w.w.WriteByte(':')
+		w.w.Write(value)
+	}
+	w.w.WriteByte('}')
+	return w.w.WriteByte('\n')
+}
+
+func (w *ndjsonWriter) Flush() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	return flush(w.dst)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.Flush()
+}
@@ -0,0 +1,256 @@
+// Package songimport читает песни для массового импорта из CSV, NDJSON и JSON-массива.
+// Записи читаются по одной, поэтому файл не загружается в память целиком.
+package songimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// ErrMalformed — файл повреждён так, что дальше его читать нельзя
+var ErrMalformed = errors.New("malformed import file")
+
+// Reader читает песни по одной
+type Reader interface {
+	// Next возвращает следующую песню и номер её строки (для JSON-массива — номер элемента с 1).
+	// io.EOF — записи закончились; *apperror.ValidationError — ошибка в записи, чтение можно продолжать;
+	// ошибка ErrMalformed или ошибка чтения потока означает, что дальше поток не читается.
+	Next() (models.Song, int, error)
+}
+
+// NewReader создаёт Reader для формата models.ImportCSV, models.ImportNDJSON или models.ImportJSON
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case models.ImportCSV:
+		return newCSVReader(r)
+	case models.ImportNDJSON:
+		return &ndjsonReader{r: bufio.NewReader(r)}, nil
+	case models.ImportJSON:
+		return &jsonReader{dec: json.NewDecoder(r)}, nil
+	default:
+		return nil, apperror.NewValidationError("format", fmt.Sprintf("unsupported import format %q, expected csv, ndjson or json", format))
+	}
+}
+
+// csvColumns — колонки CSV, совпадающие с полями песни в JSON; регистр в заголовке не важен
+var csvColumns = map[string]func(song *models.Song, value string) error{
+	"group": func(song *models.Song, value string) error { song.GroupName = value; return nil },
+	"song":  func(song *models.Song, value string) error { song.SongName = value; return nil },
+	"releasedate": func(song *models.Song, value string) error {
+		date, err := models.ParseReleaseDate(value)
+		if err != nil {
+			return err
+		}
+		song.ReleaseDate = date
+		return nil
+	},
+	"text":   func(song *models.Song, value string) error { song.Text = value; return nil },
+	"lyrics": func(song *models.Song, value string) error { song.Lyrics = value; return nil },
+	"link":   func(song *models.Song, value string) error { song.Link = value; return nil },
+	"duration": func(song *models.Song, value string) error {
+		if value == "" {
+			return nil
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("must be an integer number of seconds")
+		}
+		song.Duration = seconds
+		return nil
+	},
+}
+
+// csvFieldNames — имена полей для отчёта об ошибках
+var csvFieldNames = map[string]string{"releasedate": "releaseDate"}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns []string
+}
+
+// newCSVReader читает заголовок: он сопоставляет колонки с полями песни, неизвестные колонки пропускаются
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, apperror.NewValidationError("header", "CSV file is empty")
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // BOM, который добавляют табличные редакторы
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := csvColumns[name]; !ok {
+			continue
+		}
+		if seen[name] {
+			return nil, apperror.NewValidationError("header", fmt.Sprintf("duplicate column %q", header[i]))
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	if !seen["group"] || !seen["song"] {
+		return nil, apperror.NewValidationError("header", "CSV header must contain group and song columns")
+	}
+
+	return &csvReader{r: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (models.Song, int, error) {
+	record, err := r.r.Read()
+	if err == io.EOF {
+		return models.Song{}, 0, io.EOF
+	}
+	if err != nil {
+		// После ошибки разбора позиций полей нет: номер строки берём из самой ошибки
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return models.Song{}, 0, err
+		}
+		// Строка с другим числом колонок портит только её саму
+		if errors.Is(err, csv.ErrFieldCount) {
+			return models.Song{}, parseErr.StartLine, apperror.NewValidationError("row", fmt.Sprintf("expected %d columns, got %d", len(r.columns), len(record)))
+		}
+		return models.Song{}, parseErr.StartLine, csvError(err)
+	}
+	line, _ := r.r.FieldPos(0)
+
+	var song models.Song
+	validationErr := &apperror.ValidationError{}
+	for i, value := range record {
+		if r.columns[i] == "" {
+			continue
+		}
+		if err := csvColumns[r.columns[i]](&song, value); err != nil {
+			name := r.columns[i]
+			if field, ok := csvFieldNames[name]; ok {
+				name = field
+			}
+			validationErr.Add(name, err.Error())
+		}
+	}
+	if len(validationErr.Fields) > 0 {
+		return models.Song{}, line, validationErr
+	}
+	return song, line, nil
+}
+
+func csvError(err error) error {
+	return fmt.Errorf("%w: %v", ErrMalformed, err)
+}
+
+type ndjsonReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (r *ndjsonReader) Next() (models.Song, int, error) {
+	for {
+		data, err := r.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return models.Song{}, r.line + 1, err
+		}
+		if len(data) == 0 && err == io.EOF {
+			return models.Song{}, 0, io.EOF
+		}
+		r.line++
+
+		// Пустые строки между записями пропускаем
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			if err == io.EOF {
+				return models.Song{}, 0, io.EOF
+			}
+			continue
+		}
+
+		if !json.Valid(data) {
+			return models.Song{}, r.line, apperror.NewValidationError("row", "malformed JSON")
+		}
+		song, decodeErr := decodeSong(data)
+		return song, r.line, decodeErr
+	}
+}
+
+type jsonReader struct {
+	dec     *json.Decoder
+	index   int
+	started bool
+}
+
+func (r *jsonReader) Next() (models.Song, int, error) {
+	if !r.started {
+		token, err := r.dec.Token()
+		if err == io.EOF {
+			return models.Song{}, 0, fmt.Errorf("%w: expected a JSON array", ErrMalformed)
+		}
+		if err != nil {
+			return models.Song{}, 0, jsonError(err)
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return models.Song{}, 0, fmt.Errorf("%w: expected a JSON array", ErrMalformed)
+		}
+		r.started = true
+	}
+
+	if !r.dec.More() {
+		if _, err := r.dec.Token(); err != nil {
+			return models.Song{}, r.index + 1, jsonError(err)
+		}
+		return models.Song{}, 0, io.EOF
+	}
+
+	r.index++
+	var raw json.RawMessage
+	if err := r.dec.Decode(&raw); err != nil {
+		return models.Song{}, r.index, jsonError(err)
+	}
+	song, err := decodeSong(raw)
+	return song, r.index, err
+}
+
+// jsonError отделяет синтаксические ошибки JSON-массива (дальше читать нельзя) от ошибок чтения потока
+func jsonError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return err
+}
+
+// decodeSong разбирает песню из JSON-объекта; ошибки полей возвращаются как ValidationError
+func decodeSong(data []byte) (models.Song, error) {
+	var song models.Song
+	err := json.Unmarshal(data, &song)
+	if err == nil {
+		return song, nil
+	}
+
+	var validationErr *apperror.ValidationError
+	if errors.As(err, &validationErr) {
+		return models.Song{}, validationErr
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return models.Song{}, apperror.NewValidationError(typeErr.Field, fmt.Sprintf("must be of type %s", typeErr.Type))
+	}
+	return models.Song{}, apperror.NewValidationError("row", "must be a JSON object with song fields")
+}
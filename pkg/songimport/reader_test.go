@@ -0,0 +1,168 @@
+package songimport
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/skorpsrgvch/music-lib/models"
+	"github.com/skorpsrgvch/music-lib/pkg/apperror"
+)
+
+// record — итог одного вызова Next: песня или поля с ошибками, либо "malformed"
+type record struct {
+	Row      int
+	Group    string
+	Song     string
+	Duration int
+	Invalid  []string
+	Fatal    string
+}
+
+// readAll читает записи до io.EOF или до ошибки, после которой поток не читается
+func readAll(t *testing.T, format, input string) []record {
+	t.Helper()
+	reader, err := NewReader(format, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	records := make([]record, 0)
+	for {
+		song, row, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+
+		rec := record{Row: row, Group: song.GroupName, Song: song.SongName, Duration: song.Duration}
+		var validationErr *apperror.ValidationError
+		switch {
+		case err == nil:
+		case errors.As(err, &validationErr):
+			for field := range validationErr.Fields {
+				rec.Invalid = append(rec.Invalid, field)
+			}
+			sort.Strings(rec.Invalid)
+		case errors.Is(err, ErrMalformed):
+			rec.Fatal = "malformed"
+		default:
+			t.Fatalf("Next() unexpected error = %v", err)
+		}
+		records = append(records, rec)
+		if rec.Fatal != "" {
+			return records
+		}
+	}
+}
+
+func TestReaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   []record
+	}{
+		{
+			name:   "csv",
+			format: models.ImportCSV,
+			input:  "\ufeffGroup, Song ,releaseDate,duration,comment\nMuse,Uprising,2009,305,x\n\"Queen\",\"Bohemian Rhapsody, live\",,,\n",
+			want: []record{
+				{Row: 2, Group: "Muse", Song: "Uprising", Duration: 305},
+				{Row: 3, Group: "Queen", Song: "Bohemian Rhapsody, live"},
+			},
+		},
+		{
+			name:   "csv invalid fields and column count",
+			format: models.ImportCSV,
+			input:  "group,song,releaseDate,duration\nMuse,Uprising,32.13.2009,long\nMuse,Uprising\nMuse,Madness,2012,281\n",
+			want: []record{
+				{Row: 2, Invalid: []string{"duration", "releaseDate"}},
+				{Row: 3, Invalid: []string{"row"}},
+				{Row: 4, Group: "Muse", Song: "Madness", Duration: 281},
+			},
+		},
+		{
+			name:   "csv unterminated quote",
+			format: models.ImportCSV,
+			input:  "group,song\nMuse,Uprising\n\"abc,d\n",
+			want: []record{
+				{Row: 2, Group: "Muse", Song: "Uprising"},
+				{Row: 3, Fatal: "malformed"},
+			},
+		},
+		{
+			name:   "csv bare quote",
+			format: models.ImportCSV,
+			input:  "group,song\na\"b,c\n",
+			want:   []record{{Row: 2, Fatal: "malformed"}},
+		},
+		{
+			name:   "ndjson",
+			format: models.ImportNDJSON,
+			input:  "{\"group\":\"Muse\",\"song\":\"Uprising\"}\n\n{\"group\":1}\n{broken\n{\"group\":\"Queen\",\"song\":\"Innuendo\",\"duration\":391}",
+			want: []record{
+				{Row: 1, Group: "Muse", Song: "Uprising"},
+				{Row: 3, Invalid: []string{"group"}},
+				{Row: 4, Invalid: []string{"row"}},
+				{Row: 5, Group: "Queen", Song: "Innuendo", Duration: 391},
+			},
+		},
+		{
+			name:   "json array",
+			format: models.ImportJSON,
+			input:  `[{"group":"Muse","song":"Uprising"}, "text", {"group":"Queen","song":"Innuendo"}]`,
+			want: []record{
+				{Row: 1, Group: "Muse", Song: "Uprising"},
+				{Row: 2, Invalid: []string{"row"}},
+				{Row: 3, Group: "Queen", Song: "Innuendo"},
+			},
+		},
+		{
+			name:   "json array cut off",
+			format: models.ImportJSON,
+			input:  `[{"group":"Muse","song":"Uprising"}, {"group":`,
+			want: []record{
+				{Row: 1, Group: "Muse", Song: "Uprising"},
+				{Row: 2, Fatal: "malformed"},
+			},
+		},
+		{
+			name:   "json object instead of array",
+			format: models.ImportJSON,
+			input:  `{"group":"Muse"}`,
+			want:   []record{{Fatal: "malformed"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readAll(t, tt.format, tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	tests := []struct {
+		name, format, input, field string
+	}{
+		{"unknown format", "xml", "", "format"},
+		{"empty csv", models.ImportCSV, "", "header"},
+		{"missing song column", models.ImportCSV, "group,title\n", "header"},
+		{"duplicate column", models.ImportCSV, "group,song,Song\n", "header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(tt.format, strings.NewReader(tt.input))
+			var validationErr *apperror.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Fields[tt.field] == "" {
+				t.Errorf("NewReader() error = %v, want a validation error on %q", err, tt.field)
+			}
+		})
+	}
+}